@@ -1,10 +1,19 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -15,13 +24,28 @@ import (
 	"github.com/newrelic/go-agent/v3/integrations/nrredis-v8"
 	"github.com/newrelic/go-agent/v3/newrelic"
 
+	"github.com/rssh-jp/test-api/api/domain"
 	"github.com/rssh-jp/test-api/api/gen"
+	"github.com/rssh-jp/test-api/api/infrastructure/auth"
+	"github.com/rssh-jp/test-api/api/infrastructure/cache"
+	"github.com/rssh-jp/test-api/api/infrastructure/imaging"
+	"github.com/rssh-jp/test-api/api/infrastructure/mail"
+	"github.com/rssh-jp/test-api/api/infrastructure/storage"
 	redisCache "github.com/rssh-jp/test-api/api/infrastructure/cache/redis"
 	mysqlRepo "github.com/rssh-jp/test-api/api/infrastructure/persistence/mysql"
 	"github.com/rssh-jp/test-api/api/interfaces/handler"
 	"github.com/rssh-jp/test-api/api/usecase"
 )
 
+const (
+	// staticPathPrefix はローカル保存したファイルを配信するパス
+	staticPathPrefix = "/static"
+	// staticCacheControl は保存ファイルのキャッシュ指定（キーにバージョンを含むため変更されない）
+	staticCacheControl = "public, max-age=31536000, immutable"
+	// shutdownTimeout は停止時に処理中のリクエストを待つ時間
+	shutdownTimeout = 10 * time.Second
+)
+
 func main() {
 	// Load environment variables
 	dbUser := getEnv("DB_USER", "root")
@@ -39,9 +63,80 @@ func main() {
 
 	port := getEnv("PORT", "8080")
 
+	// タイムライン方式: read（MySQLでfan-out-on-read）/ write（Redisでfan-out-on-write）
+	feedMode := getEnv("FEED_MODE", domain.FeedModeFanoutOnRead)
+	if feedMode != domain.FeedModeFanoutOnRead && feedMode != domain.FeedModeFanoutOnWrite {
+		log.Fatalf("Invalid FEED_MODE: %s (expected %q or %q)", feedMode, domain.FeedModeFanoutOnRead, domain.FeedModeFanoutOnWrite)
+	}
+
+	// APP_ENV=development の場合だけ、未設定の秘密鍵に開発用の固定値を使う（誰でも知っている鍵のまま本番で起動しないため）
+	devMode := getEnv("APP_ENV", "") == "development"
+
+	// JWT署名鍵とトークン有効期限
+	jwtSecret := getEnv("JWT_SECRET", "")
+	if jwtSecret == "" {
+		if !devMode {
+			log.Fatal("JWT_SECRET is required (set APP_ENV=development to use an insecure development secret)")
+		}
+		jwtSecret = "dev-insecure-jwt-secret"
+		log.Println("⚠ JWT_SECRET is not set, using an insecure development secret")
+	}
+	accessTokenTTL := getDurationEnv("ACCESS_TOKEN_TTL", 15*time.Minute)
+	refreshTokenTTL := getDurationEnv("REFRESH_TOKEN_TTL", 7*24*time.Hour)
+
+	// メール送信: file（MAIL_DIRに.emlを書き出す）/ smtp
+	mailDriver := getEnv("MAIL_DRIVER", "file")
+	mailFrom := getEnv("MAIL_FROM", "no-reply@example.com")
+	appBaseURL := getEnv("APP_BASE_URL", "http://localhost:8080")
+	emailVerificationTTL := getDurationEnv("EMAIL_VERIFICATION_TTL", 24*time.Hour)
+	passwordResetTTL := getDurationEnv("PASSWORD_RESET_TTL", time.Hour)
+	// パスワード再設定メールのリンク先（フロントエンドの入力画面。?token=を付けて送る）
+	passwordResetURL := getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password")
+	// 認証アプリに表示されるサービス名
+	totpIssuer := getEnv("TOTP_ISSUER", "test-api")
+	// TOTPシークレットの暗号化鍵（32バイトをBase64で指定。AES-256-GCM）
+	twoFactorKey, err := getEncryptionKeyEnv("TWO_FACTOR_ENCRYPTION_KEY", devMode)
+	if err != nil {
+		log.Fatalf("Invalid TWO_FACTOR_ENCRYPTION_KEY: %v", err)
+	}
+	secretCipher, err := auth.NewAESGCMCipher(twoFactorKey)
+	if err != nil {
+		log.Fatalf("Invalid TWO_FACTOR_ENCRYPTION_KEY: %v", err)
+	}
+	// false の場合は未認証アカウントでも投稿・コメントできる
+	requireVerifiedEmail := getEnv("REQUIRE_VERIFIED_EMAIL", "true") == "true"
+
+	// 閲覧数: Redisに溜めてVIEW_FLUSH_INTERVALごとにMySQLへ反映。
+	// VIEW_DEDUP_WINDOW > 0 の場合は同じ閲覧者の同じ投稿への閲覧をウィンドウ内で1回だけ数える
+	viewFlushInterval := getDurationEnv("VIEW_FLUSH_INTERVAL", 10*time.Second)
+	viewDedupWindow := getDurationEnv("VIEW_DEDUP_WINDOW", 0)
+
+	// 投稿・ユーザー・ユーザー詳細のキャッシュ: CACHE_SOFT_TTLを過ぎた値は返しつつ裏で再取得し、CACHE_HARD_TTLで破棄する。
+	// CACHE_LOCK_TTL > 0 の場合はキャッシュミス時の読み込みをRedisのロックでインスタンス間でも1つに絞る
+	readCacheConfig := redisCache.DefaultReadCacheConfig()
+	readCacheConfig.SoftTTL = getDurationEnv("CACHE_SOFT_TTL", readCacheConfig.SoftTTL)
+	readCacheConfig.HardTTL = getDurationEnv("CACHE_HARD_TTL", readCacheConfig.HardTTL)
+	readCacheConfig.LockTTL = getDurationEnv("CACHE_LOCK_TTL", readCacheConfig.LockTTL)
+
+	// L1: Redisの前にプロセス内のLRU（最大CACHE_L1_SIZE件、CACHE_L1_TTLまで）を置く。0で無効。
+	// 無効化はRedisのpub/subで全インスタンスに通知し、通知が届かなかった場合もCACHE_L1_TTLで反映される
+	if l1Size := getIntEnv("CACHE_L1_SIZE", 1000); l1Size > 0 {
+		readCacheConfig.L1 = cache.NewLRU(l1Size, getDurationEnv("CACHE_L1_TTL", 10*time.Second))
+	}
+
+	// クライアントIP（レート制限・閲覧数の重複判定に使う）: TRUSTED_PROXIES（CIDRのカンマ区切り）からの接続に限り
+	// X-Forwarded-Forを信頼する。未設定の場合は転送ヘッダーを無視して接続元のIPを使う
+	ipExtractor, err := handler.NewIPExtractor(getEnv("TRUSTED_PROXIES", ""))
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	// Blob storage configuration (avatars): "local" serves files from /static, "s3" uses an S3-compatible API
+	blobDriver := getEnv("BLOB_DRIVER", "local")
+	blobDir := getEnv("BLOB_DIR", "/tmp/blobs")
+
 	// Initialize New Relic
 	var nrApp *newrelic.Application
-	var err error
 	if newrelicLicense != "" {
 		nrApp, err = newrelic.NewApplication(
 			newrelic.ConfigAppName(newrelicAppName),
@@ -120,43 +215,251 @@ func main() {
 	}
 	log.Println("Connected to Redis successfully")
 
+	if readCacheConfig.L1 != nil {
+		go redisCache.SubscribeL1Invalidations(context.Background(), redisClient, readCacheConfig.L1)
+	}
+
+	// Initialize mailer
+	var mailer domain.Mailer
+	switch mailDriver {
+	case "smtp":
+		mailer = mail.NewSMTPMailer(mail.SMTPConfig{
+			Host:     getEnv("SMTP_HOST", "localhost"),
+			Port:     getEnv("SMTP_PORT", "587"),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     mailFrom,
+		})
+	case "file":
+		mailer = mail.NewFileMailer(getEnv("MAIL_DIR", "/tmp/mail"), mailFrom)
+	default:
+		log.Fatalf("Invalid MAIL_DRIVER: %s (expected \"file\" or \"smtp\")", mailDriver)
+	}
+	log.Printf("Mail driver: %s", mailDriver)
+
+	// Initialize authorization (roles/permissions + ownership checks in usecases)
+	roleRepo := redisCache.NewCachedRoleRepository(mysqlRepo.NewRoleRepository(db), redisClient, readCacheConfig)
+	emailVerificationRepo := redisCache.NewCachedEmailVerificationRepository(mysqlRepo.NewEmailVerificationRepository(db), redisClient)
+	var policyVerificationRepo domain.EmailVerificationRepository
+	if requireVerifiedEmail {
+		policyVerificationRepo = emailVerificationRepo
+	}
+	policy := usecase.NewPolicy(roleRepo, policyVerificationRepo)
+
 	// Initialize repositories and services
 	baseUserRepo := mysqlRepo.NewUserRepository(db)
-	cachedUserRepo := redisCache.NewCachedUserRepository(baseUserRepo, redisClient)
+	cachedUserRepo := redisCache.NewCachedUserRepository(baseUserRepo, redisClient, readCacheConfig)
 	// ユーザーハンドラーにキャッシュ層とDB直接アクセス層の両方を渡す
-	userUsecase := usecase.NewUserUsecase(cachedUserRepo)
-	directUserUsecase := usecase.NewUserUsecase(baseUserRepo)
+	userUsecase := usecase.NewUserUsecase(cachedUserRepo, policy)
+	directUserUsecase := usecase.NewUserUsecase(baseUserRepo, policy)
 	
 	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
 	userHandlerV2 := handler.NewUserHandlerV2(userUsecase, directUserUsecase)
 	userHandler := handler.NewUserHandlerBridge(userHandlerV2)
 
+	// Initialize email verification (single-use expiring tokens sent by mail)
+	emailVerificationUsecase := usecase.NewEmailVerificationUsecase(emailVerificationRepo, baseUserRepo, mailer, appBaseURL, emailVerificationTTL)
+
+	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
+	emailVerificationHandlerV2 := handler.NewEmailVerificationHandlerV2(emailVerificationUsecase)
+	emailVerificationHandler := handler.NewEmailVerificationHandlerBridge(emailVerificationHandlerV2)
+
+	// Initialize two-factor authentication (TOTP + hashed recovery codes)
+	rateLimiter := redisCache.NewRateLimiter(redisClient)
+	twoFactorRepo := redisCache.NewCachedTwoFactorRepository(mysqlRepo.NewTwoFactorRepository(db), redisClient)
+	twoFactorUsecase := usecase.NewTwoFactorUsecase(twoFactorRepo, baseUserRepo, auth.NewTOTP(totpIssuer), secretCipher, rateLimiter)
+
+	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
+	twoFactorHandlerV2 := handler.NewTwoFactorHandlerV2(twoFactorUsecase)
+	twoFactorHandler := handler.NewTwoFactorHandlerBridge(twoFactorHandlerV2)
+
+	// Initialize auth services (bcrypt passwords + JWT access/refresh tokens)
+	authRepo := redisCache.NewCachedAuthRepository(mysqlRepo.NewAuthRepository(db), redisClient)
+	passwordHasher := auth.NewBcryptHasher(0)
+	refreshTokenStore := redisCache.NewRefreshTokenStore(redisClient)
+	authUsecase := usecase.NewAuthUsecase(
+		authRepo,
+		passwordHasher,
+		auth.NewJWTTokenIssuer(jwtSecret, accessTokenTTL, refreshTokenTTL),
+		refreshTokenStore,
+		emailVerificationUsecase,
+		twoFactorUsecase,
+		cachedUserRepo,
+	)
+
+	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
+	authHandlerV2 := handler.NewAuthHandlerV2(authUsecase)
+	authHandler := handler.NewAuthHandlerBridge(authHandlerV2)
+
+	// Initialize password reset (single-use expiring tokens, rate limited per email/IP in Redis)
+	passwordResetUsecase := usecase.NewPasswordResetUsecase(
+		mysqlRepo.NewPasswordResetRepository(db),
+		authRepo,
+		passwordHasher,
+		refreshTokenStore,
+		mailer,
+		rateLimiter,
+		passwordResetURL,
+		passwordResetTTL,
+	)
+
+	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
+	passwordResetHandlerV2 := handler.NewPasswordResetHandlerV2(passwordResetUsecase)
+	passwordResetHandler := handler.NewPasswordResetHandlerBridge(passwordResetHandlerV2)
+
+	// Initialize account status lifecycle (deactivate / suspend / reactivate / restore with reasons)
+	userStatusRepo := redisCache.NewCachedUserStatusRepository(mysqlRepo.NewUserStatusRepository(db), redisClient)
+	accountStatusUsecase := usecase.NewAccountStatusUsecase(userStatusRepo, refreshTokenStore, policy)
+
+	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
+	accountStatusHandlerV2 := handler.NewAccountStatusHandlerV2(accountStatusUsecase)
+	accountStatusHandler := handler.NewAccountStatusHandlerBridge(accountStatusHandlerV2)
+
 	// Initialize post-related services (complex JOIN queries with Redis cache)
 	basePostRepo := mysqlRepo.NewPostRepository(db)
-	cachedPostRepo := redisCache.NewCachedPostRepository(basePostRepo, redisClient)
+	cachedPostRepo := redisCache.NewCachedPostRepository(basePostRepo, redisClient, readCacheConfig)
+	baseFollowRepo := mysqlRepo.NewFollowRepository(db)
+	// fan-out-on-write の場合は公開・非公開の変更をフォロワーのタイムラインへ配信
+	postWriteRepo := cachedPostRepo
+	if feedMode == domain.FeedModeFanoutOnWrite {
+		postWriteRepo = redisCache.NewFanoutPostRepository(cachedPostRepo, baseFollowRepo, redisClient)
+	}
+	baseLikeRepo := mysqlRepo.NewLikeRepository(db)
+	// 閲覧数の反映はキャッシュ層経由で行い、反映した投稿のキャッシュを無効化する
+	viewCounter := redisCache.NewRedisViewCounter(redisClient, cachedPostRepo, viewDedupWindow)
+	flusherCtx, stopFlusher := context.WithCancel(context.Background())
+	flusherDone := make(chan struct{})
+	go func() {
+		defer close(flusherDone)
+		usecase.NewViewCountFlusher(viewCounter, viewFlushInterval).Run(flusherCtx)
+	}()
 	// 投稿ハンドラーにキャッシュ層とDB直接アクセス層の両方を渡す
-	postUsecase := usecase.NewPostUsecase(cachedPostRepo)
-	directPostUsecase := usecase.NewPostUsecase(basePostRepo)
+	taxonomyRepo := mysqlRepo.NewTaxonomyRepository(db)
+	postUsecase := usecase.NewPostUsecase(postWriteRepo, taxonomyRepo, baseLikeRepo, viewCounter, policy)
+	directPostUsecase := usecase.NewPostUsecase(basePostRepo, taxonomyRepo, baseLikeRepo, viewCounter, policy)
 	
 	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
 	postHandlerV2 := handler.NewPostHandlerV2(postUsecase, directPostUsecase)
 	postHandler := handler.NewPostHandlerBridge(postHandlerV2)
 
-	// Initialize user detail service (complex JOIN queries for all user-related data)
-	userDetailRepo := mysqlRepo.NewUserDetailRepository(db)
+	// Initialize profile editing (upserts user_profiles, invalidates author names in post caches)
+	userProfileRepo := redisCache.NewCachedUserProfileRepository(mysqlRepo.NewUserProfileRepository(db), redisClient)
+	userProfileUsecase := usecase.NewUserProfileUsecase(userProfileRepo, policy)
+
+	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
+	userProfileHandlerV2 := handler.NewUserProfileHandlerV2(userProfileUsecase)
+	userProfileHandler := handler.NewUserProfileHandlerBridge(userProfileHandlerV2)
+
+	// Initialize blob storage and avatar uploads (thumbnails are stored under versioned keys)
+	var blobStore domain.BlobStore
+	switch blobDriver {
+	case "local":
+		blobStore = storage.NewLocalBlobStore(blobDir, strings.TrimRight(appBaseURL, "/")+staticPathPrefix)
+	case "s3":
+		blobStore, err = storage.NewS3BlobStore(storage.S3Config{
+			Endpoint:        getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+			Region:          getEnv("S3_REGION", "us-east-1"),
+			Bucket:          getEnv("S3_BUCKET", ""),
+			AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+			UsePathStyle:    getEnv("S3_USE_PATH_STYLE", "false") == "true",
+			PublicBaseURL:   getEnv("S3_PUBLIC_BASE_URL", ""),
+			CacheControl:    staticCacheControl,
+		})
+		if err != nil {
+			log.Fatalf("Failed to configure S3 blob store: %v", err)
+		}
+	default:
+		log.Fatalf("Invalid BLOB_DRIVER: %s (expected \"local\" or \"s3\")", blobDriver)
+	}
+	log.Printf("Blob driver: %s", blobDriver)
+	avatarUsecase := usecase.NewAvatarUsecase(userProfileRepo, blobStore, imaging.NewThumbnailer(), policy)
+
+	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
+	avatarHandlerV2 := handler.NewAvatarHandlerV2(avatarUsecase)
+	avatarHandler := handler.NewAvatarHandlerBridge(avatarHandlerV2)
+
+	// Initialize user detail service (complex JOIN queries for all user-related data with Redis cache)
+	userDetailRepo := redisCache.NewCachedUserDetailRepository(mysqlRepo.NewUserDetailRepository(db), redisClient, readCacheConfig)
 	userDetailUsecase := usecase.NewUserDetailUsecase(userDetailRepo)
 	
 	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
 	userDetailHandlerV2 := handler.NewUserDetailHandlerV2(userDetailUsecase)
 	userDetailHandler := handler.NewUserDetailHandlerBridge(userDetailHandlerV2)
 
+	// Initialize notification services (follow/like/comment/mention notifications + inbox)
+	baseCommentRepo := mysqlRepo.NewCommentRepository(db)
+	baseNotificationRepo := mysqlRepo.NewNotificationRepository(db)
+	cachedNotificationRepo := redisCache.NewCachedNotificationRepository(baseNotificationRepo, redisClient)
+	// Redis pub/subで全インスタンスのSSE接続へ配信
+	notificationBroker := redisCache.NewNotificationBroker(redisClient)
+	notifier := usecase.NewNotifier(cachedNotificationRepo, baseUserRepo, basePostRepo, baseCommentRepo, notificationBroker)
+	notificationUsecase := usecase.NewNotificationUsecase(cachedNotificationRepo, baseUserRepo, notificationBroker, policy)
+
+	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
+	notificationHandlerV2 := handler.NewNotificationHandlerV2(notificationUsecase)
+	notificationHandler := handler.NewNotificationHandlerBridge(notificationHandlerV2)
+
+	// Initialize comment services (threaded comments + moderation)
+	cachedCommentRepo := redisCache.NewCachedCommentRepository(baseCommentRepo, basePostRepo, redisClient)
+	commentUsecase := usecase.NewCommentUsecase(cachedCommentRepo, basePostRepo, notifier, policy)
+
+	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
+	commentHandlerV2 := handler.NewCommentHandlerV2(commentUsecase)
+	commentHandler := handler.NewCommentHandlerBridge(commentHandlerV2)
+
+	// Initialize like services (idempotent likes + counters in the same transaction)
+	cachedLikeRepo := redisCache.NewCachedLikeRepository(baseLikeRepo, basePostRepo, baseCommentRepo, redisClient)
+	likeUsecase := usecase.NewLikeUsecase(cachedLikeRepo, basePostRepo, baseCommentRepo, baseUserRepo, notifier, policy)
+
+	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
+	likeHandlerV2 := handler.NewLikeHandlerV2(likeUsecase)
+	likeHandler := handler.NewLikeHandlerBridge(likeHandlerV2)
+
+	// Initialize follow services (follow graph + cursor pagination)
+	cachedFollowRepo := redisCache.NewCachedFollowRepository(baseFollowRepo, redisClient)
+	followUsecase := usecase.NewFollowUsecase(cachedFollowRepo, baseUserRepo, notifier, policy)
+
+	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
+	followHandlerV2 := handler.NewFollowHandlerV2(followUsecase)
+	followHandler := handler.NewFollowHandlerBridge(followHandlerV2)
+
+	// Initialize role management (grant/revoke roles, roles.manage only)
+	roleUsecase := usecase.NewRoleUsecase(roleRepo, baseUserRepo, policy)
+
+	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
+	roleHandlerV2 := handler.NewRoleHandlerV2(roleUsecase)
+	roleHandler := handler.NewRoleHandlerBridge(roleHandlerV2)
+
+	// Initialize API keys for machine clients (lookups by prefix cached in Redis)
+	apiKeyRepo := redisCache.NewCachedAPIKeyRepository(mysqlRepo.NewAPIKeyRepository(db), redisClient, readCacheConfig)
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(apiKeyRepo, cachedUserRepo, policy)
+
+	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
+	apiKeyHandlerV2 := handler.NewAPIKeyHandlerV2(apiKeyUsecase)
+	apiKeyHandler := handler.NewAPIKeyHandlerBridge(apiKeyHandlerV2)
+
+	// Initialize home timeline (FEED_MODE: read=MySQL fan-out-on-read, write=Redis fan-out-on-write)
+	feedRepo := mysqlRepo.NewFeedRepository(db)
+	if feedMode == domain.FeedModeFanoutOnWrite {
+		feedRepo = redisCache.NewRedisFeedRepository(feedRepo, cachedPostRepo, redisClient)
+	}
+	log.Printf("Home timeline mode: fan-out-on-%s", feedMode)
+	feedUsecase := usecase.NewFeedUsecase(feedRepo, baseUserRepo, baseLikeRepo, viewCounter)
+
+	// V2: フレームワーク非依存ハンドラーを作成し、ブリッジ経由でEchoに接続
+	feedHandlerV2 := handler.NewFeedHandlerV2(feedUsecase)
+	feedHandler := handler.NewFeedHandlerBridge(feedHandlerV2)
+
 	// Initialize Echo
 	e := echo.New()
+	e.IPExtractor = ipExtractor
 
 	// Middleware
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORS())
+	e.Use(handler.AuthMiddleware(authUsecase, apiKeyUsecase))
 
 	// New Relic middleware
 	if nrApp != nil {
@@ -166,23 +469,135 @@ func main() {
 	// Register routes using OpenAPI generated code
 	gen.RegisterHandlers(e, userHandler)
 
+	// Register auth routes (register/login + refresh token rotation)
+	e.POST("/auth/register", authHandler.Register)
+	e.POST("/auth/login", authHandler.Login)
+	e.POST("/auth/refresh", authHandler.Refresh)
+	e.POST("/auth/logout", authHandler.Logout)
+	e.POST("/auth/login/2fa", authHandler.LoginTwoFactor)
+	e.GET("/auth/me", authHandler.Me, handler.RequireAuth())
+
+	// Register two-factor authentication routes (for the authenticated user)
+	e.POST("/auth/2fa/enroll", twoFactorHandler.Enroll, handler.RequireAuth())
+	e.POST("/auth/2fa/enable", twoFactorHandler.Enable, handler.RequireAuth())
+	e.POST("/auth/2fa/disable", twoFactorHandler.Disable, handler.RequireAuth())
+
+	// Register email verification routes (confirm link + resend)
+	e.POST("/auth/email/verification", emailVerificationHandler.SendVerification, handler.RequireAuth())
+	e.GET("/auth/email/verify", emailVerificationHandler.ConfirmEmail)
+	e.POST("/auth/email/verify", emailVerificationHandler.ConfirmEmail)
+
+	// Register password reset routes
+	e.POST("/auth/password/forgot", passwordResetHandler.ForgotPassword)
+	e.POST("/auth/password/reset", passwordResetHandler.ResetPassword)
+
 	// Register post routes (complex JOIN queries)
 	e.GET("/posts", postHandler.GetPosts)
 	e.GET("/posts/featured", postHandler.GetFeaturedPosts)
+	e.GET("/posts/search", postHandler.SearchPosts)
 	e.GET("/posts/:id", postHandler.GetPostByID)
 	e.GET("/posts/slug/:slug", postHandler.GetPostBySlug)
 	e.GET("/posts/category/:slug", postHandler.GetPostsByCategory)
 	e.GET("/posts/tag/:slug", postHandler.GetPostsByTag)
 
+	// Register post write routes (draft → published → archived → deleted)
+	e.POST("/posts", postHandler.CreatePost)
+	e.PUT("/posts/:id", postHandler.UpdatePost)
+	e.PATCH("/posts/:id/publish", postHandler.PublishPost)
+	e.PATCH("/posts/:id/archive", postHandler.ArchivePost)
+	e.DELETE("/posts/:id", postHandler.DeletePost)
+
+	// Register comment routes (threaded comments + moderation)
+	e.GET("/posts/:id/comments", commentHandler.GetComments)
+	e.POST("/posts/:id/comments", commentHandler.PostComment)
+	e.GET("/comments/moderation", commentHandler.GetModerationQueue)
+	e.PATCH("/comments/:id/approve", commentHandler.ApproveComment)
+	e.PATCH("/comments/:id/reject", commentHandler.RejectComment)
+	e.PATCH("/comments/:id/spam", commentHandler.MarkCommentAsSpam)
+
+	// Register like routes (idempotent like/unlike for posts and comments)
+	e.PUT("/posts/:id/likes/:userId", likeHandler.LikePost)
+	e.DELETE("/posts/:id/likes/:userId", likeHandler.UnlikePost)
+	e.GET("/posts/:id/likes", likeHandler.GetPostLikers)
+	e.PUT("/comments/:id/likes/:userId", likeHandler.LikeComment)
+	e.DELETE("/comments/:id/likes/:userId", likeHandler.UnlikeComment)
+	e.GET("/comments/:id/likes", likeHandler.GetCommentLikers)
+
 	// Register user detail routes (complex JOIN queries for all user-related data)
 	e.GET("/users/:id/detail", userDetailHandler.GetUserDetailByID)
 	e.GET("/users/username/:username/detail", userDetailHandler.GetUserDetailByUsername)
 
+	// Register profile routes (PUT replaces, PATCH updates only the given fields)
+	e.GET("/users/:id/profile", userProfileHandler.GetProfile)
+	e.PUT("/users/:id/profile", userProfileHandler.ReplaceProfile, handler.RequireAuth())
+	e.PATCH("/users/:id/profile", userProfileHandler.UpdateProfile, handler.RequireAuth())
+
+	// Register avatar routes (multipart upload, thumbnails in 64/128/256px)
+	e.PUT("/users/:id/avatar", avatarHandler.UploadAvatar, handler.RequireAuth())
+	e.DELETE("/users/:id/avatar", avatarHandler.DeleteAvatar, handler.RequireAuth())
+
+	// Serve locally stored blobs (keys are versioned, so they can be cached indefinitely)
+	if blobDriver == "local" {
+		e.Group(staticPathPrefix, handler.CacheControl(staticCacheControl)).Static("/", blobDir)
+	}
+
+	// Register follow routes (follow/unfollow + cursor-paginated lists)
+	e.POST("/users/:id/following", followHandler.Follow)
+	e.DELETE("/users/:id/following/:targetId", followHandler.Unfollow)
+	e.GET("/users/:id/following/:targetId", followHandler.IsFollowing)
+	e.GET("/users/:id/following", followHandler.GetFollowing)
+	e.GET("/users/:id/followers", followHandler.GetFollowers)
+
+	// Register account status routes (soft delete is DELETE /users/:id)
+	e.POST("/users/:id/deactivate", accountStatusHandler.DeactivateUser, handler.RequireAuth())
+	e.POST("/users/:id/suspend", accountStatusHandler.SuspendUser, handler.RequireAuth())
+	e.POST("/users/:id/reactivate", accountStatusHandler.ReactivateUser, handler.RequireAuth())
+	e.POST("/users/:id/restore", accountStatusHandler.RestoreUser, handler.RequireAuth())
+	e.GET("/users/:id/status-history", accountStatusHandler.GetStatusHistory, handler.RequireAuth())
+
+	// Register role routes (RBAC: moderator / admin)
+	e.GET("/users/:id/roles", roleHandler.GetUserRoles)
+	e.PUT("/users/:id/roles/:role", roleHandler.AssignRole)
+	e.DELETE("/users/:id/roles/:role", roleHandler.RevokeRole)
+
+	// Register API key routes (the user themselves or users.manage)
+	e.GET("/users/:id/api-keys", apiKeyHandler.ListAPIKeys, handler.RequireAuth())
+	e.POST("/users/:id/api-keys", apiKeyHandler.CreateAPIKey, handler.RequireAuth())
+	e.DELETE("/users/:id/api-keys/:keyId", apiKeyHandler.RevokeAPIKey, handler.RequireAuth())
+
+	// Register home timeline route
+	e.GET("/users/:id/feed", feedHandler.GetFeed)
+
+	// Register notification routes (inbox + read management)
+	e.GET("/users/:id/notifications", notificationHandler.GetNotifications)
+	e.GET("/users/:id/notifications/stream", notificationHandler.StreamNotifications)
+	e.PATCH("/users/:id/notifications/read", notificationHandler.MarkAllAsRead)
+	e.PATCH("/users/:id/notifications/:notificationId/read", notificationHandler.MarkAsRead)
+
 	// Start server
 	log.Printf("Starting server on port %s", port)
-	if err := e.Start(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	go func() {
+		if err := e.Start(":" + port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// SIGINT/SIGTERMで新しいリクエストの受付を止め、処理中のリクエストを待ってから
+	// 溜まっている閲覧数をMySQLへ反映して終了する
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down server...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to shut down server gracefully: %v", err)
 	}
+
+	stopFlusher()
+	<-flusherDone
+	log.Println("Server stopped")
 }
 
 func getEnv(key, defaultValue string) string {
@@ -192,3 +607,50 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// getEncryptionKeyEnv はBase64でエンコードされた32バイトの暗号化鍵を読み込みます。
+// 未設定の場合はエラーになり、devModeの場合だけ開発用の固定鍵を使います
+func getEncryptionKeyEnv(key string, devMode bool) ([]byte, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		if !devMode {
+			return nil, fmt.Errorf("not set (set APP_ENV=development to use an insecure development key)")
+		}
+		log.Printf("⚠ %s is not set, using an insecure development key", key)
+		devKey := sha256.Sum256([]byte("dev-insecure-" + key))
+		return devKey[:], nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("must be Base64: %w", err)
+	}
+	if len(decoded) != 32 {
+		return nil, fmt.Errorf("must be 32 bytes, got %d", len(decoded))
+	}
+	return decoded, nil
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+	return d
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+	return n
+}
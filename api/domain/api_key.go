@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// API key scopes
+const (
+	// APIKeyScopeRead allows safe requests (GET, HEAD, OPTIONS)
+	APIKeyScopeRead = "read"
+
+	// APIKeyScopeWrite allows requests that modify data (POST, PUT, PATCH, DELETE)
+	APIKeyScopeWrite = "write"
+)
+
+// APIKeyPrefix is prepended to every key so leaked keys are easy to recognise
+const APIKeyPrefix = "tak_"
+
+var (
+	// ErrInvalidAPIKeyInput is returned when an API key request fails validation
+	ErrInvalidAPIKeyInput = NewError(KindValidation, "invalid_input", "invalid api key input")
+
+	// ErrInvalidAPIKey is returned when an API key is malformed, unknown or revoked
+	ErrInvalidAPIKey = NewError(KindUnauthenticated, "invalid_api_key", "invalid or revoked api key")
+)
+
+// APIKey はマシンクライアント用のAPIキー（シークレットは発行時にのみ返し、DBにはハッシュを保存）
+type APIKey struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"userId"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	SecretHash string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// APIKeyRepository defines methods for api_keys data access
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	// FindByPrefix returns the key including revoked ones (ErrNotFound if not found)
+	FindByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	FindByID(ctx context.Context, id int64) (*APIKey, error)
+	FindByUserID(ctx context.Context, userID int64) ([]APIKey, error)
+	Revoke(ctx context.Context, key *APIKey) error
+	TouchLastUsed(ctx context.Context, key *APIKey, at time.Time) error
+}
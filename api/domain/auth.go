@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Token types (JWT "typ" claim)
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+	// TokenTypeTwoFactor はパスワード確認後、2段階認証コードの入力を待つ間だけ有効なチャレンジトークン
+	TokenTypeTwoFactor = "2fa"
+)
+
+// User statuses (users.status)
+const (
+	UserStatusActive    = "active"
+	UserStatusInactive  = "inactive"
+	UserStatusSuspended = "suspended"
+	UserStatusDeleted   = "deleted"
+)
+
+var (
+	// ErrInvalidAuthInput is returned when a registration or login request fails validation
+	ErrInvalidAuthInput = NewError(KindValidation, "invalid_input", "invalid auth input")
+
+	// ErrUserAlreadyExists is returned when the username or email is already registered
+	ErrUserAlreadyExists = NewError(KindConflict, "user_already_exists", "username or email already registered")
+
+	// ErrInvalidCredentials is returned when the login or password is wrong
+	ErrInvalidCredentials = NewError(KindUnauthenticated, "invalid_credentials", "invalid login or password")
+
+	// ErrInvalidToken is returned when a token is malformed, expired or revoked
+	ErrInvalidToken = NewError(KindUnauthenticated, "invalid_token", "invalid or expired token")
+
+	// ErrAccountDisabled is returned when a suspended or deleted user tries to log in
+	ErrAccountDisabled = NewError(KindForbidden, "account_disabled", "account is disabled")
+)
+
+// AuthUser is the authenticated user attached to the request context
+type AuthUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// APIKeyID/Scopes はAPIキーで認証した場合のみ設定される（JWTセッションはスコープ制限なし）
+	APIKeyID int64    `json:"apiKeyId,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+	// SessionVersion はトークン発行時のセッションバージョン（JWTのみ）。RevokeAllで上がり、それより前に発行したトークンは無効になる
+	SessionVersion int64 `json:"-"`
+}
+
+// IsAPIKey reports whether the user authenticated with an API key
+func (u *AuthUser) IsAPIKey() bool {
+	return u.APIKeyID != 0
+}
+
+// HasScope reports whether the request may use the scope (always true for JWT sessions)
+func (u *AuthUser) HasScope(scope string) bool {
+	if !u.IsAPIKey() {
+		return true
+	}
+	for _, s := range u.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// UserCredentials は認証に必要なユーザー情報（password_hashを含むためレスポンスには使わない）
+type UserCredentials struct {
+	UserID       int64
+	Username     string
+	Email        string
+	PasswordHash string
+	Status       string
+}
+
+// TokenPair is the response of register, login and refresh
+type TokenPair struct {
+	AccessToken           string    `json:"accessToken"`
+	RefreshToken          string    `json:"refreshToken"`
+	TokenType             string    `json:"tokenType"`
+	AccessTokenExpiresAt  time.Time `json:"accessTokenExpiresAt"`
+	RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt"`
+}
+
+// TokenClaims are the verified contents of a JWT
+type TokenClaims struct {
+	TokenID   string
+	Type      string
+	User      AuthUser
+	ExpiresAt time.Time
+}
+
+// AuthRepository defines methods for credential data access
+type AuthRepository interface {
+	// CreateUser inserts a user with a password hash (ErrUserAlreadyExists on duplicate username/email)
+	CreateUser(ctx context.Context, user *User, passwordHash string) error
+
+	// FindCredentials retrieves credentials by username or email
+	FindCredentials(ctx context.Context, login string) (*UserCredentials, error)
+
+	// UpdateLastLogin sets users.last_login_at
+	UpdateLastLogin(ctx context.Context, userID int64, at time.Time) error
+}
+
+// PasswordHasher hashes and verifies passwords
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Compare(hash, password string) error
+}
+
+// TokenIssuer issues and verifies signed tokens
+type TokenIssuer interface {
+	Issue(user AuthUser, tokenType string) (string, *TokenClaims, error)
+	Parse(token, tokenType string) (*TokenClaims, error)
+}
+
+// RefreshTokenStore tracks active refresh tokens so they can be revoked,
+// and the per-user session version that every JWT is checked against
+type RefreshTokenStore interface {
+	Save(ctx context.Context, userID int64, tokenID string, expiresAt time.Time) error
+	// Revoke atomically removes an active token. It returns ErrInvalidToken if the token is not active
+	// (already used, revoked or expired), so each refresh token can be redeemed only once
+	Revoke(ctx context.Context, userID int64, tokenID string) error
+	// RevokeAll revokes every refresh token of the user and bumps the session version,
+	// so access tokens issued so far are rejected as well
+	RevokeAll(ctx context.Context, userID int64) error
+	// SessionVersion returns the user's current session version (0 until RevokeAll is first called)
+	SessionVersion(ctx context.Context, userID int64) (int64, error)
+}
+
+type authUserKey struct{}
+
+// ContextWithAuthUser returns a copy of ctx carrying the authenticated user
+func ContextWithAuthUser(ctx context.Context, user *AuthUser) context.Context {
+	return context.WithValue(ctx, authUserKey{}, user)
+}
+
+// AuthUserFromContext returns the authenticated user (false for anonymous requests)
+func AuthUserFromContext(ctx context.Context) (*AuthUser, bool) {
+	user, ok := ctx.Value(authUserKey{}).(*AuthUser)
+	return user, ok && user != nil
+}
+
+// ViewerIDFromContext returns the requesting user's ID (false for anonymous requests)
+func ViewerIDFromContext(ctx context.Context) (int64, bool) {
+	user, ok := AuthUserFromContext(ctx)
+	if !ok {
+		return 0, false
+	}
+	return user.ID, true
+}
@@ -0,0 +1,67 @@
+package domain
+
+import "context"
+
+// Roles (roles.name)
+const (
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Permissions (permissions.name)
+const (
+	// PermissionPostsManage allows editing, publishing and deleting any user's posts
+	PermissionPostsManage = "posts.manage"
+
+	// PermissionPostsModerate allows archiving and deleting any user's posts
+	PermissionPostsModerate = "posts.moderate"
+
+	// PermissionCommentsModerate allows viewing the moderation queue and approving/rejecting comments
+	PermissionCommentsModerate = "comments.moderate"
+
+	// PermissionUsersManage allows creating users and acting on other users' accounts
+	PermissionUsersManage = "users.manage"
+
+	// PermissionRolesManage allows granting and revoking roles
+	PermissionRolesManage = "roles.manage"
+)
+
+var (
+	// ErrUnauthenticated is returned when an operation requires a logged-in user
+	ErrUnauthenticated = newKindError(KindUnauthenticated, "unauthenticated", "authentication required")
+
+	// ErrForbidden is returned when the user lacks the ownership or permission for an operation
+	ErrForbidden = newKindError(KindForbidden, "forbidden", "forbidden")
+
+	// ErrRoleNotFound is returned when granting a role that does not exist
+	ErrRoleNotFound = NewError(KindNotFound, "not_found", "role not found")
+)
+
+// UserRoles はユーザーに付与されたロールと、それらから導かれる権限
+type UserRoles struct {
+	UserID      int64    `json:"userId"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+// HasPermission reports whether the user has the permission through any role
+func (r *UserRoles) HasPermission(permission string) bool {
+	for _, p := range r.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleRepository defines methods for roles/permissions data access
+type RoleRepository interface {
+	// FindByUserID retrieves the roles and permissions granted to the user (empty if none)
+	FindByUserID(ctx context.Context, userID int64) (*UserRoles, error)
+
+	// Assign grants a role to the user (ErrRoleNotFound if the role does not exist, no-op if already granted)
+	Assign(ctx context.Context, userID int64, role string) error
+
+	// Revoke removes a role from the user (no-op if not granted)
+	Revoke(ctx context.Context, userID int64, role string) error
+}
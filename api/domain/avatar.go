@@ -0,0 +1,35 @@
+package domain
+
+import "context"
+
+// ErrInvalidAvatar is returned when an uploaded avatar is too large or not a supported image
+var ErrInvalidAvatar = NewError(KindValidation, "invalid_avatar", "invalid avatar image")
+
+// BlobStore stores binary objects such as avatar images under slash-separated keys
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Delete removes the object (no error if it does not exist)
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL of the object
+	URL(key string) string
+}
+
+// Thumbnail is a resized square image produced by ImageProcessor
+type Thumbnail struct {
+	Size        int
+	ContentType string
+	Extension   string
+	Data        []byte
+}
+
+// ImageProcessor decodes uploaded images and generates thumbnails
+type ImageProcessor interface {
+	// Thumbnails center-crops the image to a square and resizes it to each size (ErrInvalidAvatar if unsupported)
+	Thumbnails(data []byte, sizes []int) ([]Thumbnail, error)
+}
+
+// Avatar is returned after an avatar upload; Thumbnails maps the pixel size to the URL
+type Avatar struct {
+	AvatarURL  string            `json:"avatarUrl"`
+	Thumbnails map[string]string `json:"thumbnails"`
+}
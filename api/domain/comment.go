@@ -0,0 +1,42 @@
+package domain
+
+import "context"
+
+// Comment status values (comments.status ENUM)
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+	CommentStatusSpam     = "spam"
+)
+
+// ErrInvalidCommentInput is returned when comment fields or moderation requests fail validation
+var ErrInvalidCommentInput = NewError(KindValidation, "invalid_input", "invalid comment input")
+
+// CommentThread represents a comment with its nested replies
+type CommentThread struct {
+	CommentWithAuthor
+	Replies []*CommentThread `json:"replies"`
+}
+
+// CommentRepository defines methods for comment data access
+type CommentRepository interface {
+	// FindByID retrieves a comment by ID regardless of its status
+	FindByID(ctx context.Context, id int64) (*Comment, error)
+
+	// FindApprovedThreadByPostID retrieves a page of approved root comments of a post
+	// together with all of their approved descendants (flat, ordered by created_at)
+	FindApprovedThreadByPostID(ctx context.Context, postID int64, limit, offset int) ([]CommentWithAuthor, error)
+
+	// CountApprovedRootsByPostID returns the number of approved root comments of a post
+	CountApprovedRootsByPostID(ctx context.Context, postID int64) (int64, error)
+
+	// FindByStatus retrieves comments in the given status for moderation (oldest first)
+	FindByStatus(ctx context.Context, status string, limit, offset int) ([]CommentWithAuthor, error)
+
+	// Create inserts a new comment
+	Create(ctx context.Context, comment *Comment) error
+
+	// UpdateStatus changes the comment status and keeps posts.comment_count in sync
+	UpdateStatus(ctx context.Context, id int64, status string) error
+}
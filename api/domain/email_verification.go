@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	// ErrInvalidVerificationToken is returned when a verification token is unknown, expired or already used
+	ErrInvalidVerificationToken = NewError(KindValidation, "invalid_verification_token", "invalid or expired verification token")
+
+	// ErrEmailAlreadyVerified is returned when requesting verification for a verified address
+	ErrEmailAlreadyVerified = NewError(KindConflict, "email_already_verified", "email already verified")
+
+	// ErrEmailNotVerified is returned when an unverified account attempts a restricted operation
+	ErrEmailNotVerified = NewError(KindForbidden, "email_not_verified", "email verification required")
+)
+
+// EmailVerificationToken はメール認証トークン（平文はメールでのみ送信し、DBにはハッシュを保存）
+type EmailVerificationToken struct {
+	ID        int64
+	UserID    int64
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// EmailVerificationRepository defines methods for email_verification_tokens data access
+type EmailVerificationRepository interface {
+	// Create stores a new token and invalidates the user's earlier unused tokens
+	Create(ctx context.Context, token *EmailVerificationToken) error
+
+	// Consume marks the token used and the user's email verified, returning the user ID
+	// (ErrInvalidVerificationToken if the token is unknown, expired or already used)
+	Consume(ctx context.Context, tokenHash string, now time.Time) (int64, error)
+
+	// IsEmailVerified reports whether users.email_verified is set
+	IsEmailVerified(ctx context.Context, userID int64) (bool, error)
+}
+
+// EmailMessage is a plain-text email
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(ctx context.Context, message EmailMessage) error
+}
@@ -0,0 +1,119 @@
+package domain
+
+import "errors"
+
+// ErrorKind classifies domain errors so that adapters can map each class to a stable response
+type ErrorKind int
+
+const (
+	// KindInternal is an unexpected failure (infrastructure errors, bugs)
+	KindInternal ErrorKind = iota
+
+	// KindValidation is invalid input from the caller
+	KindValidation
+
+	// KindUnauthenticated is a missing or invalid credential
+	KindUnauthenticated
+
+	// KindForbidden is an authenticated caller that is not allowed to perform the operation
+	KindForbidden
+
+	// KindNotFound is a resource that does not exist
+	KindNotFound
+
+	// KindConflict is an operation that conflicts with the current state (duplicates, invalid transitions)
+	KindConflict
+
+	// KindRateLimited is a caller that exceeded a request rate limit
+	KindRateLimited
+)
+
+// Error is a domain error with a kind and a stable machine-readable code.
+// errors.Is(err, ErrNotFound) etc. matches any error of the same kind, while
+// errors.Is(err, ErrUserAlreadyExists) etc. still matches only that specific error.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+
+	// generic marks the per-kind errors (ErrNotFound, ErrConflict, ...) that match every error of their kind
+	generic bool
+}
+
+// NewError creates a domain error of the given kind
+func NewError(kind ErrorKind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func newKindError(kind ErrorKind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message, generic: true}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is the generic error of e's kind
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.generic && t.Kind == e.Kind
+}
+
+var (
+	// ErrValidation matches every validation error
+	ErrValidation = newKindError(KindValidation, "invalid_input", "invalid input")
+
+	// ErrNotFound matches every not-found error
+	ErrNotFound = newKindError(KindNotFound, "not_found", "not found")
+
+	// ErrConflict matches every conflict error
+	ErrConflict = newKindError(KindConflict, "conflict", "conflict")
+)
+
+// NotFoundError returns a not-found error for the named resource (e.g. "user" -> "user not found")
+func NotFoundError(resource string) error {
+	return NewError(KindNotFound, "not_found", resource+" not found")
+}
+
+// ConflictError returns a conflict error for a resource that already exists (e.g. "post" -> "post already exists")
+func ConflictError(resource string) error {
+	return NewError(KindConflict, "conflict", resource+" already exists")
+}
+
+// InvalidReferenceError returns a validation error for a resource that refers to a record that does not exist
+// (e.g. "post" -> "post refers to a missing record")
+func InvalidReferenceError(resource string) error {
+	return NewError(KindValidation, "invalid_reference", resource+" refers to a missing record")
+}
+
+// KindOf returns the kind of err, or KindInternal if err is not a domain error
+func KindOf(err error) ErrorKind {
+	if e, ok := asError(err); ok {
+		return e.Kind
+	}
+	return KindInternal
+}
+
+// CodeOf returns the code of err, or "internal_error" if err is not a domain error
+func CodeOf(err error) string {
+	if e, ok := asError(err); ok {
+		return e.Code
+	}
+	return "internal_error"
+}
+
+// MessageOf returns the message of the domain error wrapped in err, or "" if err is not a domain error
+func MessageOf(err error) string {
+	if e, ok := asError(err); ok {
+		return e.Message
+	}
+	return ""
+}
+
+func asError(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+// Feed modes (FEED_MODE)
+const (
+	// FeedModeFanoutOnRead builds the timeline with a JOIN on every request (MySQL)
+	FeedModeFanoutOnRead = "read"
+
+	// FeedModeFanoutOnWrite pushes published posts into per-user timelines (Redis)
+	FeedModeFanoutOnWrite = "write"
+)
+
+// FeedRepository defines methods for home timeline data access
+type FeedRepository interface {
+	// FindFeed retrieves published posts by authors userID follows, newest first
+	FindFeed(ctx context.Context, userID int64, limit, offset int) ([]PostWithDetails, error)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	// ErrSelfFollow is returned when a user tries to follow themselves
+	ErrSelfFollow = NewError(KindValidation, "self_follow", "cannot follow yourself")
+
+	// ErrAlreadyFollowing is returned when the follow relation already exists
+	ErrAlreadyFollowing = NewError(KindConflict, "already_following", "already following")
+
+	// ErrNotFollowing is returned when the follow relation does not exist
+	ErrNotFollowing = NewError(KindNotFound, "not_following", "not following")
+
+	// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+	ErrInvalidCursor = NewError(KindValidation, "invalid_cursor", "invalid cursor")
+)
+
+// FollowUser はフォロワー・フォロー中一覧の1件
+type FollowUser struct {
+	ID          int64     `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName *string   `json:"displayName,omitempty"`
+	AvatarURL   *string   `json:"avatarUrl,omitempty"`
+	FollowedAt  time.Time `json:"followedAt"`
+}
+
+// FollowCursor はフォロー一覧のカーソル位置（followedAt DESC, id DESC の順で次ページを取得）
+type FollowCursor struct {
+	FollowedAt time.Time
+	UserID     int64
+}
+
+// FollowRepository defines methods for user_follows data access
+type FollowRepository interface {
+	// Create inserts a follow relation (ErrAlreadyFollowing if it exists)
+	Create(ctx context.Context, followerID, followingID int64) error
+
+	// Delete removes a follow relation (ErrNotFollowing if it does not exist)
+	Delete(ctx context.Context, followerID, followingID int64) error
+
+	// Exists reports whether followerID follows followingID
+	Exists(ctx context.Context, followerID, followingID int64) (bool, error)
+
+	// FindFollowers retrieves users following userID, newest first, after the cursor
+	FindFollowers(ctx context.Context, userID int64, after *FollowCursor, limit int) ([]FollowUser, error)
+
+	// FindFollowing retrieves users followed by userID, newest first, after the cursor
+	FindFollowing(ctx context.Context, userID int64, after *FollowCursor, limit int) ([]FollowUser, error)
+
+	// FindFollowerIDs retrieves the IDs of all users following userID
+	FindFollowerIDs(ctx context.Context, userID int64) ([]int64, error)
+}
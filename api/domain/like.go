@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Likeable types (likes.likeable_type)
+const (
+	LikeableTypePost    = "post"
+	LikeableTypeComment = "comment"
+)
+
+// ErrInvalidLikeInput is returned when a like request fails validation
+var ErrInvalidLikeInput = NewError(KindValidation, "invalid_input", "invalid like input")
+
+// LikeStatus is the state of a user's like after like/unlike
+type LikeStatus struct {
+	Liked     bool  `json:"liked"`
+	LikeCount int32 `json:"likeCount"`
+
+	// Changed is true when this call actually added or removed the like
+	Changed bool `json:"-"`
+}
+
+// Liker はいいねしたユーザー一覧の1件
+type Liker struct {
+	ID          int64     `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName *string   `json:"displayName,omitempty"`
+	AvatarURL   *string   `json:"avatarUrl,omitempty"`
+	LikedAt     time.Time `json:"likedAt"`
+}
+
+// LikeRepository defines methods for likes data access
+type LikeRepository interface {
+	// Like adds a like and increments like_count in the same transaction (no-op if already liked)
+	Like(ctx context.Context, userID int64, likeableType string, likeableID int64) (*LikeStatus, error)
+
+	// Unlike removes a like and decrements like_count in the same transaction (no-op if not liked)
+	Unlike(ctx context.Context, userID int64, likeableType string, likeableID int64) (*LikeStatus, error)
+
+	// FindLikers retrieves users who liked the target, newest first
+	FindLikers(ctx context.Context, likeableType string, likeableID int64, limit, offset int) ([]Liker, error)
+
+	// FindLikedIDs reports which of likeableIDs userID has liked
+	FindLikedIDs(ctx context.Context, userID int64, likeableType string, likeableIDs []int64) (map[int64]bool, error)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "context"
+
+// Notification types (notifications.type)
+const (
+	NotificationTypeFollow  = "follow"
+	NotificationTypeLike    = "like"
+	NotificationTypeComment = "comment"
+	NotificationTypeMention = "mention"
+	NotificationTypeSystem  = "system"
+)
+
+// Notification inbox filters
+const (
+	NotificationFilterAll    = "all"
+	NotificationFilterUnread = "unread"
+	NotificationFilterRead   = "read"
+)
+
+// ErrInvalidNotificationInput is returned when a notification request fails validation
+var ErrInvalidNotificationInput = NewError(KindValidation, "invalid_input", "invalid notification input")
+
+// NotificationRepository defines methods for notifications data access
+type NotificationRepository interface {
+	// Create inserts a notification for userID (sets ID and CreatedAt)
+	Create(ctx context.Context, userID int64, notification *UserNotification) error
+
+	// FindByUserID retrieves userID's notifications matching the filter, newest first
+	FindByUserID(ctx context.Context, userID int64, filter string, limit, offset int) ([]UserNotification, error)
+
+	// CountByUserID counts userID's notifications matching the filter
+	CountByUserID(ctx context.Context, userID int64, filter string) (int64, error)
+
+	// FindByID retrieves a notification owned by userID (ErrNotFound if not found)
+	FindByID(ctx context.Context, userID, id int64) (*UserNotification, error)
+
+	// MarkRead marks a notification read and sets read_at (ErrNotFound if not found)
+	MarkRead(ctx context.Context, userID, id int64) error
+
+	// MarkAllRead marks all of userID's unread notifications read and returns how many changed
+	MarkAllRead(ctx context.Context, userID int64) (int64, error)
+
+	// FindAfterID retrieves userID's notifications with ID greater than afterID, oldest first
+	FindAfterID(ctx context.Context, userID, afterID int64, limit int) ([]UserNotification, error)
+}
+
+// NotificationBroker delivers newly created notifications to connected clients on every API instance
+type NotificationBroker interface {
+	// Publish sends a created notification to userID's subscribers
+	Publish(ctx context.Context, userID int64, notification *UserNotification) error
+
+	// Subscribe returns a channel of userID's new notifications.
+	// The channel is closed when ctx is done or the subscriber falls too far behind.
+	Subscribe(ctx context.Context, userID int64) (<-chan UserNotification, error)
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	// ErrInvalidResetToken is returned when a password reset token is unknown, expired or already used
+	ErrInvalidResetToken = NewError(KindValidation, "invalid_reset_token", "invalid or expired password reset token")
+
+	// ErrRateLimited is returned when a client exceeds a request rate limit
+	ErrRateLimited = newKindError(KindRateLimited, "rate_limited", "too many requests")
+)
+
+// PasswordResetToken はパスワードリセットトークン（平文はメールでのみ送信し、DBにはハッシュを保存）
+type PasswordResetToken struct {
+	ID        int64
+	UserID    int64
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// PasswordResetRepository defines methods for password_reset_tokens data access
+type PasswordResetRepository interface {
+	// Create stores a new token and invalidates the user's earlier unused tokens
+	Create(ctx context.Context, token *PasswordResetToken) error
+
+	// Reset consumes the token, replaces the user's password hash and invalidates the user's
+	// other unused tokens, returning the user ID
+	// (ErrInvalidResetToken if the token is unknown, expired or already used)
+	Reset(ctx context.Context, tokenHash, passwordHash string, now time.Time) (int64, error)
+}
+
+// RateLimiter counts requests per key in fixed windows
+type RateLimiter interface {
+	// Allow records a request for key and reports whether it is within limit for the current window
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
+}
@@ -5,6 +5,31 @@ import (
 	"time"
 )
 
+// Post status values (posts.status ENUM)
+const (
+	PostStatusDraft     = "draft"
+	PostStatusPublished = "published"
+	PostStatusArchived  = "archived"
+	PostStatusDeleted   = "deleted"
+)
+
+var (
+	// ErrInvalidPostInput is returned when post fields fail validation
+	ErrInvalidPostInput = NewError(KindValidation, "invalid_input", "invalid post input")
+
+	// ErrInvalidPostStatusTransition is returned when a status change is not allowed
+	ErrInvalidPostStatusTransition = NewError(KindConflict, "invalid_status_transition", "invalid post status transition")
+
+	// ErrInvalidSearchQuery is returned when a full-text search query is not usable
+	ErrInvalidSearchQuery = NewError(KindValidation, "invalid_query", "invalid search query")
+)
+
+// Full-text search modes (MATCH ... AGAINST)
+const (
+	SearchModeNatural = "natural"
+	SearchModeBoolean = "boolean"
+)
+
 // Post represents a blog post entity
 type Post struct {
 	ID            int64     `json:"id"`
@@ -41,6 +66,17 @@ type PostWithDetails struct {
 	
 	// Comment preview (latest comments)
 	LatestComments []CommentWithAuthor `json:"latestComments,omitempty"`
+
+	// LikedByMe is true when the requesting user has liked the post (set per request, not cached)
+	LikedByMe bool `json:"likedByMe"`
+}
+
+// PostSearchResult represents a full-text search hit with relevance and highlights
+type PostSearchResult struct {
+	PostWithDetails
+	Relevance      float64 `json:"relevance"`
+	TitleHighlight string  `json:"titleHighlight"`
+	Snippet        string  `json:"snippet"`
 }
 
 // Category represents a post category
@@ -91,7 +127,8 @@ type CommentWithAuthor struct {
 
 // PostRepository defines methods for post data access
 type PostRepository interface {
-	// FindAllWithDetails retrieves all published posts with joined data
+	// FindAllWithDetails retrieves all published posts with joined data.
+	// Listing queries exclude posts whose author is suspended or deleted
 	FindAllWithDetails(ctx context.Context, limit, offset int) ([]PostWithDetails, error)
 	
 	// FindByIDWithDetails retrieves a post by ID with all related data
@@ -109,9 +146,52 @@ type PostRepository interface {
 	// FindFeaturedWithDetails retrieves featured posts with related data
 	FindFeaturedWithDetails(ctx context.Context, limit int) ([]PostWithDetails, error)
 	
-	// GetTotalCount returns total count of published posts
+	// SearchWithDetails runs a full-text search on title and content ordered by relevance
+	SearchWithDetails(ctx context.Context, query, mode string, limit, offset int) ([]PostSearchResult, error)
+	
+	// GetTotalCount returns total count of published posts by visible authors
 	GetTotalCount(ctx context.Context) (int64, error)
 	
-	// IncrementViewCount increments the view count for a post
-	IncrementViewCount(ctx context.Context, postID int64) error
+	// AddViewCounts adds the buffered view deltas (post ID -> views) to the posts' view counts
+	AddViewCounts(ctx context.Context, deltas map[int64]int64) error
+
+	// FindByID retrieves a post row by ID regardless of its status
+	FindByID(ctx context.Context, id int64) (*Post, error)
+
+	// Create inserts a new post and links the given tags
+	Create(ctx context.Context, post *Post, tagIDs []int64) error
+
+	// Update updates post fields and replaces its tag links (nil tagIDs keeps current links)
+	Update(ctx context.Context, post *Post, tagIDs []int64) error
+
+	// UpdateStatus changes the post status (sets published_at on first publish)
+	UpdateStatus(ctx context.Context, id int64, status string) error
+
+	// Delete marks a post as deleted and unlinks its tags
+	Delete(ctx context.Context, id int64) error
+}
+
+// TaxonomyRepository checks the categories and tags that posts refer to
+type TaxonomyRepository interface {
+	// CategoryExists reports whether the category exists
+	CategoryExists(ctx context.Context, id int64) (bool, error)
+
+	// FindExistingTagIDs returns the IDs among ids that exist
+	FindExistingTagIDs(ctx context.Context, ids []int64) ([]int64, error)
+}
+
+// CanTransitionPostStatus reports whether a post may move from one status to another
+//
+//	draft → published → archived → deleted
+//	archived → published（再公開）
+func CanTransitionPostStatus(from, to string) bool {
+	switch from {
+	case PostStatusDraft:
+		return to == PostStatusPublished || to == PostStatusDeleted
+	case PostStatusPublished:
+		return to == PostStatusArchived || to == PostStatusDeleted
+	case PostStatusArchived:
+		return to == PostStatusPublished || to == PostStatusDeleted
+	}
+	return false
 }
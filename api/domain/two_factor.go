@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	// ErrInvalidTwoFactorCode is returned when a TOTP or recovery code does not match
+	ErrInvalidTwoFactorCode = NewError(KindUnauthenticated, "invalid_two_factor_code", "invalid two-factor code")
+
+	// ErrTwoFactorAlreadyEnabled is returned when enrolling an account that already has 2FA enabled
+	ErrTwoFactorAlreadyEnabled = NewError(KindConflict, "two_factor_already_enabled", "two-factor authentication already enabled")
+
+	// ErrTwoFactorNotEnabled is returned when disabling or verifying 2FA for an account without it
+	ErrTwoFactorNotEnabled = NewError(KindConflict, "two_factor_not_enabled", "two-factor authentication not enabled")
+
+	// ErrTwoFactorNotEnrolled is returned when enabling 2FA before an enrollment secret was issued
+	ErrTwoFactorNotEnrolled = NewError(KindConflict, "two_factor_not_enrolled", "two-factor enrollment not started")
+)
+
+// TwoFactorSettings はユーザーのTOTP設定（EnabledAtがnilの間は登録途中で、ログインには使われない）
+type TwoFactorSettings struct {
+	UserID int64
+	Secret string // SecretCipherで暗号化されたTOTPシークレット（復号するとBase32）
+	// LastUsedStep は最後に受け付けたTOTPの時間ステップ（同じコードの再利用を防ぐ）
+	LastUsedStep int64
+	EnabledAt    *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// TwoFactorEnrollment is returned when starting 2FA enrollment
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// TwoFactorChallenge is returned by login when the account requires a second factor
+type TwoFactorChallenge struct {
+	TwoFactorRequired bool      `json:"twoFactorRequired"`
+	ChallengeToken    string    `json:"challengeToken"`
+	ExpiresAt         time.Time `json:"expiresAt"`
+}
+
+// LoginResult holds either a token pair or, for accounts with 2FA, a challenge to complete
+type LoginResult struct {
+	Tokens    *TokenPair
+	Challenge *TwoFactorChallenge
+}
+
+// TwoFactorRepository defines methods for user_two_factor and user_recovery_codes data access
+type TwoFactorRepository interface {
+	// FindByUserID returns the user's settings (ErrNotFound if 2FA was never enrolled)
+	FindByUserID(ctx context.Context, userID int64) (*TwoFactorSettings, error)
+
+	// SaveSecret stores a pending (encrypted) secret, replacing any earlier unfinished enrollment
+	SaveSecret(ctx context.Context, userID int64, secret string) error
+
+	// Enable marks 2FA enabled, records the step of the confirming code and replaces the recovery codes
+	Enable(ctx context.Context, userID int64, step int64, recoveryCodeHashes []string) error
+
+	// Disable removes the secret and recovery codes
+	Disable(ctx context.Context, userID int64) error
+
+	// UseStep records step as used; it returns false if step is not newer than the last used one
+	UseStep(ctx context.Context, userID int64, step int64) (bool, error)
+
+	// ConsumeRecoveryCode marks an unused recovery code used; it returns false if none matched
+	ConsumeRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error)
+}
+
+// SecretCipher encrypts secrets stored at rest (e.g. TOTP secrets).
+// context is authenticated with the ciphertext, so a value copied to another row (e.g. another user) fails to decrypt
+type SecretCipher interface {
+	Encrypt(plaintext, context string) (string, error)
+	Decrypt(ciphertext, context string) (string, error)
+}
+
+// TOTP generates and validates RFC 6238 time-based one-time passwords
+type TOTP interface {
+	GenerateSecret() (string, error)
+	ProvisioningURI(secret, accountName string) string
+	// Validate returns the matched time step, allowing for clock skew around at
+	Validate(secret, code string, at time.Time) (int64, bool)
+}
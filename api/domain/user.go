@@ -5,21 +5,99 @@ import (
 	"time"
 )
 
-// User represents a user entity
+// User represents a row of the users table.
+// BirthDate is stored in user_profiles; Age is derived from it
 type User struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Age       *int32    `json:"age,omitempty"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID            int64      `json:"id"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email"`
+	Status        string     `json:"status"`
+	EmailVerified bool       `json:"emailVerified"`
+	LastLoginAt   *time.Time `json:"lastLoginAt,omitempty"`
+	BirthDate     *string    `json:"birthDate,omitempty"` // YYYY-MM-DD
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// Age returns the user's age on the date of now, or nil if the birth date is not set
+func (u *User) Age(now time.Time) *int32 {
+	if u.BirthDate == nil {
+		return nil
+	}
+	birthDate, err := time.Parse("2006-01-02", *u.BirthDate)
+	if err != nil {
+		return nil
+	}
+
+	age := int32(now.Year() - birthDate.Year())
+	// 誕生日前なら1歳引く
+	if now.Month() < birthDate.Month() || (now.Month() == birthDate.Month() && now.Day() < birthDate.Day()) {
+		age--
+	}
+	if age < 0 {
+		return nil
+	}
+	return &age
+}
+
+// HidePrivateFields clears the fields only the user themselves and users.manage holders may see
+func (u *User) HidePrivateFields() {
+	u.Email = ""
+	u.LastLoginAt = nil
+	u.BirthDate = nil
+}
+
+// ErrInvalidUserInput is returned when user fields fail validation
+var ErrInvalidUserInput = NewError(KindValidation, "invalid_input", "invalid user input")
+
+// User list sort fields (GET /users?sort=)
+const (
+	UserSortCreatedAt = "created_at"
+	UserSortUsername  = "username"
+	UserSortID        = "id"
+)
+
+// ErrInvalidUserListQuery is returned when user list filters, sorting or paging are invalid
+var ErrInvalidUserListQuery = NewError(KindValidation, "invalid_query", "invalid user list query")
+
+// UserListFilter はユーザー一覧の絞り込み条件（ゼロ値の項目は絞り込まない）
+type UserListFilter struct {
+	Statuses       []string
+	EmailVerified  *bool
+	CreatedFrom    *time.Time // この日時以降（含む）
+	CreatedTo      *time.Time // この日時より前（含まない）
+	UsernamePrefix string
+}
+
+// UserCursor はユーザー一覧のカーソル位置（並び順の列の値とIDで次ページを取得）
+type UserCursor struct {
+	CreatedAt time.Time
+	Username  string
+	ID        int64
+}
+
+// UserListQuery はユーザー一覧の取得条件。
+// Afterがある場合はカーソル、ない場合はOffsetでページングします
+type UserListQuery struct {
+	Filter UserListFilter
+	Sort   string
+	Desc   bool
+	Limit  int
+	Offset int
+	After  *UserCursor
 }
 
 // UserRepository defines the interface for user data operations
 type UserRepository interface {
-	FindAll(ctx context.Context) ([]User, error)
+	// FindAll retrieves users matching the query, ordered by the sort field and then by ID
+	FindAll(ctx context.Context, query UserListQuery) ([]User, error)
+	// Count returns the number of users matching the filter
+	Count(ctx context.Context, filter UserListFilter) (int64, error)
 	FindByID(ctx context.Context, id int64) (*User, error)
+	FindByUsernames(ctx context.Context, usernames []string) ([]User, error)
+	// Create inserts the user and its birth date (ErrUserAlreadyExists on duplicate username/email)
 	Create(ctx context.Context, user *User) error
+	// Update saves username, email, email_verified and the birth date (status is changed by UserStatusRepository)
 	Update(ctx context.Context, user *User) error
 	Delete(ctx context.Context, id int64) error
 }
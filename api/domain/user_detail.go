@@ -81,6 +81,7 @@ type UserDetail struct {
 	Email          string    `json:"email"`
 	Status         string    `json:"status"`
 	EmailVerified  bool      `json:"emailVerified"`
+	TwoFactorEnabled bool    `json:"twoFactorEnabled"`
 	LastLoginAt    *time.Time `json:"lastLoginAt,omitempty"`
 	CreatedAt      time.Time `json:"createdAt"`
 	UpdatedAt      time.Time `json:"updatedAt"`
@@ -0,0 +1,26 @@
+package domain
+
+import "context"
+
+// Gender values (user_profiles.gender ENUM)
+const (
+	GenderMale           = "male"
+	GenderFemale         = "female"
+	GenderOther          = "other"
+	GenderPreferNotToSay = "prefer_not_to_say"
+)
+
+// ErrInvalidProfileInput is returned when profile fields fail validation
+var ErrInvalidProfileInput = NewError(KindValidation, "invalid_input", "invalid profile input")
+
+// UserProfileRepository defines methods for user_profiles data access
+type UserProfileRepository interface {
+	// FindByUserID returns the profile (empty if the user has no profile row; ErrNotFound if the user does not exist)
+	FindByUserID(ctx context.Context, userID int64) (*UserProfile, error)
+
+	// Upsert inserts or replaces the editable profile fields (avatar_url is left unchanged)
+	Upsert(ctx context.Context, userID int64, profile *UserProfile) error
+
+	// UpdateAvatarURL sets avatar_url, creating the profile row if needed (nil clears it)
+	UpdateAvatarURL(ctx context.Context, userID int64, avatarURL *string) error
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	// ErrInvalidUserStatusInput is returned when a status change request fails validation (e.g. missing reason)
+	ErrInvalidUserStatusInput = NewError(KindValidation, "invalid_input", "invalid user status input")
+
+	// ErrInvalidUserStatusTransition is returned when an account status change is not allowed
+	ErrInvalidUserStatusTransition = NewError(KindConflict, "invalid_status_transition", "invalid user status transition")
+)
+
+// UserStatusChange はアカウント状態の変更履歴（user_status_history）
+type UserStatusChange struct {
+	ID         int64     `json:"id"`
+	UserID     int64     `json:"userId"`
+	FromStatus string    `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	Reason     *string   `json:"reason,omitempty"`
+	ChangedBy  *int64    `json:"changedBy,omitempty"` // 操作したユーザー（不明な場合はnil）
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// UserStatusRepository defines methods for users.status changes and their history
+type UserStatusRepository interface {
+	// FindStatus returns users.status (ErrNotFound if the user does not exist)
+	FindStatus(ctx context.Context, userID int64) (string, error)
+
+	// ChangeStatus moves the user from change.FromStatus to change.ToStatus and records the change
+	// (ErrInvalidUserStatusTransition if the status no longer equals FromStatus)
+	ChangeStatus(ctx context.Context, change *UserStatusChange) error
+
+	// FindHistory retrieves status changes for the user, newest first
+	FindHistory(ctx context.Context, userID int64, limit int) ([]UserStatusChange, error)
+}
+
+// IsUserStatusHidden reports whether users in the status are hidden from listings along with their posts
+func IsUserStatusHidden(status string) bool {
+	return status == UserStatusSuspended || status == UserStatusDeleted
+}
+
+// CanTransitionUserStatus reports whether an account may move from one status to another
+//
+//	active ⇄ inactive（休止・再開）
+//	active/inactive → suspended → active（停止・再開）
+//	active/inactive/suspended → deleted → active（論理削除・復元）
+func CanTransitionUserStatus(from, to string) bool {
+	switch from {
+	case UserStatusActive:
+		return to == UserStatusInactive || to == UserStatusSuspended || to == UserStatusDeleted
+	case UserStatusInactive:
+		return to == UserStatusActive || to == UserStatusSuspended || to == UserStatusDeleted
+	case UserStatusSuspended:
+		return to == UserStatusActive || to == UserStatusDeleted
+	case UserStatusDeleted:
+		return to == UserStatusActive
+	}
+	return false
+}
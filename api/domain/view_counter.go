@@ -0,0 +1,29 @@
+package domain
+
+import "context"
+
+// ViewCounter buffers post views and writes them to the post repository in batches
+type ViewCounter interface {
+	// Record counts one view of a post. A non-empty viewer identifies the visitor;
+	// repeated views by the same visitor within the dedup window are not counted.
+	Record(ctx context.Context, postID int64, viewer string) error
+
+	// Pending returns the views recorded but not yet flushed, keyed by post ID
+	Pending(ctx context.Context, postIDs []int64) (map[int64]int64, error)
+
+	// Flush writes the buffered views to the post repository and returns the number of posts updated
+	Flush(ctx context.Context) (int, error)
+}
+
+type visitorKey struct{}
+
+// ContextWithVisitor returns a copy of ctx carrying an identifier of the anonymous visitor (e.g. client IP)
+func ContextWithVisitor(ctx context.Context, visitor string) context.Context {
+	return context.WithValue(ctx, visitorKey{}, visitor)
+}
+
+// VisitorFromContext returns the visitor identifier ("" if not set)
+func VisitorFromContext(ctx context.Context) string {
+	visitor, _ := ctx.Value(visitorKey{}).(string)
+	return visitor
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"github.com/rssh-jp/test-api/api/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type bcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher creates a password hasher using bcrypt with the given cost (bcrypt.DefaultCost if 0)
+func NewBcryptHasher(cost int) domain.PasswordHasher {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	return &bcryptHasher{cost: cost}
+}
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Compare は一致しない場合や不正なハッシュの場合に domain.ErrInvalidCredentials を返します
+func (h *bcryptHasher) Compare(hash, password string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return domain.ErrInvalidCredentials
+	}
+	return nil
+}
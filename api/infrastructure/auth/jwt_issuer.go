@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+const jwtIssuer = "test-api"
+
+// twoFactorChallengeTTL は2段階認証チャレンジトークンの有効期限（コード入力までの猶予）
+const twoFactorChallengeTTL = 5 * time.Minute
+
+// jwtClaims はアクセストークン・リフレッシュトークン・2段階認証チャレンジ共通のクレーム
+type jwtClaims struct {
+	Username string `json:"username"`
+	Type     string `json:"typ"`
+	// SessionVersion は発行時のセッションバージョン（パスワード再設定等で上がると無効になる）
+	SessionVersion int64 `json:"ver,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type jwtTokenIssuer struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewJWTTokenIssuer creates a token issuer signing HS256 JWTs with the given secret
+func NewJWTTokenIssuer(secret string, accessTTL, refreshTTL time.Duration) domain.TokenIssuer {
+	return &jwtTokenIssuer{
+		secret:     []byte(secret),
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+func (i *jwtTokenIssuer) Issue(user domain.AuthUser, tokenType string) (string, *domain.TokenClaims, error) {
+	ttl := i.accessTTL
+	switch tokenType {
+	case domain.TokenTypeRefresh:
+		ttl = i.refreshTTL
+	case domain.TokenTypeTwoFactor:
+		ttl = twoFactorChallengeTTL
+	}
+
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := jwtClaims{
+		Username:       user.Username,
+		Type:           tokenType,
+		SessionVersion: user.SessionVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Issuer:    jwtIssuer,
+			Subject:   strconv.FormatInt(user.ID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.secret)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return signed, &domain.TokenClaims{
+		TokenID:   tokenID,
+		Type:      tokenType,
+		User:      user,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Parse は署名・有効期限・種別を検証し、不正な場合は domain.ErrInvalidToken を返します
+func (i *jwtTokenIssuer) Parse(token, tokenType string) (*domain.TokenClaims, error) {
+	var claims jwtClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return i.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(jwtIssuer), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+	if claims.Type != tokenType {
+		return nil, domain.ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return &domain.TokenClaims{
+		TokenID:   claims.ID,
+		Type:      claims.Type,
+		User:      domain.AuthUser{ID: userID, Username: claims.Username, SessionVersion: claims.SessionVersion},
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
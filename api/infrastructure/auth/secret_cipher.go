@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// secretCipherPrefix は暗号文の形式のバージョン（鍵や方式を変えた場合に区別するため）
+const secretCipherPrefix = "v1:"
+
+type aesGCMCipher struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCMCipher creates a secret cipher using AES-256-GCM with the given 32-byte key
+func NewAESGCMCipher(key []byte) (domain.SecretCipher, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &aesGCMCipher{aead: aead}, nil
+}
+
+// Encrypt は "v1:" + base64(nonce || 暗号文) を返します。contextは追加認証データとして使います
+func (c *aesGCMCipher) Encrypt(plaintext, context string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), []byte(context))
+	return secretCipherPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt は改ざん・鍵違い・contextの不一致の場合にエラーを返します
+func (c *aesGCMCipher) Decrypt(ciphertext, context string) (string, error) {
+	encoded, ok := strings.CutPrefix(ciphertext, secretCipherPrefix)
+	if !ok {
+		return "", fmt.Errorf("unsupported ciphertext format")
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", fmt.Errorf("malformed ciphertext")
+	}
+
+	nonce, sealed := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, sealed, []byte(context))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAESGCMCipherRoundTrip(t *testing.T) {
+	c, err := NewAESGCMCipher(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ciphertext, err := c.Encrypt("JBSWY3DPEHPK3PXP", "user:1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Contains(ciphertext, "JBSWY3DPEHPK3PXP") {
+		t.Fatalf("Expected the plaintext not to appear in %q", ciphertext)
+	}
+	if again, _ := c.Encrypt("JBSWY3DPEHPK3PXP", "user:1"); again == ciphertext {
+		t.Errorf("Expected a fresh nonce for every encryption")
+	}
+
+	plaintext, err := c.Decrypt(ciphertext, "user:1")
+	if err != nil || plaintext != "JBSWY3DPEHPK3PXP" {
+		t.Fatalf("Expected the original secret, got %q (%v)", plaintext, err)
+	}
+
+	// 別のユーザーの行に移した暗号文・別の鍵・改ざんされた暗号文は復号できない
+	if _, err := c.Decrypt(ciphertext, "user:2"); err == nil {
+		t.Errorf("Expected an error for a different context")
+	}
+	other, _ := NewAESGCMCipher(bytes.Repeat([]byte{2}, 32))
+	if _, err := other.Decrypt(ciphertext, "user:1"); err == nil {
+		t.Errorf("Expected an error for a different key")
+	}
+	tampered := ciphertext[:len(ciphertext)-1] + "A"
+	if tampered == ciphertext {
+		tampered = ciphertext[:len(ciphertext)-1] + "B"
+	}
+	if _, err := c.Decrypt(tampered, "user:1"); err == nil {
+		t.Errorf("Expected an error for a tampered ciphertext")
+	}
+	if _, err := c.Decrypt("JBSWY3DPEHPK3PXP", "user:1"); err == nil {
+		t.Errorf("Expected an error for a plaintext value")
+	}
+}
+
+func TestNewAESGCMCipherRejectsShortKey(t *testing.T) {
+	if _, err := NewAESGCMCipher([]byte("too-short")); err == nil {
+		t.Errorf("Expected an error for a key that is not 32 bytes")
+	}
+}
@@ -0,0 +1,22 @@
+package cache
+
+import "encoding/json"
+
+// Codec converts cached values to and from bytes
+type Codec[T any] interface {
+	Encode(value T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// JSONCodec encodes values as JSON
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Encode(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
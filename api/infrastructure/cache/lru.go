@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lruGenerationSlots は無効化の世代を管理する枠の数。同じ枠のキーの無効化は、互いの書き込みも見送らせる
+const lruGenerationSlots = 256
+
+// LRU is an in-process cache of decoded values bounded by the number of entries and a TTL.
+// One LRU can be shared by ReadThrough caches of different types as long as their keys don't collide.
+// Values are stored as is, so they must not be modified once set.
+type LRU struct {
+	maxEntries int
+	ttl        time.Duration
+	now        func() time.Time // テストで時刻を差し替えるため
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+	// gens はキーごとの無効化の世代（キーのハッシュで枠に分ける）。Delete・Clearで進める
+	gens [lruGenerationSlots]uint64
+}
+
+type lruEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// NewLRU creates an LRU that keeps at most maxEntries values for at most ttl each
+func NewLRU(maxEntries int, ttl time.Duration) *LRU {
+	return &LRU{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// Get returns the value of key (false if it is missing or expired)
+func (l *LRU) Get(key string) (any, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*lruEntry)
+	if l.now().After(entry.expiresAt) {
+		l.removeElement(elem)
+		return nil, false
+	}
+
+	l.ll.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Set stores value at key for ttl, capped at the LRU's TTL, evicting the least recently used entry when full
+func (l *LRU) Set(key string, value any, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.set(key, value, ttl)
+}
+
+// Generation returns the invalidation generation of key. Take it before reading a value from the source
+// and store the value with SetIfGeneration, so that a value read before key was invalidated is not cached.
+func (l *LRU) Generation(key string) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.gens[generationSlot(key)]
+}
+
+// SetIfGeneration is Set, skipped if key has been deleted or the LRU cleared since gen was taken.
+// Keys sharing a slot with key may also cause a skip. It reports whether value was stored
+func (l *LRU) SetIfGeneration(key string, value any, ttl time.Duration, gen uint64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.gens[generationSlot(key)] != gen {
+		return false
+	}
+	l.set(key, value, ttl)
+	return true
+}
+
+// set はl.muを持った状態でvalueを保存します
+func (l *LRU) set(key string, value any, ttl time.Duration) {
+	if ttl > l.ttl {
+		ttl = l.ttl
+	}
+	if ttl <= 0 || l.maxEntries <= 0 {
+		return
+	}
+	expiresAt := l.now().Add(ttl)
+
+	if elem, ok := l.items[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		l.ll.MoveToFront(elem)
+		return
+	}
+
+	l.items[key] = l.ll.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	if l.ll.Len() > l.maxEntries {
+		l.removeElement(l.ll.Back())
+	}
+}
+
+// Delete removes keys and advances their generations (even if they are not cached)
+func (l *LRU) Delete(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		l.gens[generationSlot(key)]++
+		if elem, ok := l.items[key]; ok {
+			l.removeElement(elem)
+		}
+	}
+}
+
+// Clear removes every entry and advances every generation
+func (l *LRU) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i := range l.gens {
+		l.gens[i]++
+	}
+	l.ll.Init()
+	l.items = make(map[string]*list.Element)
+}
+
+// Len returns the number of entries, including expired ones not yet removed
+func (l *LRU) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ll.Len()
+}
+
+func (l *LRU) removeElement(elem *list.Element) {
+	l.ll.Remove(elem)
+	delete(l.items, elem.Value.(*lruEntry).key)
+}
+
+// generationSlot はkeyの世代の枠（FNV-1aハッシュ）を返します
+func generationSlot(key string) int {
+	h := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= 16777619
+	}
+	return int(h % lruGenerationSlots)
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestLRU(maxEntries int, ttl time.Duration, clock *fakeClock) *LRU {
+	l := NewLRU(maxEntries, ttl)
+	l.now = clock.Now
+	return l
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	l := newTestLRU(2, time.Minute, &fakeClock{now: time.Unix(1700000000, 0)})
+
+	l.Set("a", 1, time.Minute)
+	l.Set("b", 2, time.Minute)
+	// aを使うと、次に追い出されるのはb
+	if _, ok := l.Get("a"); !ok {
+		t.Fatal("Expected a to be cached")
+	}
+	l.Set("c", 3, time.Minute)
+
+	if _, ok := l.Get("b"); ok {
+		t.Error("Expected b to be evicted as the least recently used entry")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if value, ok := l.Get(key); !ok || value != want {
+			t.Errorf("Expected %s=%d, got %v (%t)", key, want, value, ok)
+		}
+	}
+	if l.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", l.Len())
+	}
+}
+
+func TestLRUOverwriteDoesNotEvict(t *testing.T) {
+	l := newTestLRU(2, time.Minute, &fakeClock{now: time.Unix(1700000000, 0)})
+
+	l.Set("a", 1, time.Minute)
+	l.Set("b", 2, time.Minute)
+	l.Set("a", 10, time.Minute)
+
+	if value, ok := l.Get("a"); !ok || value != 10 {
+		t.Errorf("Expected the overwritten value, got %v (%t)", value, ok)
+	}
+	if _, ok := l.Get("b"); !ok {
+		t.Error("Expected b to stay cached")
+	}
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	l := newTestLRU(10, time.Minute, clock)
+
+	l.Set("short", 1, 10*time.Second)
+	// LRUのTTLより長い指定はLRUのTTLまで
+	l.Set("long", 2, time.Hour)
+
+	clock.Advance(10 * time.Second)
+	if _, ok := l.Get("short"); !ok {
+		t.Error("Expected short to be cached until its TTL passes")
+	}
+	clock.Advance(time.Millisecond)
+	if _, ok := l.Get("short"); ok {
+		t.Error("Expected short to expire after its TTL")
+	}
+
+	clock.Advance(time.Minute)
+	if _, ok := l.Get("long"); ok {
+		t.Error("Expected long to expire after the LRU's TTL")
+	}
+	if l.Len() != 0 {
+		t.Errorf("Expected expired entries to be removed, got %d entries", l.Len())
+	}
+}
+
+func TestLRUSkipsUnusableEntries(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+
+	l := newTestLRU(10, time.Minute, clock)
+	l.Set("zero", 1, 0)
+	l.Set("negative", 1, -time.Second)
+	if l.Len() != 0 {
+		t.Errorf("Expected entries without TTL to be skipped, got %d entries", l.Len())
+	}
+
+	disabled := newTestLRU(0, time.Minute, clock)
+	disabled.Set("a", 1, time.Minute)
+	if _, ok := disabled.Get("a"); ok {
+		t.Error("Expected an LRU of size 0 to keep nothing")
+	}
+}
+
+func TestLRUDeleteAndClear(t *testing.T) {
+	l := newTestLRU(10, time.Minute, &fakeClock{now: time.Unix(1700000000, 0)})
+	for _, key := range []string{"a", "b", "c"} {
+		l.Set(key, key, time.Minute)
+	}
+
+	l.Delete("a", "b", "missing")
+	if _, ok := l.Get("a"); ok {
+		t.Error("Expected a to be deleted")
+	}
+	if value, ok := l.Get("c"); !ok || value != "c" {
+		t.Errorf("Expected c to stay cached, got %v (%t)", value, ok)
+	}
+
+	l.Clear()
+	if _, ok := l.Get("c"); ok || l.Len() != 0 {
+		t.Errorf("Expected every entry to be cleared, got %d entries", l.Len())
+	}
+}
+
+func TestLRUSetIfGenerationSkipsInvalidatedKeys(t *testing.T) {
+	l := newTestLRU(10, time.Minute, &fakeClock{now: time.Unix(1700000000, 0)})
+	if generationSlot("a") == generationSlot("b") {
+		t.Fatal("Expected a and b to use different generation slots")
+	}
+
+	// 読み込み中にaが削除された場合、読み込んだ値は入れない
+	gen := l.Generation("a")
+	l.Delete("a")
+	if l.SetIfGeneration("a", "stale", time.Minute, gen) {
+		t.Error("Expected a value read before the delete to be skipped")
+	}
+	if _, ok := l.Get("a"); ok {
+		t.Error("Expected a to stay uncached")
+	}
+
+	// 他のキーの削除では見送らない
+	gen = l.Generation("a")
+	l.Delete("b")
+	if !l.SetIfGeneration("a", "fresh", time.Minute, gen) {
+		t.Error("Expected a value to be stored when only another key was deleted")
+	}
+
+	gen = l.Generation("a")
+	l.Clear()
+	if l.SetIfGeneration("a", "stale", time.Minute, gen) {
+		t.Error("Expected a value read before Clear to be skipped")
+	}
+}
@@ -0,0 +1,404 @@
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// refreshTimeout はバックグラウンドでの再取得に使う時間
+	refreshTimeout = 10 * time.Second
+	// lockPollInterval は他のインスタンスの読み込み完了を待つ間隔
+	lockPollInterval = 50 * time.Millisecond
+	// entryVersion は保存形式のバージョン（先頭1バイト）。形式が違う値はキャッシュミスとして扱う
+	entryVersion = 1
+)
+
+var errInvalidEntry = errors.New("invalid cache entry")
+
+// Store is the key-value store behind a ReadThrough cache (e.g. Redis)
+type Store interface {
+	// Get returns the value stored at key (false if there is none)
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value at key for ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Lock acquires the lock named key for at most ttl. It returns false if someone else holds it
+	Lock(ctx context.Context, key string, ttl time.Duration) (unlock func(), acquired bool, err error)
+}
+
+// TTL is how long a cached value is served as fresh (Soft) and how long it is kept at all (Hard).
+// Between Soft and Hard the stale value is served while it is reloaded in the background.
+type TTL struct {
+	Soft time.Duration
+	Hard time.Duration
+}
+
+// TTLPolicy returns the TTL for a cache key
+type TTLPolicy func(key string) TTL
+
+// FixedTTL returns a policy that uses ttl for every key
+func FixedTTL(ttl TTL) TTLPolicy {
+	return func(string) TTL { return ttl }
+}
+
+// Hooks are called on cache events, e.g. to log them or record hit/miss metrics. Nil hooks are skipped.
+type Hooks struct {
+	L1Hit func(key string)
+	Hit   func(key string)
+	Stale func(key string)
+	Miss  func(key string)
+	Set   func(key string, ttl TTL)
+	Error func(key string, err error)
+}
+
+// LogHooks returns hooks that log cache events in the format used across the repositories
+func LogHooks(source string) Hooks {
+	return Hooks{
+		L1Hit: func(key string) {
+			log.Printf("✓ L1 Cache HIT: %s", key)
+		},
+		Hit: func(key string) {
+			log.Printf("✓ Redis Cache HIT: %s", key)
+		},
+		Stale: func(key string) {
+			log.Printf("✓ Redis Cache HIT (stale): %s - Refreshing in background", key)
+		},
+		Miss: func(key string) {
+			log.Printf("✗ Redis Cache MISS: %s - Fetching from %s", key, source)
+		},
+		Set: func(key string, ttl TTL) {
+			log.Printf("→ Redis Cache SET: %s (TTL: soft %v / hard %v)", key, ttl.Soft, ttl.Hard)
+		},
+		Error: func(key string, err error) {
+			log.Printf("Warning: cache %s: %v", key, err)
+		},
+	}
+}
+
+// Loader loads the value of a key on a cache miss
+type Loader[T any] func(ctx context.Context) (T, error)
+
+// Options configures a ReadThrough cache
+type Options[T any] struct {
+	// Codec encodes the cached values (JSONCodec if nil)
+	Codec Codec[T]
+
+	// TTL decides the TTL of each key (required)
+	TTL TTLPolicy
+
+	// LockTTL enables a distributed lock in the store so that only one instance loads a missing key.
+	// The others wait up to LockTTL for it to be stored. Zero disables the lock.
+	LockTTL time.Duration
+
+	// L1 keeps fresh decoded values in process in front of the store. Entries live until the soft TTL
+	// or the LRU's TTL, whichever comes first; deleting keys from the store does not remove them,
+	// so the owner of the LRU has to drop invalidated keys (e.g. on a pub/sub message). Nil disables it.
+	L1 *LRU
+
+	// Clone copies values going into and out of L1, so that callers modifying what they get
+	// change neither the cached value nor what other callers get. Nil shares the values,
+	// which is only safe when T is never modified (e.g. int64).
+	Clone func(T) T
+
+	Hooks Hooks
+}
+
+// ReadThrough is a typed read-through cache with stampede protection:
+//   - concurrent loads of the same key in this process are coalesced (singleflight)
+//   - with LockTTL, loads of the same key are also coalesced across instances
+//   - values past their soft TTL are served stale while a single background reload runs
+//   - with L1, fresh values are also served from process memory without a round trip to the store or decoding
+//
+// Store errors are reported to Hooks.Error and treated as misses; only loader errors are returned.
+// Every caller gets its own decoded (or with L1, cloned) value, so callers may modify what they get.
+type ReadThrough[T any] struct {
+	store   Store
+	codec   Codec[T]
+	ttl     TTLPolicy
+	lockTTL time.Duration
+	l1      *LRU
+	clone   func(T) T
+	hooks   Hooks
+	now     func() time.Time // テストで時刻を差し替えるため
+
+	group      singleflight.Group
+	refreshing sync.Map
+}
+
+// New creates a read-through cache backed by store
+func New[T any](store Store, opts Options[T]) *ReadThrough[T] {
+	codec := opts.Codec
+	if codec == nil {
+		codec = JSONCodec[T]{}
+	}
+	return &ReadThrough[T]{
+		store:   store,
+		codec:   codec,
+		ttl:     opts.TTL,
+		lockTTL: opts.LockTTL,
+		l1:      opts.L1,
+		clone:   opts.Clone,
+		hooks:   opts.Hooks,
+		now:     time.Now,
+	}
+}
+
+// Get returns the cached value of key, loading and storing it with load on a miss
+func (c *ReadThrough[T]) Get(ctx context.Context, key string, load Loader[T]) (T, error) {
+	if value, ok := c.readL1(key); ok {
+		c.onL1Hit(key)
+		return value, nil
+	}
+
+	// 読み込み中に無効化されたキーの古い値をL1に入れないよう、storeから読む前の世代を控える
+	gen := c.l1Generation(key)
+	if data, freshUntil, ok := c.read(ctx, key); ok {
+		value, err := c.codec.Decode(data)
+		if err == nil {
+			if c.now().Before(freshUntil) {
+				c.onHit(key)
+				c.writeL1(key, value, freshUntil, gen)
+			} else {
+				c.onStale(key)
+				c.refresh(key, load)
+			}
+			return value, nil
+		}
+		c.onError(key, fmt.Errorf("failed to decode: %w", err))
+	}
+
+	c.onMiss(key)
+	// 最初の呼び出し側のリクエストが切断されても、相乗りしている他の呼び出し側の読み込みは続ける
+	loadCtx := context.WithoutCancel(ctx)
+	data, err, _ := c.group.Do(key, func() (interface{}, error) {
+		return c.fill(loadCtx, key, load, true)
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return c.codec.Decode(data.([]byte))
+}
+
+// Set stores value at key (e.g. a value loaded together with another key)
+func (c *ReadThrough[T]) Set(ctx context.Context, key string, value T) error {
+	data, err := c.codec.Encode(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache %s: %w", key, err)
+	}
+	return c.write(ctx, key, data, value, c.l1Generation(key))
+}
+
+// refresh はkeyをバックグラウンドで再取得します（同じキーの再取得はプロセス内で同時に1つだけ）
+func (c *ReadThrough[T]) refresh(key string, load Loader[T]) {
+	if _, loaded := c.refreshing.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+
+	go func() {
+		defer c.refreshing.Delete(key)
+
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+		if _, err := c.fill(ctx, key, load, false); err != nil {
+			c.onError(key, fmt.Errorf("failed to refresh: %w", err))
+		}
+	}()
+}
+
+// fill はloadで取得した値を保存し、エンコードした値を返します。
+// 分散ロックを取れなかった場合、waitがtrueなら他のインスタンスの書き込みを待ち（来なければ自分で読み込む）、
+// falseなら何もしません（nilを返す）
+func (c *ReadThrough[T]) fill(ctx context.Context, key string, load Loader[T], wait bool) ([]byte, error) {
+	gen := c.l1Generation(key)
+	if c.lockTTL > 0 {
+		unlock, acquired, err := c.store.Lock(ctx, key, c.lockTTL)
+		switch {
+		case err != nil:
+			// ロックできない場合はロックなしで読み込む
+			c.onError(key, fmt.Errorf("failed to lock: %w", err))
+		case acquired:
+			defer unlock()
+		case !wait:
+			return nil, nil
+		default:
+			if data, ok := c.waitForFill(ctx, key, gen); ok {
+				return data, nil
+			}
+		}
+	}
+
+	value, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.codec.Encode(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cache %s: %w", key, err)
+	}
+	if err := c.write(ctx, key, data, value, gen); err != nil {
+		c.onError(key, err)
+	}
+
+	return data, nil
+}
+
+// waitForFill はロックを持つインスタンスがkeyを書き込むまで最大LockTTL待ちます
+func (c *ReadThrough[T]) waitForFill(ctx context.Context, key string, gen uint64) ([]byte, bool) {
+	timer := time.NewTimer(c.lockTTL)
+	defer timer.Stop()
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if data, freshUntil, ok := c.read(ctx, key); ok {
+				c.onHit(key)
+				if value, err := c.codec.Decode(data); err == nil {
+					c.writeL1(key, value, freshUntil, gen)
+				}
+				return data, true
+			}
+		case <-timer.C:
+			return nil, false
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
+
+// read はkeyの値と、soft TTLの期限を返します
+func (c *ReadThrough[T]) read(ctx context.Context, key string) ([]byte, time.Time, bool) {
+	raw, ok, err := c.store.Get(ctx, key)
+	if err != nil {
+		c.onError(key, fmt.Errorf("failed to get: %w", err))
+		return nil, time.Time{}, false
+	}
+	if !ok {
+		return nil, time.Time{}, false
+	}
+
+	freshUntil, data, err := decodeEntry(raw)
+	if err != nil {
+		c.onError(key, err)
+		return nil, time.Time{}, false
+	}
+	return data, time.UnixMilli(freshUntil), true
+}
+
+// write はdata（valueをエンコードしたもの）をstoreに保存し、keyの世代がgenのままならvalueをL1にも入れます
+func (c *ReadThrough[T]) write(ctx context.Context, key string, data []byte, value T, gen uint64) error {
+	ttl := c.ttl(key)
+	if ttl.Hard < ttl.Soft {
+		ttl.Hard = ttl.Soft
+	}
+
+	freshUntil := c.now().Add(ttl.Soft)
+	entry := encodeEntry(freshUntil.UnixMilli(), data)
+	if err := c.store.Set(ctx, key, entry, ttl.Hard); err != nil {
+		return fmt.Errorf("failed to set: %w", err)
+	}
+	if c.hooks.Set != nil {
+		c.hooks.Set(key, ttl)
+	}
+	c.writeL1(key, value, freshUntil, gen)
+	return nil
+}
+
+// readL1 はL1の値の複製を返します。型の違う値（他のキャッシュとキーが衝突した場合）はミスとして削除します
+func (c *ReadThrough[T]) readL1(key string) (T, bool) {
+	var zero T
+	if c.l1 == nil {
+		return zero, false
+	}
+	cached, ok := c.l1.Get(key)
+	if !ok {
+		return zero, false
+	}
+	value, ok := cached.(T)
+	if !ok {
+		c.l1.Delete(key)
+		c.onError(key, fmt.Errorf("unexpected L1 value of type %T", cached))
+		return zero, false
+	}
+	return c.copy(value), true
+}
+
+// l1Generation はL1でのkeyの無効化の世代を返します
+func (c *ReadThrough[T]) l1Generation(key string) uint64 {
+	if c.l1 == nil {
+		return 0
+	}
+	return c.l1.Generation(key)
+}
+
+// writeL1 はsoft TTL内の値だけを、複製してL1に入れます（古い値はstore側で再取得させるため）。
+// 世代がgenから進んでいれば（読み込み中に無効化されていれば）入れません
+func (c *ReadThrough[T]) writeL1(key string, value T, freshUntil time.Time, gen uint64) {
+	if c.l1 == nil {
+		return
+	}
+	c.l1.SetIfGeneration(key, c.copy(value), freshUntil.Sub(c.now()), gen)
+}
+
+func (c *ReadThrough[T]) copy(value T) T {
+	if c.clone == nil {
+		return value
+	}
+	return c.clone(value)
+}
+
+func (c *ReadThrough[T]) onL1Hit(key string) {
+	if c.hooks.L1Hit != nil {
+		c.hooks.L1Hit(key)
+	}
+}
+
+func (c *ReadThrough[T]) onHit(key string) {
+	if c.hooks.Hit != nil {
+		c.hooks.Hit(key)
+	}
+}
+
+func (c *ReadThrough[T]) onStale(key string) {
+	if c.hooks.Stale != nil {
+		c.hooks.Stale(key)
+	}
+}
+
+func (c *ReadThrough[T]) onMiss(key string) {
+	if c.hooks.Miss != nil {
+		c.hooks.Miss(key)
+	}
+}
+
+func (c *ReadThrough[T]) onError(key string, err error) {
+	if c.hooks.Error != nil {
+		c.hooks.Error(key, err)
+	}
+}
+
+// encodeEntry は値の前にバージョン（1バイト）とsoft TTLの期限（UnixMilli、8バイト）を付けます
+func encodeEntry(freshUntil int64, data []byte) []byte {
+	entry := make([]byte, 9, 9+len(data))
+	entry[0] = entryVersion
+	binary.BigEndian.PutUint64(entry[1:9], uint64(freshUntil))
+	return append(entry, data...)
+}
+
+func decodeEntry(entry []byte) (int64, []byte, error) {
+	if len(entry) < 9 || entry[0] != entryVersion {
+		return 0, nil, errInvalidEntry
+	}
+	return int64(binary.BigEndian.Uint64(entry[1:9])), entry[9:], nil
+}
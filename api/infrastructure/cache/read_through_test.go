@@ -0,0 +1,399 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memoryStore はテスト用のStoreです。lockedの間はLockが取得できず、lockErrが設定されていればLockはエラーを返します
+type memoryStore struct {
+	mu      sync.Mutex
+	values  map[string][]byte
+	locked  bool
+	lockErr error
+	sets    int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{values: make(map[string][]byte)}
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	return value, ok, nil
+}
+
+func (s *memoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	s.sets++
+	return nil
+}
+
+func (s *memoryStore) Lock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lockErr != nil {
+		return nil, false, s.lockErr
+	}
+	if s.locked {
+		return nil, false, nil
+	}
+	s.locked = true
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.locked = false
+	}, true, nil
+}
+
+func (s *memoryStore) put(key string, value []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+// fakeClock は手動で進める時計です
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// countingLoader は呼ばれた回数を数え、releaseが閉じられるまで（nilなら待たずに）valueを返します
+type countingLoader struct {
+	calls   atomic.Int32
+	value   string
+	release chan struct{}
+}
+
+func (l *countingLoader) load(ctx context.Context) (string, error) {
+	l.calls.Add(1)
+	if l.release != nil {
+		<-l.release
+	}
+	return l.value, nil
+}
+
+var testTTL = TTL{Soft: time.Minute, Hard: 10 * time.Minute}
+
+func newTestCache(store Store, clock *fakeClock, lockTTL time.Duration, hooks Hooks) *ReadThrough[string] {
+	c := New(store, Options[string]{TTL: FixedTTL(testTTL), LockTTL: lockTTL, Hooks: hooks})
+	c.now = clock.Now
+	return c
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReadThroughCollapsesConcurrentMisses(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	var misses atomic.Int32
+	c := newTestCache(newMemoryStore(), clock, 0, Hooks{Miss: func(string) { misses.Add(1) }})
+	loader := &countingLoader{value: "loaded", release: make(chan struct{})}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make(chan string, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := c.Get(context.Background(), "key", loader.load)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			results <- value
+		}()
+	}
+
+	// 全員がミスしてから読み込みを終わらせる
+	waitFor(t, "all callers to miss", func() bool { return misses.Load() == callers })
+	time.Sleep(10 * time.Millisecond)
+	close(loader.release)
+	wg.Wait()
+	close(results)
+
+	if calls := loader.calls.Load(); calls != 1 {
+		t.Errorf("Expected 1 load for %d concurrent misses, got %d", callers, calls)
+	}
+	for value := range results {
+		if value != "loaded" {
+			t.Errorf("Expected every caller to get the loaded value, got %q", value)
+		}
+	}
+}
+
+func TestReadThroughServesStaleAndRefreshesOnce(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	store := newMemoryStore()
+	var stale atomic.Int32
+	c := newTestCache(store, clock, 0, Hooks{Stale: func(string) { stale.Add(1) }})
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "key", "old"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	clock.Advance(testTTL.Soft + time.Second)
+
+	loader := &countingLoader{value: "new", release: make(chan struct{})}
+	for i := 0; i < 5; i++ {
+		value, err := c.Get(ctx, "key", loader.load)
+		if err != nil || value != "old" {
+			t.Fatalf("Expected the stale value, got %q (%v)", value, err)
+		}
+	}
+	if stale.Load() != 5 {
+		t.Errorf("Expected 5 stale hits, got %d", stale.Load())
+	}
+
+	close(loader.release)
+	waitFor(t, "the background refresh", func() bool {
+		_, refreshing := c.refreshing.Load("key")
+		return loader.calls.Load() == 1 && !refreshing
+	})
+	if calls := loader.calls.Load(); calls != 1 {
+		t.Errorf("Expected exactly 1 background refresh, got %d", calls)
+	}
+
+	// 再取得した値はsoft TTLが延び、読み込みなしで返る
+	value, err := c.Get(ctx, "key", loader.load)
+	if err != nil || value != "new" {
+		t.Errorf("Expected the refreshed value, got %q (%v)", value, err)
+	}
+	if calls := loader.calls.Load(); calls != 1 {
+		t.Errorf("Expected no further loads, got %d", calls)
+	}
+}
+
+func TestReadThroughStaleRefreshSkippedWhenLocked(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	store := newMemoryStore()
+	c := newTestCache(store, clock, time.Second, Hooks{})
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "key", "old"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	clock.Advance(testTTL.Soft + time.Second)
+
+	// 他のインスタンスが再取得中なら、このインスタンスは読み込まない
+	store.locked = true
+	loader := &countingLoader{value: "new"}
+	if value, _ := c.Get(ctx, "key", loader.load); value != "old" {
+		t.Fatalf("Expected the stale value, got %q", value)
+	}
+	waitFor(t, "the background refresh", func() bool {
+		_, refreshing := c.refreshing.Load("key")
+		return !refreshing
+	})
+	if calls := loader.calls.Load(); calls != 0 {
+		t.Errorf("Expected no load while another instance holds the lock, got %d", calls)
+	}
+}
+
+func TestReadThroughLockFallbacks(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("lock error loads without the lock", func(t *testing.T) {
+		store := newMemoryStore()
+		store.lockErr = errors.New("redis down")
+		var errs atomic.Int32
+		c := newTestCache(store, &fakeClock{now: time.Unix(1700000000, 0)}, time.Second, Hooks{Error: func(string, error) { errs.Add(1) }})
+		loader := &countingLoader{value: "loaded"}
+
+		value, err := c.Get(ctx, "key", loader.load)
+		if err != nil || value != "loaded" {
+			t.Fatalf("Expected the loaded value, got %q (%v)", value, err)
+		}
+		if loader.calls.Load() != 1 || errs.Load() != 1 {
+			t.Errorf("Expected 1 load and 1 reported error, got %d loads and %d errors", loader.calls.Load(), errs.Load())
+		}
+	})
+
+	t.Run("waits for the lock holder", func(t *testing.T) {
+		store := newMemoryStore()
+		store.locked = true
+		clock := &fakeClock{now: time.Unix(1700000000, 0)}
+		c := newTestCache(store, clock, time.Second, Hooks{})
+		loader := &countingLoader{value: "mine"}
+
+		// ロックを持つ他のインスタンスが少し後に書き込む
+		other := newTestCache(store, clock, 0, Hooks{})
+		go func() {
+			time.Sleep(2 * lockPollInterval)
+			other.Set(ctx, "key", "theirs")
+		}()
+
+		value, err := c.Get(ctx, "key", loader.load)
+		if err != nil || value != "theirs" {
+			t.Fatalf("Expected the value written by the lock holder, got %q (%v)", value, err)
+		}
+		if calls := loader.calls.Load(); calls != 0 {
+			t.Errorf("Expected no load while waiting for the lock holder, got %d", calls)
+		}
+	})
+
+	t.Run("loads itself when the lock holder never writes", func(t *testing.T) {
+		store := newMemoryStore()
+		store.locked = true
+		c := newTestCache(store, &fakeClock{now: time.Unix(1700000000, 0)}, 2*lockPollInterval, Hooks{})
+		loader := &countingLoader{value: "mine"}
+
+		value, err := c.Get(ctx, "key", loader.load)
+		if err != nil || value != "mine" {
+			t.Fatalf("Expected the value loaded after the wait, got %q (%v)", value, err)
+		}
+		if calls := loader.calls.Load(); calls != 1 {
+			t.Errorf("Expected 1 load after the wait, got %d", calls)
+		}
+	})
+}
+
+func TestReadThroughDecodeErrorEvictsEntry(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	store := newMemoryStore()
+	var errs atomic.Int32
+	c := newTestCache(store, clock, 0, Hooks{Error: func(string, error) { errs.Add(1) }})
+	ctx := context.Background()
+
+	// 形式は正しいが値が壊れているエントリーと、形式の古いエントリー
+	store.put("broken", encodeEntry(clock.Now().Add(time.Hour).UnixMilli(), []byte("{not json")))
+	store.put("legacy", []byte(`"plain json"`))
+
+	for _, key := range []string{"broken", "legacy"} {
+		loader := &countingLoader{value: "reloaded"}
+		value, err := c.Get(ctx, key, loader.load)
+		if err != nil || value != "reloaded" {
+			t.Fatalf("%s: Expected the reloaded value, got %q (%v)", key, value, err)
+		}
+		if calls := loader.calls.Load(); calls != 1 {
+			t.Errorf("%s: Expected 1 load, got %d", key, calls)
+		}
+
+		// 壊れたエントリーは読み込んだ値で置き換わり、次は読み込まない
+		if value, _ := c.Get(ctx, key, loader.load); value != "reloaded" || loader.calls.Load() != 1 {
+			t.Errorf("%s: Expected the replaced entry to be served from the store, got %q after %d loads", key, value, loader.calls.Load())
+		}
+	}
+	if errs.Load() != 2 {
+		t.Errorf("Expected 2 reported errors, got %d", errs.Load())
+	}
+}
+
+func TestReadThroughServesL1WithoutStoreOrDecode(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	store := newMemoryStore()
+	var l1Hits atomic.Int32
+	c := New(store, Options[[]string]{
+		TTL:   FixedTTL(testTTL),
+		L1:    NewLRU(10, time.Minute),
+		Clone: slices.Clone[[]string],
+		Hooks: Hooks{L1Hit: func(string) { l1Hits.Add(1) }},
+	})
+	c.now = clock.Now
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "key", []string{"cached"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// storeの値が読めなくても、L1の値はデコードせずに返る
+	store.put("key", []byte("{not json"))
+
+	for i := 0; i < 2; i++ {
+		value, err := c.Get(ctx, "key", func(context.Context) ([]string, error) {
+			t.Fatal("Expected no load on an L1 hit")
+			return nil, nil
+		})
+		if err != nil || len(value) != 1 || value[0] != "cached" {
+			t.Fatalf("Expected the L1 value, got %v (%v)", value, err)
+		}
+		// 呼び出し側が変更しても、L1の値は変わらない
+		value[0] = "modified"
+	}
+	if l1Hits.Load() != 2 {
+		t.Errorf("Expected 2 L1 hits, got %d", l1Hits.Load())
+	}
+}
+
+func TestReadThroughL1EntryOfAnotherTypeIsMiss(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	l1 := NewLRU(10, time.Minute)
+	c := New(newMemoryStore(), Options[string]{TTL: FixedTTL(testTTL), L1: l1})
+	c.now = clock.Now
+	ctx := context.Background()
+
+	l1.Set("key", 42, time.Minute)
+	loader := &countingLoader{value: "reloaded"}
+	value, err := c.Get(ctx, "key", loader.load)
+	if err != nil || value != "reloaded" {
+		t.Fatalf("Expected the reloaded value, got %q (%v)", value, err)
+	}
+
+	cached, ok := l1.Get("key")
+	if !ok || cached != "reloaded" {
+		t.Errorf("Expected the L1 entry of another type to be replaced, got %v (%t)", cached, ok)
+	}
+}
+
+func TestReadThroughSkipsL1FillInvalidatedDuringLoad(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	l1 := NewLRU(10, time.Minute)
+	c := New(newMemoryStore(), Options[string]{TTL: FixedTTL(testTTL), L1: l1})
+	c.now = clock.Now
+	ctx := context.Background()
+
+	loader := &countingLoader{value: "old", release: make(chan struct{})}
+	done := make(chan string)
+	go func() {
+		value, _ := c.Get(ctx, "key", loader.load)
+		done <- value
+	}()
+
+	// 読み込み中に無効化の通知が届く
+	waitFor(t, "the load to start", func() bool { return loader.calls.Load() == 1 })
+	l1.Delete("key")
+	close(loader.release)
+	if value := <-done; value != "old" {
+		t.Fatalf("Expected the loaded value, got %q", value)
+	}
+
+	if _, ok := l1.Get("key"); ok {
+		t.Error("Expected the value loaded before the invalidation not to be put in L1")
+	}
+
+	// 無効化の後に始まった読み取りはL1に入れる
+	if _, err := c.Get(ctx, "key", loader.load); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := l1.Get("key"); !ok {
+		t.Error("Expected a read started after the invalidation to fill L1")
+	}
+}
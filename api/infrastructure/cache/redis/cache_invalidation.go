@@ -0,0 +1,142 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/infrastructure/cache"
+)
+
+const (
+	// cacheInvalidationChannel は削除したキャッシュキー・進めた世代番号のキー（JSONの配列）を各インスタンスに通知するチャンネル
+	cacheInvalidationChannel = "cache:invalidate"
+	// listVersionL1TTL はL1に置いた一覧の世代番号を使う最大の時間（LRUのTTLが短ければそちら）。通知を取りこぼしてもこの時間で読み直す
+	listVersionL1TTL = time.Minute
+	// subscribeRetryInterval は購読でエラーになった後、再度受信するまでの間隔
+	subscribeRetryInterval = time.Second
+)
+
+// deleteCacheKeys はキーを削除し、全インスタンス（自分を含む）のL1からも削除されるよう通知します
+func deleteCacheKeys(ctx context.Context, redisClient *redis.Client, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	pipe := redisClient.TxPipeline()
+	queueDeleteCacheKeys(ctx, pipe, keys...)
+	pipe.Exec(ctx)
+}
+
+// queueDeleteCacheKeys はpipeにキーの削除とその通知を追加します
+func queueDeleteCacheKeys(ctx context.Context, pipe redis.Pipeliner, keys ...string) {
+	payload, _ := json.Marshal(keys)
+	pipe.Del(ctx, keys...)
+	pipe.Publish(ctx, cacheInvalidationChannel, payload)
+}
+
+// incrListVersions は一覧キャッシュの世代番号を進め、全インスタンス（自分を含む）のL1に置いた世代番号も削除されるよう通知します
+func incrListVersions(ctx context.Context, redisClient *redis.Client, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	pipe := redisClient.TxPipeline()
+	queueIncrListVersions(ctx, pipe, keys...)
+	pipe.Exec(ctx)
+}
+
+// queueIncrListVersions はpipeに世代番号の更新とその通知を追加します
+func queueIncrListVersions(ctx context.Context, pipe redis.Pipeliner, keys ...string) {
+	payload, _ := json.Marshal(keys)
+	for _, key := range keys {
+		pipe.Incr(ctx, key)
+	}
+	pipe.Publish(ctx, cacheInvalidationChannel, payload)
+}
+
+// getListVersions は一覧キャッシュの世代番号（未設定は"0"）をkeysの順に返します。
+// l1があればそこに置いた世代番号を使い、無いものだけRedisから読んでl1に置きます（L1のヒット時にRedisへ問い合わせないため）。
+// 読み込み中に世代が進んだ（通知を受けた）キーは、古い世代番号をl1に置きません。
+// Redisから読めない場合はエラーを返します（古い世代のキャッシュを返さないよう、呼び出し側はキャッシュを使わずに読む）
+func getListVersions(ctx context.Context, redisClient *redis.Client, l1 *cache.LRU, keys ...string) ([]string, error) {
+	versions := make([]string, len(keys))
+	gens := make([]uint64, len(keys))
+	var missing []int
+	for i, key := range keys {
+		if l1 != nil {
+			if cached, ok := l1.Get(key); ok {
+				if version, ok := cached.(string); ok {
+					versions[i] = version
+					continue
+				}
+			}
+			gens[i] = l1.Generation(key)
+		}
+		versions[i] = "0"
+		missing = append(missing, i)
+	}
+	if len(missing) == 0 {
+		return versions, nil
+	}
+
+	missingKeys := make([]string, len(missing))
+	for j, i := range missing {
+		missingKeys[j] = keys[i]
+	}
+	values, err := redisClient.MGet(ctx, missingKeys...).Result()
+	if err != nil {
+		log.Printf("Warning: failed to get list versions, bypassing list caches: %v", err)
+		return nil, fmt.Errorf("failed to get list versions: %w", err)
+	}
+	for j, i := range missing {
+		if version, ok := values[j].(string); ok {
+			versions[i] = version
+		}
+		if l1 != nil {
+			l1.SetIfGeneration(keys[i], versions[i], listVersionL1TTL, gens[i])
+		}
+	}
+	return versions, nil
+}
+
+// SubscribeL1Invalidations receives deleted cache keys and bumped list versions from every instance
+// and drops them from l1 until ctx is done.
+// Messages published while disconnected are lost, so l1 is cleared every time the subscription is (re)established.
+func SubscribeL1Invalidations(ctx context.Context, redisClient *redis.Client, l1 *cache.LRU) {
+	pubsub := redisClient.Subscribe(ctx, cacheInvalidationChannel)
+	defer pubsub.Close()
+
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Warning: cache invalidation subscription: %v", err)
+			select {
+			case <-time.After(subscribeRetryInterval):
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			l1.Clear()
+			log.Printf("⚠ L1 Cache CLEAR (%s %s)", m.Kind, m.Channel)
+		case *redis.Message:
+			var keys []string
+			if err := json.Unmarshal([]byte(m.Payload), &keys); err != nil {
+				log.Printf("Warning: invalid cache invalidation message: %v", err)
+				l1.Clear()
+				continue
+			}
+			l1.Delete(keys...)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package redis
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/infrastructure/cache"
+)
+
+func waitUntil(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func countCommands(f *fakeRedis, name string) int {
+	n := 0
+	for _, command := range f.executed() {
+		if command == name {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSubscribeL1Invalidations(t *testing.T) {
+	fake, redisClient := newFakeRedis(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	l1 := cache.NewLRU(100, time.Minute)
+	isCached := func(key string) bool {
+		_, ok := l1.Get(key)
+		return ok
+	}
+
+	// 購読を始めると、それまでの通知を取りこぼしている可能性があるためL1を空にする
+	l1.Set("before", "value", time.Minute)
+	go SubscribeL1Invalidations(ctx, redisClient, l1)
+	waitUntil(t, "the subscription to clear L1", func() bool { return l1.Len() == 0 })
+
+	t.Run("deleted keys", func(t *testing.T) {
+		for _, key := range []string{"a", "b", "c"} {
+			l1.Set(key, "value", time.Minute)
+		}
+		deleteCacheKeys(ctx, redisClient, "a", "b")
+
+		waitUntil(t, "the deleted keys to leave L1", func() bool { return !isCached("a") && !isCached("b") })
+		if !isCached("c") {
+			t.Error("Expected keys that were not deleted to stay in L1")
+		}
+	})
+
+	t.Run("list versions", func(t *testing.T) {
+		keys := []string{postListVersionKey, getPostScopeVersionKey(postScopeAll)}
+		if versions, err := getListVersions(ctx, redisClient, l1, keys...); err != nil || !slices.Equal(versions, []string{"0", "0"}) {
+			t.Fatalf("Expected unset versions to be 0, got %v (%v)", versions, err)
+		}
+
+		// 2回目以降はL1の世代番号を使い、Redisに問い合わせない
+		mgets := countCommands(fake, "mget")
+		getListVersions(ctx, redisClient, l1, keys...)
+		if n := countCommands(fake, "mget"); n != mgets {
+			t.Errorf("Expected versions to be served from L1, got %d more MGET", n-mgets)
+		}
+
+		invalidatePostListCaches(ctx, redisClient)
+		waitUntil(t, "the bumped version to leave L1", func() bool { return !isCached(postListVersionKey) })
+		if !isCached(getPostScopeVersionKey(postScopeAll)) {
+			t.Error("Expected versions that were not bumped to stay in L1")
+		}
+		if versions, err := getListVersions(ctx, redisClient, l1, keys...); err != nil || !slices.Equal(versions, []string{"1", "0"}) {
+			t.Errorf("Expected the bumped version to be read from Redis, got %v (%v)", versions, err)
+		}
+	})
+
+	t.Run("invalid message", func(t *testing.T) {
+		l1.Set("a", "value", time.Minute)
+		redisClient.Publish(ctx, cacheInvalidationChannel, "{not json")
+
+		waitUntil(t, "the invalid message to clear L1", func() bool { return l1.Len() == 0 })
+	})
+}
+
+func TestGetListVersionsWithoutL1(t *testing.T) {
+	fake, redisClient := newFakeRedis(t)
+	ctx := context.Background()
+
+	redisClient.Set(ctx, userListVersionKey, "3", 0)
+	for i := 0; i < 2; i++ {
+		if versions, err := getListVersions(ctx, redisClient, nil, userListVersionKey); err != nil || !slices.Equal(versions, []string{"3"}) {
+			t.Fatalf("Expected version 3, got %v (%v)", versions, err)
+		}
+	}
+	if n := countCommands(fake, "mget"); n != 2 {
+		t.Errorf("Expected every call to read Redis without L1, got %d MGET", n)
+	}
+}
+
+// totalCountPostRepository は公開中の投稿数だけを返します
+type totalCountPostRepository struct {
+	domain.PostRepository
+	total int64
+	calls int
+}
+
+func (r *totalCountPostRepository) GetTotalCount(ctx context.Context) (int64, error) {
+	r.calls++
+	return r.total, nil
+}
+
+func TestListCachesAreBypassedWhenVersionsAreUnavailable(t *testing.T) {
+	// 接続できないRedis
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	t.Cleanup(func() { redisClient.Close() })
+	ctx := context.Background()
+
+	l1 := cache.NewLRU(100, time.Minute)
+	if versions, err := getListVersions(ctx, redisClient, l1, postListVersionKey); err == nil {
+		t.Fatalf("Expected an error instead of defaulting to version 0, got %v", versions)
+	}
+	if l1.Len() != 0 {
+		t.Errorf("Expected nothing to be put in L1, got %d entries", l1.Len())
+	}
+
+	// 古い世代のキャッシュを返さず、キャッシュを使わずに読む
+	base := &totalCountPostRepository{total: 7}
+	config := DefaultReadCacheConfig()
+	config.L1 = l1
+	repo := NewCachedPostRepository(base, redisClient, config)
+	for i := 0; i < 2; i++ {
+		total, err := repo.GetTotalCount(ctx)
+		if err != nil || total != 7 {
+			t.Fatalf("Expected the total count from the base repository, got %d (%v)", total, err)
+		}
+	}
+	if base.calls != 2 {
+		t.Errorf("Expected every call to bypass the cache, got %d base calls", base.calls)
+	}
+}
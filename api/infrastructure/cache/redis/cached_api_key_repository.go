@@ -0,0 +1,94 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/infrastructure/cache"
+)
+
+// cachedAPIKeyRepository はAPIキー認証時のプレフィックス検索をRedisにキャッシュするDecoratorです。
+// 失効・last_used_at更新時にキャッシュを削除し、次のリクエストでMySQLから読み直します
+type cachedAPIKeyRepository struct {
+	baseRepo    domain.APIKeyRepository
+	redisClient *redis.Client
+	keys        *cache.ReadThrough[*cachedAPIKey]
+}
+
+// cachedAPIKey はSecretHashを含めてキャッシュするための構造体
+// （domain.APIKeyはレスポンスに使うためSecretHashをJSONに出さない）
+type cachedAPIKey struct {
+	domain.APIKey
+	SecretHash string `json:"secretHash"`
+}
+
+// NewCachedAPIKeyRepository creates an API key repository that caches lookups by prefix in Redis
+func NewCachedAPIKeyRepository(baseRepo domain.APIKeyRepository, redisClient *redis.Client, config ReadCacheConfig) domain.APIKeyRepository {
+	return &cachedAPIKeyRepository{
+		baseRepo:    baseRepo,
+		redisClient: redisClient,
+		keys:        newReadThrough(redisClient, config, clonePointer[cachedAPIKey]),
+	}
+}
+
+func (r *cachedAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	return r.baseRepo.Create(ctx, key)
+}
+
+func (r *cachedAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	entry, err := r.keys.Get(ctx, getAPIKeyCacheKey(prefix), func(ctx context.Context) (*cachedAPIKey, error) {
+		key, err := r.baseRepo.FindByPrefix(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		return &cachedAPIKey{APIKey: *key, SecretHash: key.SecretHash}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	key := entry.APIKey
+	key.SecretHash = entry.SecretHash
+	return &key, nil
+}
+
+func (r *cachedAPIKeyRepository) FindByID(ctx context.Context, id int64) (*domain.APIKey, error) {
+	return r.baseRepo.FindByID(ctx, id)
+}
+
+func (r *cachedAPIKeyRepository) FindByUserID(ctx context.Context, userID int64) ([]domain.APIKey, error) {
+	return r.baseRepo.FindByUserID(ctx, userID)
+}
+
+func (r *cachedAPIKeyRepository) Revoke(ctx context.Context, key *domain.APIKey) error {
+	if err := r.baseRepo.Revoke(ctx, key); err != nil {
+		return err
+	}
+
+	// 失効したキーが使われ続けないよう即座に削除する
+	cacheKey := getAPIKeyCacheKey(key.Prefix)
+	deleteCacheKeys(ctx, r.redisClient, cacheKey)
+	log.Printf("⚠ Redis Cache INVALIDATE: %s (API key revoked: ID=%d)", cacheKey, key.ID)
+
+	return nil
+}
+
+func (r *cachedAPIKeyRepository) TouchLastUsed(ctx context.Context, key *domain.APIKey, at time.Time) error {
+	if err := r.baseRepo.TouchLastUsed(ctx, key, at); err != nil {
+		return err
+	}
+
+	cacheKey := getAPIKeyCacheKey(key.Prefix)
+	deleteCacheKeys(ctx, r.redisClient, cacheKey)
+	log.Printf("⚠ Redis Cache INVALIDATE: %s (API key used: ID=%d)", cacheKey, key.ID)
+
+	return nil
+}
+
+func getAPIKeyCacheKey(prefix string) string {
+	return fmt.Sprintf("api_key:%s", prefix)
+}
@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// cachedAuthRepository はユーザー登録時に一覧キャッシュを、ログイン時にユーザーキャッシュ（lastLoginAt）を無効化するDecoratorです
+type cachedAuthRepository struct {
+	baseRepo    domain.AuthRepository
+	redisClient *redis.Client
+}
+
+// NewCachedAuthRepository creates an auth repository that invalidates user caches on registration and login
+func NewCachedAuthRepository(baseRepo domain.AuthRepository, redisClient *redis.Client) domain.AuthRepository {
+	return &cachedAuthRepository{
+		baseRepo:    baseRepo,
+		redisClient: redisClient,
+	}
+}
+
+func (r *cachedAuthRepository) CreateUser(ctx context.Context, user *domain.User, passwordHash string) error {
+	err := r.baseRepo.CreateUser(ctx, user, passwordHash)
+	if err != nil {
+		return err
+	}
+
+	invalidateUserListCaches(ctx, r.redisClient)
+	log.Printf("⚠ Redis Cache INVALIDATE: users:list:* (User registered)")
+	return nil
+}
+
+func (r *cachedAuthRepository) FindCredentials(ctx context.Context, login string) (*domain.UserCredentials, error) {
+	return r.baseRepo.FindCredentials(ctx, login)
+}
+
+func (r *cachedAuthRepository) UpdateLastLogin(ctx context.Context, userID int64, at time.Time) error {
+	if err := r.baseRepo.UpdateLastLogin(ctx, userID, at); err != nil {
+		return err
+	}
+
+	// 一覧はログインのたびに作り直さず、TTLで反映する
+	key := getCacheKey(userID)
+	deleteCacheKeys(ctx, r.redisClient, key)
+	log.Printf("⚠ Redis Cache INVALIDATE: %s (Logged in)", key)
+	return nil
+}
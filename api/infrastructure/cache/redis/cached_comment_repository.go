@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"context"
+	"log"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// cachedCommentRepository はコメントの書き込み時に投稿キャッシュを無効化するDecoratorです。
+// 投稿詳細キャッシュ（post:{id}, post:slug:{slug}）は最新コメントとcomment_countを含むため、
+// 承認状態が変わったら該当投稿と一覧系キャッシュを削除します。
+// ユーザー詳細は最近のコメントを含むため、コメント投稿者の詳細キャッシュも削除します。
+// コメントツリーの読み取りはキャッシュせずbaseRepoへ委譲します。
+type cachedCommentRepository struct {
+	baseRepo    domain.CommentRepository
+	postRepo    domain.PostRepository // スラッグ取得用（キャッシュなしの実装を渡す）
+	redisClient *redis.Client
+}
+
+// NewCachedCommentRepository creates a comment repository that invalidates post caches on writes
+func NewCachedCommentRepository(baseRepo domain.CommentRepository, postRepo domain.PostRepository, redisClient *redis.Client) domain.CommentRepository {
+	return &cachedCommentRepository{
+		baseRepo:    baseRepo,
+		postRepo:    postRepo,
+		redisClient: redisClient,
+	}
+}
+
+func (r *cachedCommentRepository) FindByID(ctx context.Context, id int64) (*domain.Comment, error) {
+	return r.baseRepo.FindByID(ctx, id)
+}
+
+func (r *cachedCommentRepository) FindApprovedThreadByPostID(ctx context.Context, postID int64, limit, offset int) ([]domain.CommentWithAuthor, error) {
+	return r.baseRepo.FindApprovedThreadByPostID(ctx, postID, limit, offset)
+}
+
+func (r *cachedCommentRepository) CountApprovedRootsByPostID(ctx context.Context, postID int64) (int64, error) {
+	return r.baseRepo.CountApprovedRootsByPostID(ctx, postID)
+}
+
+func (r *cachedCommentRepository) FindByStatus(ctx context.Context, status string, limit, offset int) ([]domain.CommentWithAuthor, error) {
+	return r.baseRepo.FindByStatus(ctx, status, limit, offset)
+}
+
+func (r *cachedCommentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	err := r.baseRepo.Create(ctx, comment)
+	if err != nil {
+		return err
+	}
+
+	// 未承認コメントは投稿キャッシュに影響しない
+	if comment.Status == domain.CommentStatusApproved {
+		r.invalidatePost(ctx, comment.PostID)
+	}
+	r.invalidateAuthorDetail(ctx, comment.UserID)
+
+	return nil
+}
+
+func (r *cachedCommentRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	comment, err := r.baseRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = r.baseRepo.UpdateStatus(ctx, id, status)
+	if err != nil {
+		return err
+	}
+
+	if comment.Status != status && (comment.Status == domain.CommentStatusApproved || status == domain.CommentStatusApproved) {
+		r.invalidatePost(ctx, comment.PostID)
+	}
+	if comment.Status != status {
+		r.invalidateAuthorDetail(ctx, comment.UserID)
+	}
+
+	return nil
+}
+
+func (r *cachedCommentRepository) invalidatePost(ctx context.Context, postID int64) {
+	// 件数は公開中の投稿の一覧にのみ表示されるため、非公開の投稿は詳細のみ削除する
+	post, err := r.postRepo.FindByIDWithDetails(ctx, postID)
+	if err != nil {
+		post = nil
+	}
+
+	scopes := invalidatePostCaches(ctx, r.redisClient, postID, post)
+	log.Printf("⚠ Redis Cache INVALIDATE: post:%d, posts scopes %v (Comments changed)", postID, scopes)
+}
+
+// invalidateAuthorDetail はコメント投稿者のユーザー詳細（最近のコメント・コメント数を含む）のキャッシュを削除します
+func (r *cachedCommentRepository) invalidateAuthorDetail(ctx context.Context, userID int64) {
+	key := getUserDetailCacheKey(userID)
+	deleteCacheKeys(ctx, r.redisClient, key)
+	log.Printf("⚠ Redis Cache INVALIDATE: %s (Comments changed)", key)
+}
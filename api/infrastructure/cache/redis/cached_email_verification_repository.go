@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// cachedEmailVerificationRepository はメール認証完了時にユーザー・ユーザー詳細・一覧キャッシュを無効化するDecoratorです
+// （いずれもemailVerifiedを含み、一覧はemailVerifiedで絞り込めるため）
+type cachedEmailVerificationRepository struct {
+	baseRepo    domain.EmailVerificationRepository
+	redisClient *redis.Client
+}
+
+// NewCachedEmailVerificationRepository creates an email verification repository that invalidates user, user detail and list caches
+func NewCachedEmailVerificationRepository(baseRepo domain.EmailVerificationRepository, redisClient *redis.Client) domain.EmailVerificationRepository {
+	return &cachedEmailVerificationRepository{
+		baseRepo:    baseRepo,
+		redisClient: redisClient,
+	}
+}
+
+func (r *cachedEmailVerificationRepository) Create(ctx context.Context, token *domain.EmailVerificationToken) error {
+	return r.baseRepo.Create(ctx, token)
+}
+
+func (r *cachedEmailVerificationRepository) Consume(ctx context.Context, tokenHash string, now time.Time) (int64, error) {
+	userID, err := r.baseRepo.Consume(ctx, tokenHash, now)
+	if err != nil {
+		return 0, err
+	}
+
+	deleteCacheKeys(ctx, r.redisClient, getCacheKey(userID), getUserDetailCacheKey(userID))
+	invalidateUserListCaches(ctx, r.redisClient)
+	log.Printf("⚠ Redis Cache INVALIDATE: user:%d, user:%d:detail, users:list:* (Email verified)", userID, userID)
+	return userID, nil
+}
+
+func (r *cachedEmailVerificationRepository) IsEmailVerified(ctx context.Context, userID int64) (bool, error) {
+	return r.baseRepo.IsEmailVerified(ctx, userID)
+}
@@ -0,0 +1,80 @@
+package redis
+
+import (
+	"context"
+	"log"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// cachedFollowRepository はフォロー関係の変更時にユーザー詳細キャッシュを無効化するDecoratorです。
+// ユーザー詳細（FollowStats）はフォロワー数・フォロー中数を含むため、
+// フォロー・フォロー解除が成功したら両ユーザーの詳細キャッシュと、フォローした側のタイムラインを削除します。
+type cachedFollowRepository struct {
+	baseRepo    domain.FollowRepository
+	redisClient *redis.Client
+}
+
+// NewCachedFollowRepository creates a follow repository that invalidates user detail caches on writes
+func NewCachedFollowRepository(baseRepo domain.FollowRepository, redisClient *redis.Client) domain.FollowRepository {
+	return &cachedFollowRepository{
+		baseRepo:    baseRepo,
+		redisClient: redisClient,
+	}
+}
+
+func (r *cachedFollowRepository) Create(ctx context.Context, followerID, followingID int64) error {
+	err := r.baseRepo.Create(ctx, followerID, followingID)
+	if err != nil {
+		return err
+	}
+
+	r.invalidateUserDetails(ctx, followerID, followingID)
+	r.invalidateFeed(ctx, followerID)
+	return nil
+}
+
+func (r *cachedFollowRepository) Delete(ctx context.Context, followerID, followingID int64) error {
+	err := r.baseRepo.Delete(ctx, followerID, followingID)
+	if err != nil {
+		return err
+	}
+
+	r.invalidateUserDetails(ctx, followerID, followingID)
+	r.invalidateFeed(ctx, followerID)
+	return nil
+}
+
+func (r *cachedFollowRepository) Exists(ctx context.Context, followerID, followingID int64) (bool, error) {
+	return r.baseRepo.Exists(ctx, followerID, followingID)
+}
+
+func (r *cachedFollowRepository) FindFollowers(ctx context.Context, userID int64, after *domain.FollowCursor, limit int) ([]domain.FollowUser, error) {
+	return r.baseRepo.FindFollowers(ctx, userID, after, limit)
+}
+
+func (r *cachedFollowRepository) FindFollowing(ctx context.Context, userID int64, after *domain.FollowCursor, limit int) ([]domain.FollowUser, error) {
+	return r.baseRepo.FindFollowing(ctx, userID, after, limit)
+}
+
+func (r *cachedFollowRepository) FindFollowerIDs(ctx context.Context, userID int64) ([]int64, error) {
+	return r.baseRepo.FindFollowerIDs(ctx, userID)
+}
+
+func (r *cachedFollowRepository) invalidateUserDetails(ctx context.Context, userIDs ...int64) {
+	keys := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		keys[i] = getUserDetailCacheKey(id)
+	}
+	deleteCacheKeys(ctx, r.redisClient, keys...)
+	log.Printf("⚠ Redis Cache INVALIDATE: %v (Follow relation changed)", keys)
+}
+
+// invalidateFeed はフォロー先が変わったユーザーのタイムライン（fan-out-on-write）を削除します。
+// 次回の読み取り時にMySQLから再構築されます
+func (r *cachedFollowRepository) invalidateFeed(ctx context.Context, followerID int64) {
+	key := getFeedCacheKey(getFeedVersion(ctx, r.redisClient), followerID)
+	r.redisClient.Del(ctx, key)
+	log.Printf("⚠ Redis Cache INVALIDATE: %s (Follow relation changed)", key)
+}
@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"context"
+	"log"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// cachedLikeRepository はいいねの変更時に投稿キャッシュを無効化するDecoratorです。
+// 投稿詳細・一覧キャッシュはlike_count（投稿と最新コメント）を含むため、
+// 投稿・コメントへのいいねが変わったら該当投稿と一覧系キャッシュを削除します。
+type cachedLikeRepository struct {
+	baseRepo    domain.LikeRepository
+	postRepo    domain.PostRepository    // スラッグ取得用（キャッシュなしの実装を渡す）
+	commentRepo domain.CommentRepository // コメントの投稿ID取得用
+	redisClient *redis.Client
+}
+
+// NewCachedLikeRepository creates a like repository that invalidates post caches on writes
+func NewCachedLikeRepository(baseRepo domain.LikeRepository, postRepo domain.PostRepository, commentRepo domain.CommentRepository, redisClient *redis.Client) domain.LikeRepository {
+	return &cachedLikeRepository{
+		baseRepo:    baseRepo,
+		postRepo:    postRepo,
+		commentRepo: commentRepo,
+		redisClient: redisClient,
+	}
+}
+
+func (r *cachedLikeRepository) Like(ctx context.Context, userID int64, likeableType string, likeableID int64) (*domain.LikeStatus, error) {
+	status, err := r.baseRepo.Like(ctx, userID, likeableType, likeableID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.invalidate(ctx, likeableType, likeableID)
+	return status, nil
+}
+
+func (r *cachedLikeRepository) Unlike(ctx context.Context, userID int64, likeableType string, likeableID int64) (*domain.LikeStatus, error) {
+	status, err := r.baseRepo.Unlike(ctx, userID, likeableType, likeableID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.invalidate(ctx, likeableType, likeableID)
+	return status, nil
+}
+
+func (r *cachedLikeRepository) FindLikers(ctx context.Context, likeableType string, likeableID int64, limit, offset int) ([]domain.Liker, error) {
+	return r.baseRepo.FindLikers(ctx, likeableType, likeableID, limit, offset)
+}
+
+func (r *cachedLikeRepository) FindLikedIDs(ctx context.Context, userID int64, likeableType string, likeableIDs []int64) (map[int64]bool, error) {
+	return r.baseRepo.FindLikedIDs(ctx, userID, likeableType, likeableIDs)
+}
+
+func (r *cachedLikeRepository) invalidate(ctx context.Context, likeableType string, likeableID int64) {
+	postID := likeableID
+	if likeableType == domain.LikeableTypeComment {
+		comment, err := r.commentRepo.FindByID(ctx, likeableID)
+		if err != nil {
+			return
+		}
+		postID = comment.PostID
+	}
+
+	// 件数は公開中の投稿の一覧にのみ表示されるため、非公開の投稿は詳細のみ削除する
+	post, err := r.postRepo.FindByIDWithDetails(ctx, postID)
+	if err != nil {
+		post = nil
+	}
+
+	scopes := invalidatePostCaches(ctx, r.redisClient, postID, post)
+	log.Printf("⚠ Redis Cache INVALIDATE: post:%d, posts scopes %v (Likes changed)", postID, scopes)
+}
@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"context"
+	"log"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// cachedNotificationRepository は通知の変更時にユーザー詳細キャッシュを無効化するDecoratorです。
+// ユーザー詳細は未読通知（最新10件）を含むため、作成・既読化のたびに通知先ユーザーの詳細キャッシュを削除します。
+type cachedNotificationRepository struct {
+	baseRepo    domain.NotificationRepository
+	redisClient *redis.Client
+}
+
+// NewCachedNotificationRepository creates a notification repository that invalidates user detail caches on writes
+func NewCachedNotificationRepository(baseRepo domain.NotificationRepository, redisClient *redis.Client) domain.NotificationRepository {
+	return &cachedNotificationRepository{
+		baseRepo:    baseRepo,
+		redisClient: redisClient,
+	}
+}
+
+func (r *cachedNotificationRepository) Create(ctx context.Context, userID int64, notification *domain.UserNotification) error {
+	err := r.baseRepo.Create(ctx, userID, notification)
+	if err != nil {
+		return err
+	}
+
+	r.invalidateUserDetail(ctx, userID)
+	return nil
+}
+
+func (r *cachedNotificationRepository) FindByUserID(ctx context.Context, userID int64, filter string, limit, offset int) ([]domain.UserNotification, error) {
+	return r.baseRepo.FindByUserID(ctx, userID, filter, limit, offset)
+}
+
+func (r *cachedNotificationRepository) CountByUserID(ctx context.Context, userID int64, filter string) (int64, error) {
+	return r.baseRepo.CountByUserID(ctx, userID, filter)
+}
+
+func (r *cachedNotificationRepository) FindByID(ctx context.Context, userID, id int64) (*domain.UserNotification, error) {
+	return r.baseRepo.FindByID(ctx, userID, id)
+}
+
+func (r *cachedNotificationRepository) MarkRead(ctx context.Context, userID, id int64) error {
+	err := r.baseRepo.MarkRead(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	r.invalidateUserDetail(ctx, userID)
+	return nil
+}
+
+func (r *cachedNotificationRepository) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	updated, err := r.baseRepo.MarkAllRead(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if updated > 0 {
+		r.invalidateUserDetail(ctx, userID)
+	}
+	return updated, nil
+}
+
+func (r *cachedNotificationRepository) FindAfterID(ctx context.Context, userID, afterID int64, limit int) ([]domain.UserNotification, error) {
+	return r.baseRepo.FindAfterID(ctx, userID, afterID, limit)
+}
+
+func (r *cachedNotificationRepository) invalidateUserDetail(ctx context.Context, userID int64) {
+	key := getUserDetailCacheKey(userID)
+	deleteCacheKeys(ctx, r.redisClient, key)
+	log.Printf("⚠ Redis Cache INVALIDATE: %s (Notifications changed)", key)
+}
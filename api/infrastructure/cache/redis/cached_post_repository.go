@@ -2,247 +2,409 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"time"
+	"slices"
+	"sort"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/infrastructure/cache"
 )
 
 type cachedPostRepository struct {
 	baseRepo    domain.PostRepository
 	redisClient *redis.Client
-	ctx         context.Context
-	ttl         time.Duration
+	config      ReadCacheConfig
+
+	lists    *cache.ReadThrough[[]domain.PostWithDetails]
+	details  *cache.ReadThrough[*domain.PostWithDetails]
+	searches *cache.ReadThrough[[]domain.PostSearchResult]
+	counts   *cache.ReadThrough[int64]
 }
 
 // NewCachedPostRepository creates a new cached post repository
-func NewCachedPostRepository(baseRepo domain.PostRepository, redisClient *redis.Client) domain.PostRepository {
+func NewCachedPostRepository(baseRepo domain.PostRepository, redisClient *redis.Client, config ReadCacheConfig) domain.PostRepository {
 	return &cachedPostRepository{
 		baseRepo:    baseRepo,
 		redisClient: redisClient,
-		ctx:         context.Background(),
-		ttl:         5 * time.Minute, // Cache TTL: 5 minutes
+		config:      config,
+		lists:       newReadThrough(redisClient, config, slices.Clone[[]domain.PostWithDetails]),
+		details:     newReadThrough(redisClient, config, clonePointer[domain.PostWithDetails]),
+		searches:    newReadThrough(redisClient, config, slices.Clone[[]domain.PostSearchResult]),
+		counts:      newReadThrough[int64](redisClient, config, nil),
 	}
 }
 
 func (r *cachedPostRepository) FindAllWithDetails(ctx context.Context, limit, offset int) ([]domain.PostWithDetails, error) {
-	cacheKey := fmt.Sprintf("posts:all:limit=%d:offset=%d", limit, offset)
-
-	// Try to get from cache
-	cached, err := r.redisClient.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var posts []domain.PostWithDetails
-		if err := json.Unmarshal([]byte(cached), &posts); err == nil {
-			log.Printf("✓ Redis Cache HIT: %s (4-table JOIN cached)", cacheKey)
-			return posts, nil
-		}
-	}
-
-	// Cache miss, get from database
-	log.Printf("✗ Redis Cache MISS: %s - Fetching from MySQL (4-table JOIN)", cacheKey)
-	posts, err := r.baseRepo.FindAllWithDetails(ctx, limit, offset)
+	cacheKey, err := getPostListCacheKey(ctx, r.redisClient, r.config.L1, postScopeAll, fmt.Sprintf("limit=%d:offset=%d", limit, offset))
 	if err != nil {
-		return nil, err
+		return r.baseRepo.FindAllWithDetails(ctx, limit, offset)
 	}
 
-	// Store in cache
-	data, _ := json.Marshal(posts)
-	r.redisClient.Set(ctx, cacheKey, data, r.ttl)
-	log.Printf("→ Redis Cache SET: %s (TTL: %v)", cacheKey, r.ttl)
-
-	return posts, nil
+	return r.lists.Get(ctx, cacheKey, func(ctx context.Context) ([]domain.PostWithDetails, error) {
+		return r.baseRepo.FindAllWithDetails(ctx, limit, offset)
+	})
 }
 
 func (r *cachedPostRepository) FindByIDWithDetails(ctx context.Context, id int64) (*domain.PostWithDetails, error) {
 	cacheKey := getPostCacheKey(id)
 
-	// Try to get from cache
-	cached, err := r.redisClient.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var post domain.PostWithDetails
-		if err := json.Unmarshal([]byte(cached), &post); err == nil {
-			log.Printf("✓ Redis Cache HIT: %s (multi-table JOIN)", cacheKey)
-			return &post, nil
+	return r.details.Get(ctx, cacheKey, func(ctx context.Context) (*domain.PostWithDetails, error) {
+		post, err := r.baseRepo.FindByIDWithDetails(ctx, id)
+		if err != nil {
+			return nil, err
 		}
-	}
+		r.indexPostDetail(ctx, cacheKey, post)
+		return post, nil
+	})
+}
 
-	// Cache miss, get from database
-	log.Printf("✗ Redis Cache MISS: %s - Fetching from MySQL (multi-table JOIN)", cacheKey)
-	post, err := r.baseRepo.FindByIDWithDetails(ctx, id)
+func (r *cachedPostRepository) FindBySlugWithDetails(ctx context.Context, slug string) (*domain.PostWithDetails, error) {
+	cacheKey := getPostSlugCacheKey(slug)
+
+	return r.details.Get(ctx, cacheKey, func(ctx context.Context) (*domain.PostWithDetails, error) {
+		post, err := r.baseRepo.FindBySlugWithDetails(ctx, slug)
+		if err != nil {
+			return nil, err
+		}
+		r.indexPostDetail(ctx, cacheKey, post)
+		return post, nil
+	})
+}
+
+func (r *cachedPostRepository) FindByCategoryWithDetails(ctx context.Context, categorySlug string, limit, offset int) ([]domain.PostWithDetails, error) {
+	cacheKey, err := getPostListCacheKey(ctx, r.redisClient, r.config.L1, getCategoryScope(categorySlug), fmt.Sprintf("limit=%d:offset=%d", limit, offset))
 	if err != nil {
-		return nil, err
+		return r.baseRepo.FindByCategoryWithDetails(ctx, categorySlug, limit, offset)
 	}
 
-	// Store in cache
-	data, _ := json.Marshal(post)
-	r.redisClient.Set(ctx, cacheKey, data, r.ttl)
-	log.Printf("→ Redis Cache SET: %s (TTL: %v)", cacheKey, r.ttl)
+	return r.lists.Get(ctx, cacheKey, func(ctx context.Context) ([]domain.PostWithDetails, error) {
+		return r.baseRepo.FindByCategoryWithDetails(ctx, categorySlug, limit, offset)
+	})
+}
 
-	return post, nil
+func (r *cachedPostRepository) FindByTagWithDetails(ctx context.Context, tagSlug string, limit, offset int) ([]domain.PostWithDetails, error) {
+	cacheKey, err := getPostListCacheKey(ctx, r.redisClient, r.config.L1, getTagScope(tagSlug), fmt.Sprintf("limit=%d:offset=%d", limit, offset))
+	if err != nil {
+		return r.baseRepo.FindByTagWithDetails(ctx, tagSlug, limit, offset)
+	}
+
+	return r.lists.Get(ctx, cacheKey, func(ctx context.Context) ([]domain.PostWithDetails, error) {
+		return r.baseRepo.FindByTagWithDetails(ctx, tagSlug, limit, offset)
+	})
 }
 
-func (r *cachedPostRepository) FindBySlugWithDetails(ctx context.Context, slug string) (*domain.PostWithDetails, error) {
-	cacheKey := fmt.Sprintf("post:slug:%s", slug)
-
-	// Try to get from cache
-	cached, err := r.redisClient.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var post domain.PostWithDetails
-		if err := json.Unmarshal([]byte(cached), &post); err == nil {
-			log.Printf("✓ Redis Cache HIT: %s (multi-table JOIN)", cacheKey)
-			return &post, nil
-		}
+func (r *cachedPostRepository) FindFeaturedWithDetails(ctx context.Context, limit int) ([]domain.PostWithDetails, error) {
+	cacheKey, err := getPostListCacheKey(ctx, r.redisClient, r.config.L1, postScopeFeatured, fmt.Sprintf("limit=%d", limit))
+	if err != nil {
+		return r.baseRepo.FindFeaturedWithDetails(ctx, limit)
 	}
 
-	// Cache miss, get from database
-	log.Printf("✗ Redis Cache MISS: %s - Fetching from MySQL (multi-table JOIN)", cacheKey)
-	post, err := r.baseRepo.FindBySlugWithDetails(ctx, slug)
+	return r.lists.Get(ctx, cacheKey, func(ctx context.Context) ([]domain.PostWithDetails, error) {
+		return r.baseRepo.FindFeaturedWithDetails(ctx, limit)
+	})
+}
+
+func (r *cachedPostRepository) SearchWithDetails(ctx context.Context, query, mode string, limit, offset int) ([]domain.PostSearchResult, error) {
+	cacheKey, err := getPostListCacheKey(ctx, r.redisClient, r.config.L1, postScopeSearch, fmt.Sprintf("mode=%s:q=%s:limit=%d:offset=%d", mode, query, limit, offset))
 	if err != nil {
-		return nil, err
+		return r.baseRepo.SearchWithDetails(ctx, query, mode, limit, offset)
 	}
 
-	// Store in cache
-	data, _ := json.Marshal(post)
-	r.redisClient.Set(ctx, cacheKey, data, r.ttl)
-	log.Printf("→ Redis Cache SET: %s (TTL: %v)", cacheKey, r.ttl)
+	return r.searches.Get(ctx, cacheKey, func(ctx context.Context) ([]domain.PostSearchResult, error) {
+		return r.baseRepo.SearchWithDetails(ctx, query, mode, limit, offset)
+	})
+}
 
-	return post, nil
+func (r *cachedPostRepository) GetTotalCount(ctx context.Context) (int64, error) {
+	cacheKey, err := getPostListCacheKey(ctx, r.redisClient, r.config.L1, postScopeAll, "totalcount")
+	if err != nil {
+		return r.baseRepo.GetTotalCount(ctx)
+	}
+
+	return r.counts.Get(ctx, cacheKey, func(ctx context.Context) (int64, error) {
+		return r.baseRepo.GetTotalCount(ctx)
+	})
 }
 
-func (r *cachedPostRepository) FindByCategoryWithDetails(ctx context.Context, categorySlug string, limit, offset int) ([]domain.PostWithDetails, error) {
-	cacheKey := fmt.Sprintf("posts:category:%s:limit=%d:offset=%d", categorySlug, limit, offset)
-
-	// Try to get from cache
-	cached, err := r.redisClient.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var posts []domain.PostWithDetails
-		if err := json.Unmarshal([]byte(cached), &posts); err == nil {
-			log.Printf("✓ Redis Cache HIT: %s (category JOIN cached)", cacheKey)
-			return posts, nil
+func (r *cachedPostRepository) AddViewCounts(ctx context.Context, deltas map[int64]int64) error {
+	err := r.baseRepo.AddViewCounts(ctx, deltas)
+	if err != nil {
+		return err
+	}
+
+	// 反映済みの閲覧数を含まないキャッシュと未反映分の合算で閲覧数が減って見えないよう、反映した投稿の詳細（ID・スラッグ）と、
+	// 投稿が表示される一覧の範囲を無効化する。範囲を特定できない投稿があれば全ての一覧を無効化する
+	scopes := map[string]struct{}{}
+	allLists := false
+	for id := range deltas {
+		post, err := r.FindByIDWithDetails(ctx, id)
+		if errors.Is(err, domain.ErrNotFound) {
+			// 公開中でない投稿は一覧に表示されない
+			continue
+		}
+		if err != nil {
+			allLists = true
+			break
 		}
+		for _, scope := range postListScopes(post) {
+			scopes[scope] = struct{}{}
+		}
+	}
+	versionKeys := getPostScopeVersionKeys(scopes)
+	if allLists {
+		versionKeys = []string{postListVersionKey}
+	}
+
+	pipe := r.redisClient.Pipeline()
+	indexes := make([]*redis.StringSliceCmd, 0, len(deltas))
+	for id := range deltas {
+		indexes = append(indexes, pipe.SMembers(ctx, getPostKeysCacheKey(id)))
+	}
+	pipe.Exec(ctx)
+
+	keys := make([]string, 0, len(deltas)*3)
+	for id := range deltas {
+		keys = append(keys, getPostCacheKey(id), getPostKeysCacheKey(id))
+	}
+	for _, index := range indexes {
+		keys = append(keys, index.Val()...)
 	}
+	pipe = r.redisClient.TxPipeline()
+	queueDeleteCacheKeys(ctx, pipe, keys...)
+	if len(versionKeys) > 0 {
+		queueIncrListVersions(ctx, pipe, versionKeys...)
+	}
+	pipe.Exec(ctx)
+	log.Printf("⚠ Redis Cache INVALIDATE: %d post details, %d post list versions (view counts flushed)", len(deltas), len(versionKeys))
+
+	return nil
+}
 
-	// Cache miss, get from database
-	log.Printf("✗ Redis Cache MISS: %s - Fetching from MySQL (category JOIN)", cacheKey)
-	posts, err := r.baseRepo.FindByCategoryWithDetails(ctx, categorySlug, limit, offset)
+func (r *cachedPostRepository) FindByID(ctx context.Context, id int64) (*domain.Post, error) {
+	// 書き込み系の状態確認に使うため、キャッシュせず常にDBから取得
+	return r.baseRepo.FindByID(ctx, id)
+}
+
+func (r *cachedPostRepository) Create(ctx context.Context, post *domain.Post, tagIDs []int64) error {
+	err := r.baseRepo.Create(ctx, post, tagIDs)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Store in cache
-	data, _ := json.Marshal(posts)
-	r.redisClient.Set(ctx, cacheKey, data, r.ttl)
-	log.Printf("→ Redis Cache SET: %s (TTL: %v)", cacheKey, r.ttl)
+	scopes := invalidatePostCaches(ctx, r.redisClient, post.ID, r.findListed(ctx, post.ID))
+	log.Printf("⚠ Redis Cache INVALIDATE: post:%d, posts scopes %v (Post created)", post.ID, scopes)
+	r.invalidateAuthorDetail(ctx, post.ID)
 
-	return posts, nil
+	return nil
 }
 
-func (r *cachedPostRepository) FindByTagWithDetails(ctx context.Context, tagSlug string, limit, offset int) ([]domain.PostWithDetails, error) {
-	cacheKey := fmt.Sprintf("posts:tag:%s:limit=%d:offset=%d", tagSlug, limit, offset)
-
-	// Try to get from cache
-	cached, err := r.redisClient.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var posts []domain.PostWithDetails
-		if err := json.Unmarshal([]byte(cached), &posts); err == nil {
-			log.Printf("✓ Redis Cache HIT: %s (tag JOIN cached)", cacheKey)
-			return posts, nil
-		}
+func (r *cachedPostRepository) Update(ctx context.Context, post *domain.Post, tagIDs []int64) error {
+	// スラッグ・カテゴリー・タグの変更時に変更前の一覧も無効化するため、更新前の状態を取得
+	before := r.findListed(ctx, post.ID)
+
+	err := r.baseRepo.Update(ctx, post, tagIDs)
+	if err != nil {
+		return err
+	}
+
+	scopes := invalidatePostCaches(ctx, r.redisClient, post.ID, before, r.findListed(ctx, post.ID))
+	log.Printf("⚠ Redis Cache INVALIDATE: post:%d, posts scopes %v (Post updated)", post.ID, scopes)
+	r.invalidateAuthorDetail(ctx, post.ID)
+
+	return nil
+}
+
+func (r *cachedPostRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	before := r.findListed(ctx, id)
+
+	err := r.baseRepo.UpdateStatus(ctx, id, status)
+	if err != nil {
+		return err
 	}
 
-	// Cache miss, get from database
-	log.Printf("✗ Redis Cache MISS: %s - Fetching from MySQL (tag JOIN)", cacheKey)
-	posts, err := r.baseRepo.FindByTagWithDetails(ctx, tagSlug, limit, offset)
+	scopes := invalidatePostCaches(ctx, r.redisClient, id, before, r.findListed(ctx, id))
+	log.Printf("⚠ Redis Cache INVALIDATE: post:%d, posts scopes %v (Post status changed to %s)", id, scopes, status)
+	r.invalidateAuthorDetail(ctx, id)
+
+	return nil
+}
+
+func (r *cachedPostRepository) Delete(ctx context.Context, id int64) error {
+	before := r.findListed(ctx, id)
+
+	err := r.baseRepo.Delete(ctx, id)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Store in cache
-	data, _ := json.Marshal(posts)
-	r.redisClient.Set(ctx, cacheKey, data, r.ttl)
-	log.Printf("→ Redis Cache SET: %s (TTL: %v)", cacheKey, r.ttl)
+	scopes := invalidatePostCaches(ctx, r.redisClient, id, before)
+	log.Printf("⚠ Redis Cache INVALIDATE: post:%d, posts scopes %v (Post deleted)", id, scopes)
+	r.invalidateAuthorDetail(ctx, id)
 
-	return posts, nil
+	return nil
 }
 
-func (r *cachedPostRepository) FindFeaturedWithDetails(ctx context.Context, limit int) ([]domain.PostWithDetails, error) {
-	cacheKey := fmt.Sprintf("posts:featured:limit=%d", limit)
-
-	// Try to get from cache
-	cached, err := r.redisClient.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var posts []domain.PostWithDetails
-		if err := json.Unmarshal([]byte(cached), &posts); err == nil {
-			log.Printf("✓ Redis Cache HIT: %s (featured posts cached)", cacheKey)
-			return posts, nil
-		}
+// invalidateAuthorDetail は投稿者のユーザー詳細（最近の投稿・投稿数を含む）のキャッシュを削除します
+func (r *cachedPostRepository) invalidateAuthorDetail(ctx context.Context, id int64) {
+	post, err := r.baseRepo.FindByID(ctx, id)
+	if err != nil {
+		return
 	}
 
-	// Cache miss, get from database
-	log.Printf("✗ Redis Cache MISS: %s - Fetching from MySQL (featured posts)", cacheKey)
-	posts, err := r.baseRepo.FindFeaturedWithDetails(ctx, limit)
+	key := getUserDetailCacheKey(post.UserID)
+	deleteCacheKeys(ctx, r.redisClient, key)
+	log.Printf("⚠ Redis Cache INVALIDATE: %s (Posts changed)", key)
+}
+
+// findListed は一覧に表示される（公開中の）投稿をMySQLから取得します。非公開・存在しない場合はnilを返します
+func (r *cachedPostRepository) findListed(ctx context.Context, id int64) *domain.PostWithDetails {
+	post, err := r.baseRepo.FindByIDWithDetails(ctx, id)
 	if err != nil {
-		return nil, err
+		return nil
+	}
+	return post
+}
+
+// indexPostDetail は投稿詳細のキャッシュキーを投稿ごと（post:{id}:keys）と表示名を含むユーザー（投稿者・最新コメントの投稿者）ごと
+// （user:{id}:post_keys）の索引へ登録します。閲覧数の反映時・プロフィール変更時はこの索引で無効化します
+func (r *cachedPostRepository) indexPostDetail(ctx context.Context, cacheKey string, post *domain.PostWithDetails) {
+	userIDs := map[int64]struct{}{post.UserID: {}}
+	for _, comment := range post.LatestComments {
+		userIDs[comment.UserID] = struct{}{}
 	}
 
-	// Store in cache
-	data, _ := json.Marshal(posts)
-	r.redisClient.Set(ctx, cacheKey, data, r.ttl)
-	log.Printf("→ Redis Cache SET: %s (TTL: %v)", cacheKey, r.ttl)
+	ttl := r.config.HardTTL
+	pipe := r.redisClient.TxPipeline()
+	pipe.SAdd(ctx, getPostKeysCacheKey(post.ID), cacheKey)
+	pipe.Expire(ctx, getPostKeysCacheKey(post.ID), ttl)
+	for userID := range userIDs {
+		indexKey := getUserPostKeysCacheKey(userID)
+		pipe.SAdd(ctx, indexKey, cacheKey)
+		pipe.Expire(ctx, indexKey, ttl)
+	}
+	pipe.Exec(ctx)
+}
 
-	return posts, nil
+// invalidateUserPostCaches はユーザーの表示名を含む投稿詳細キャッシュと一覧系キャッシュを無効化します
+func invalidateUserPostCaches(ctx context.Context, redisClient *redis.Client, userID int64) {
+	indexKey := getUserPostKeysCacheKey(userID)
+	keys, _ := redisClient.SMembers(ctx, indexKey).Result()
+	deleteCacheKeys(ctx, redisClient, append(keys, indexKey)...)
+
+	invalidatePostListCaches(ctx, redisClient)
+	log.Printf("⚠ Redis Cache INVALIDATE: %d post details, posts lists (User %d updated)", len(keys), userID)
 }
 
-func (r *cachedPostRepository) GetTotalCount(ctx context.Context) (int64, error) {
-	cacheKey := "posts:totalcount"
-
-	// Try to get from cache
-	cached, err := r.redisClient.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var count int64
-		if err := json.Unmarshal([]byte(cached), &count); err == nil {
-			log.Printf("✓ Redis Cache HIT: %s", cacheKey)
-			return count, nil
+// invalidatePostCaches は単一投稿の詳細キャッシュを削除し、投稿（変更前・変更後の状態）が表示される一覧の範囲の世代を進めます。
+// postsには公開中の状態のみを渡します（nilは一覧に表示されていない状態として無視します）。無効化した範囲を返します
+func invalidatePostCaches(ctx context.Context, redisClient *redis.Client, id int64, posts ...*domain.PostWithDetails) []string {
+	keys := []string{getPostCacheKey(id)}
+	scopes := map[string]struct{}{}
+	for _, post := range posts {
+		if post == nil {
+			continue
+		}
+		keys = append(keys, getPostSlugCacheKey(post.Slug))
+		for _, scope := range postListScopes(post) {
+			scopes[scope] = struct{}{}
 		}
 	}
 
-	// Cache miss, get from database
-	log.Printf("✗ Redis Cache MISS: %s - Fetching from MySQL", cacheKey)
-	count, err := r.baseRepo.GetTotalCount(ctx)
-	if err != nil {
-		return 0, err
+	invalidated := make([]string, 0, len(scopes))
+	for scope := range scopes {
+		invalidated = append(invalidated, scope)
 	}
+	sort.Strings(invalidated)
+	versionKeys := getPostScopeVersionKeys(scopes)
 
-	// Store in cache
-	data, _ := json.Marshal(count)
-	r.redisClient.Set(ctx, cacheKey, data, r.ttl)
-	log.Printf("→ Redis Cache SET: %s (TTL: %v)", cacheKey, r.ttl)
+	pipe := redisClient.TxPipeline()
+	queueDeleteCacheKeys(ctx, pipe, keys...)
+	if len(versionKeys) > 0 {
+		queueIncrListVersions(ctx, pipe, versionKeys...)
+	}
+	pipe.Exec(ctx)
 
-	return count, nil
+	return invalidated
 }
 
-func (r *cachedPostRepository) IncrementViewCount(ctx context.Context, postID int64) error {
-	// Increment view count in database
-	err := r.baseRepo.IncrementViewCount(ctx, postID)
-	if err != nil {
-		return err
+// invalidatePostListCaches は全ての一覧系キャッシュ（一覧・カテゴリー・タグ・注目・検索・件数）を無効化します。
+// 投稿者の名前変更や停止のように、影響する範囲を特定できない変更で使います
+func invalidatePostListCaches(ctx context.Context, redisClient *redis.Client) {
+	incrListVersions(ctx, redisClient, postListVersionKey)
+}
+
+// 投稿一覧のキャッシュの範囲（scope）。カテゴリー・タグは "category:{slug}" / "tag:{slug}"
+const (
+	postScopeAll      = "all"
+	postScopeFeatured = "featured"
+	postScopeSearch   = "search"
+)
+
+// postListVersionKey は全ての投稿一覧に共通の世代番号。
+// 一覧のキーは「共通の世代.範囲の世代」を含み、削除せず世代を進めて古いキーをTTLで消す
+const postListVersionKey = "posts:list:version"
+
+// postListScopes は公開中の投稿が表示される一覧の範囲を返します
+func postListScopes(post *domain.PostWithDetails) []string {
+	scopes := []string{postScopeAll, postScopeSearch}
+	if post.IsFeatured {
+		scopes = append(scopes, postScopeFeatured)
+	}
+	if post.CategorySlug != nil {
+		scopes = append(scopes, getCategoryScope(*post.CategorySlug))
+	}
+	for _, tag := range post.Tags {
+		scopes = append(scopes, getTagScope(tag.Slug))
 	}
+	return scopes
+}
+
+func getCategoryScope(slug string) string {
+	return "category:" + slug
+}
 
-	// Invalidate related caches
-	r.redisClient.Del(ctx, getPostCacheKey(postID))
-	// Invalidate list caches
-	keys, _ := r.redisClient.Keys(ctx, "posts:*").Result()
-	if len(keys) > 0 {
-		r.redisClient.Del(ctx, keys...)
+func getTagScope(slug string) string {
+	return "tag:" + slug
+}
+
+func getPostScopeVersionKey(scope string) string {
+	return fmt.Sprintf("posts:scope:%s:version", scope)
+}
+
+// getPostScopeVersionKeys は範囲の世代番号のキーを範囲の名前順に返します
+func getPostScopeVersionKeys(scopes map[string]struct{}) []string {
+	keys := make([]string, 0, len(scopes))
+	for scope := range scopes {
+		keys = append(keys, getPostScopeVersionKey(scope))
 	}
-	log.Printf("⚠ Redis Cache INVALIDATE: post:%d (view count incremented)", postID)
+	sort.Strings(keys)
+	return keys
+}
 
-	return nil
+// getPostListCacheKey は世代番号と条件からキーを作ります（例: posts:category:tech:2.5:limit=20:offset=0）
+func getPostListCacheKey(ctx context.Context, redisClient *redis.Client, l1 *cache.LRU, scope, condition string) (string, error) {
+	versions, err := getListVersions(ctx, redisClient, l1, postListVersionKey, getPostScopeVersionKey(scope))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("posts:%s:%s.%s:%s", scope, versions[0], versions[1], condition), nil
 }
 
 func getPostCacheKey(id int64) string {
 	return fmt.Sprintf("post:%d", id)
 }
+
+func getPostSlugCacheKey(slug string) string {
+	return fmt.Sprintf("post:slug:%s", slug)
+}
+
+func getUserPostKeysCacheKey(userID int64) string {
+	return fmt.Sprintf("user:%d:post_keys", userID)
+}
+
+func getPostKeysCacheKey(id int64) string {
+	return fmt.Sprintf("post:%d:keys", id)
+}
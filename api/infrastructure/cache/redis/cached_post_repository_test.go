@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// viewCountPostRepository は閲覧数の反映と、公開中の投稿の取得だけを受け付けます
+type viewCountPostRepository struct {
+	domain.PostRepository
+	posts   map[int64]*domain.PostWithDetails
+	flushed map[int64]int64
+}
+
+func (r *viewCountPostRepository) FindByIDWithDetails(ctx context.Context, id int64) (*domain.PostWithDetails, error) {
+	post, ok := r.posts[id]
+	if !ok {
+		return nil, domain.NotFoundError("post")
+	}
+	copied := *post
+	return &copied, nil
+}
+
+func (r *viewCountPostRepository) AddViewCounts(ctx context.Context, deltas map[int64]int64) error {
+	for id, delta := range deltas {
+		r.flushed[id] += delta
+	}
+	return nil
+}
+
+func TestAddViewCountsInvalidatesViewedPosts(t *testing.T) {
+	_, redisClient := newFakeRedis(t)
+	ctx := context.Background()
+
+	tech := "tech"
+	base := &viewCountPostRepository{
+		posts: map[int64]*domain.PostWithDetails{
+			1: {Post: domain.Post{ID: 1, Slug: "hello", Status: domain.PostStatusPublished}, CategorySlug: &tech},
+		},
+		flushed: make(map[int64]int64),
+	}
+	repo := NewCachedPostRepository(base, redisClient, DefaultReadCacheConfig())
+
+	redisClient.Set(ctx, postListVersionKey, "5", 0)
+	redisClient.Set(ctx, getPostCacheKey(1), "cached", 0)
+	redisClient.Set(ctx, getPostSlugCacheKey("hello"), "cached", 0)
+	redisClient.SAdd(ctx, getPostKeysCacheKey(1), getPostSlugCacheKey("hello"))
+	redisClient.Set(ctx, getPostCacheKey(2), "cached", 0)
+
+	// 投稿3は公開中でない（一覧に表示されない）
+	if err := repo.AddViewCounts(ctx, map[int64]int64{1: 3, 3: 1}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if base.flushed[1] != 3 {
+		t.Errorf("Expected 3 views flushed for post 1, got %d", base.flushed[1])
+	}
+	if n := redisClient.Exists(ctx, getPostCacheKey(1), getPostSlugCacheKey("hello")).Val(); n != 0 {
+		t.Errorf("Expected the details of post 1 to be invalidated, %d keys remain", n)
+	}
+	if n := redisClient.Exists(ctx, getPostCacheKey(2)).Val(); n != 1 {
+		t.Errorf("Expected the details of other posts to stay cached")
+	}
+	if version := redisClient.Get(ctx, postListVersionKey).Val(); version != "5" {
+		t.Errorf("Expected %s to stay at 5, got %s", postListVersionKey, version)
+	}
+
+	// 投稿1が表示される一覧の範囲だけ世代を進める
+	for _, scope := range []string{postScopeAll, postScopeSearch, getCategoryScope(tech)} {
+		if version := redisClient.Get(ctx, getPostScopeVersionKey(scope)).Val(); version != "1" {
+			t.Errorf("Expected the version of %s to be bumped, got %q", scope, version)
+		}
+	}
+	if n := redisClient.Exists(ctx, getPostScopeVersionKey(postScopeFeatured)).Val(); n != 0 {
+		t.Errorf("Expected the version of %s to stay unset", postScopeFeatured)
+	}
+}
@@ -0,0 +1,64 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/infrastructure/cache"
+)
+
+// cachedRoleRepository はユーザーのロール・権限をキャッシュするDecoratorです。
+// 認可チェックは書き込みリクエストのたびに行われるため、MySQLへのJOINクエリを避けます。
+type cachedRoleRepository struct {
+	baseRepo    domain.RoleRepository
+	redisClient *redis.Client
+	roles       *cache.ReadThrough[*domain.UserRoles]
+}
+
+// NewCachedRoleRepository creates a role repository with Redis caching
+func NewCachedRoleRepository(baseRepo domain.RoleRepository, redisClient *redis.Client, config ReadCacheConfig) domain.RoleRepository {
+	return &cachedRoleRepository{
+		baseRepo:    baseRepo,
+		redisClient: redisClient,
+		roles:       newReadThrough(redisClient, config, clonePointer[domain.UserRoles]),
+	}
+}
+
+func (r *cachedRoleRepository) FindByUserID(ctx context.Context, userID int64) (*domain.UserRoles, error) {
+	return r.roles.Get(ctx, getUserRolesCacheKey(userID), func(ctx context.Context) (*domain.UserRoles, error) {
+		return r.baseRepo.FindByUserID(ctx, userID)
+	})
+}
+
+func (r *cachedRoleRepository) Assign(ctx context.Context, userID int64, role string) error {
+	err := r.baseRepo.Assign(ctx, userID, role)
+	if err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, userID)
+	return nil
+}
+
+func (r *cachedRoleRepository) Revoke(ctx context.Context, userID int64, role string) error {
+	err := r.baseRepo.Revoke(ctx, userID, role)
+	if err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, userID)
+	return nil
+}
+
+func (r *cachedRoleRepository) invalidate(ctx context.Context, userID int64) {
+	cacheKey := getUserRolesCacheKey(userID)
+	deleteCacheKeys(ctx, r.redisClient, cacheKey)
+	log.Printf("⚠ Redis Cache INVALIDATE: %s", cacheKey)
+}
+
+func getUserRolesCacheKey(userID int64) string {
+	return fmt.Sprintf("user:%d:roles", userID)
+}
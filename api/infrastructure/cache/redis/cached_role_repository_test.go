@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/infrastructure/cache"
+)
+
+// memoryRoleRepository はユーザーごとのロールだけを持つテスト用リポジトリ（権限はロール名と同じにする）
+type memoryRoleRepository struct {
+	mu    sync.Mutex
+	roles map[int64][]string
+}
+
+func (r *memoryRoleRepository) FindByUserID(ctx context.Context, userID int64) (*domain.UserRoles, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	roles := slices.Clone(r.roles[userID])
+	return &domain.UserRoles{UserID: userID, Roles: roles, Permissions: roles}, nil
+}
+
+func (r *memoryRoleRepository) Assign(ctx context.Context, userID int64, role string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.roles[userID] = append(r.roles[userID], role)
+	return nil
+}
+
+func (r *memoryRoleRepository) Revoke(ctx context.Context, userID int64, role string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.roles[userID] = slices.DeleteFunc(r.roles[userID], func(granted string) bool { return granted == role })
+	return nil
+}
+
+func TestRoleRevocationReachesL1(t *testing.T) {
+	_, redisClient := newFakeRedis(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	config := DefaultReadCacheConfig()
+	config.L1 = cache.NewLRU(100, time.Minute)
+	config.L1.Set("before", "value", time.Minute)
+	go SubscribeL1Invalidations(ctx, redisClient, config.L1)
+	waitUntil(t, "the subscription to clear L1", func() bool { return config.L1.Len() == 0 })
+
+	repo := NewCachedRoleRepository(&memoryRoleRepository{roles: map[int64][]string{1: {"admin"}}}, redisClient, config)
+	hasAdmin := func() bool {
+		roles, err := repo.FindByUserID(ctx, 1)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return roles.HasPermission("admin")
+	}
+	if !hasAdmin() {
+		t.Fatal("Expected the granted role to be returned")
+	}
+
+	// 失効はRedisだけでなく全インスタンスのL1からも消える
+	if err := repo.Revoke(ctx, 1, "admin"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	waitUntil(t, "the revoked role to leave L1", func() bool { return !hasAdmin() })
+}
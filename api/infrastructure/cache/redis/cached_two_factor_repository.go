@@ -0,0 +1,64 @@
+package redis
+
+import (
+	"context"
+	"log"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// cachedTwoFactorRepository は2段階認証の有効化・無効化時にユーザー詳細キャッシュを無効化するDecoratorです
+// （UserDetailはtwoFactorEnabledを含むため）
+type cachedTwoFactorRepository struct {
+	baseRepo    domain.TwoFactorRepository
+	redisClient *redis.Client
+}
+
+// NewCachedTwoFactorRepository creates a two-factor repository that invalidates user detail caches
+func NewCachedTwoFactorRepository(baseRepo domain.TwoFactorRepository, redisClient *redis.Client) domain.TwoFactorRepository {
+	return &cachedTwoFactorRepository{
+		baseRepo:    baseRepo,
+		redisClient: redisClient,
+	}
+}
+
+func (r *cachedTwoFactorRepository) FindByUserID(ctx context.Context, userID int64) (*domain.TwoFactorSettings, error) {
+	return r.baseRepo.FindByUserID(ctx, userID)
+}
+
+func (r *cachedTwoFactorRepository) SaveSecret(ctx context.Context, userID int64, secret string) error {
+	return r.baseRepo.SaveSecret(ctx, userID, secret)
+}
+
+func (r *cachedTwoFactorRepository) Enable(ctx context.Context, userID int64, step int64, recoveryCodeHashes []string) error {
+	if err := r.baseRepo.Enable(ctx, userID, step, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	r.invalidateUserDetail(ctx, userID, "2FA enabled")
+	return nil
+}
+
+func (r *cachedTwoFactorRepository) Disable(ctx context.Context, userID int64) error {
+	if err := r.baseRepo.Disable(ctx, userID); err != nil {
+		return err
+	}
+
+	r.invalidateUserDetail(ctx, userID, "2FA disabled")
+	return nil
+}
+
+func (r *cachedTwoFactorRepository) UseStep(ctx context.Context, userID int64, step int64) (bool, error) {
+	return r.baseRepo.UseStep(ctx, userID, step)
+}
+
+func (r *cachedTwoFactorRepository) ConsumeRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	return r.baseRepo.ConsumeRecoveryCode(ctx, userID, codeHash)
+}
+
+func (r *cachedTwoFactorRepository) invalidateUserDetail(ctx context.Context, userID int64, reason string) {
+	key := getUserDetailCacheKey(userID)
+	deleteCacheKeys(ctx, r.redisClient, key)
+	log.Printf("⚠ Redis Cache INVALIDATE: %s (%s)", key, reason)
+}
@@ -0,0 +1,80 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/infrastructure/cache"
+)
+
+// cachedUserDetailRepository はユーザー詳細（user:{id}:detail）をキャッシュするDecoratorです。
+// ユーザー名での取得はユーザー名→IDの対応（user:username:{username}）をキャッシュし、IDの詳細キャッシュを使います。
+// 詳細の無効化はユーザー・プロフィール・フォロー・通知・投稿・コメントなど、詳細に含まれる情報を変更する各Decoratorが行います。
+// いいね数・閲覧数の変化はTTLで反映されます。
+type cachedUserDetailRepository struct {
+	baseRepo    domain.UserDetailRepository
+	redisClient *redis.Client
+
+	details *cache.ReadThrough[*domain.UserDetail]
+	ids     *cache.ReadThrough[int64]
+}
+
+// NewCachedUserDetailRepository creates a user detail repository cached in Redis
+func NewCachedUserDetailRepository(baseRepo domain.UserDetailRepository, redisClient *redis.Client, config ReadCacheConfig) domain.UserDetailRepository {
+	return &cachedUserDetailRepository{
+		baseRepo:    baseRepo,
+		redisClient: redisClient,
+		details:     newReadThrough(redisClient, config, clonePointer[domain.UserDetail]),
+		ids:         newReadThrough[int64](redisClient, config, nil),
+	}
+}
+
+func (r *cachedUserDetailRepository) FindDetailByID(ctx context.Context, id int64) (*domain.UserDetail, error) {
+	return r.details.Get(ctx, getUserDetailCacheKey(id), func(ctx context.Context) (*domain.UserDetail, error) {
+		return r.baseRepo.FindDetailByID(ctx, id)
+	})
+}
+
+func (r *cachedUserDetailRepository) FindDetailByUsername(ctx context.Context, username string) (*domain.UserDetail, error) {
+	cacheKey := getUsernameCacheKey(username)
+
+	id, err := r.ids.Get(ctx, cacheKey, func(ctx context.Context) (int64, error) {
+		detail, err := r.baseRepo.FindDetailByUsername(ctx, username)
+		if err != nil {
+			return 0, err
+		}
+		// 取得した詳細はIDのキャッシュにも保存する
+		if err := r.details.Set(ctx, getUserDetailCacheKey(detail.ID), detail); err != nil {
+			log.Printf("Warning: failed to cache user detail %d: %v", detail.ID, err)
+		}
+		return detail.ID, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	detail, err := r.FindDetailByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// ユーザー名の変更後は対応が古いため、削除してMySQLから取得し直す
+	if detail.Username != username {
+		deleteCacheKeys(ctx, r.redisClient, cacheKey)
+		log.Printf("⚠ Redis Cache INVALIDATE: %s (Username changed)", cacheKey)
+		return r.baseRepo.FindDetailByUsername(ctx, username)
+	}
+
+	return detail, nil
+}
+
+func getUserDetailCacheKey(id int64) string {
+	return fmt.Sprintf("user:%d:detail", id)
+}
+
+func getUsernameCacheKey(username string) string {
+	return fmt.Sprintf("user:username:%s", username)
+}
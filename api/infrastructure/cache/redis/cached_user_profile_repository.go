@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"context"
+	"log"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// cachedUserProfileRepository はプロフィール更新時にユーザー詳細と、表示名を含む投稿キャッシュを無効化するDecoratorです
+type cachedUserProfileRepository struct {
+	baseRepo    domain.UserProfileRepository
+	redisClient *redis.Client
+}
+
+// NewCachedUserProfileRepository creates a user profile repository that invalidates user detail and post caches
+func NewCachedUserProfileRepository(baseRepo domain.UserProfileRepository, redisClient *redis.Client) domain.UserProfileRepository {
+	return &cachedUserProfileRepository{
+		baseRepo:    baseRepo,
+		redisClient: redisClient,
+	}
+}
+
+func (r *cachedUserProfileRepository) FindByUserID(ctx context.Context, userID int64) (*domain.UserProfile, error) {
+	return r.baseRepo.FindByUserID(ctx, userID)
+}
+
+func (r *cachedUserProfileRepository) Upsert(ctx context.Context, userID int64, profile *domain.UserProfile) error {
+	if err := r.baseRepo.Upsert(ctx, userID, profile); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, userID, "Profile updated")
+	return nil
+}
+
+func (r *cachedUserProfileRepository) UpdateAvatarURL(ctx context.Context, userID int64, avatarURL *string) error {
+	if err := r.baseRepo.UpdateAvatarURL(ctx, userID, avatarURL); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, userID, "Avatar updated")
+	return nil
+}
+
+// invalidate はユーザー（生年月日を含む）・ユーザー詳細・一覧と、表示名を含む投稿キャッシュを無効化します
+func (r *cachedUserProfileRepository) invalidate(ctx context.Context, userID int64, reason string) {
+	deleteCacheKeys(ctx, r.redisClient, getCacheKey(userID), getUserDetailCacheKey(userID))
+	invalidateUserListCaches(ctx, r.redisClient)
+	log.Printf("⚠ Redis Cache INVALIDATE: user:%d, user:%d:detail, users:list:* (%s)", userID, userID, reason)
+
+	invalidateUserPostCaches(ctx, r.redisClient, userID)
+}
@@ -2,13 +2,16 @@ package redis
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
-	"time"
+	"slices"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/infrastructure/cache"
 )
 
 // cachedUserRepository はキャッシュのためのDecorator/Proxyパターンを実装します。
@@ -22,76 +25,61 @@ import (
 type cachedUserRepository struct {
 	baseRepo    domain.UserRepository // Domainインターフェース - 任意の実装が可能
 	redisClient *redis.Client
-	ctx         context.Context
-	ttl         time.Duration
+	config      ReadCacheConfig
+
+	// スタンピード対策付きのread-throughキャッシュ（値の型ごと）
+	users  *cache.ReadThrough[*domain.User]
+	lists  *cache.ReadThrough[[]domain.User]
+	counts *cache.ReadThrough[int64]
 }
 
 // NewCachedUserRepository は新しいキャッシュ付きユーザーリポジトリを作成します。
 // baseRepoはdomain.UserRepositoryの任意の実装（MySQL, PostgreSQLなど）が使用できます。
 // Decoratorパターンに従い、透過的にキャッシュ機能を追加します。
-func NewCachedUserRepository(baseRepo domain.UserRepository, redisClient *redis.Client) domain.UserRepository {
+// configでキャッシュの有効期限（soft/hard TTL）と分散ロックを指定します。
+func NewCachedUserRepository(baseRepo domain.UserRepository, redisClient *redis.Client, config ReadCacheConfig) domain.UserRepository {
 	return &cachedUserRepository{
 		baseRepo:    baseRepo,
 		redisClient: redisClient,
-		ctx:         context.Background(),
-		ttl:         5 * time.Minute, // Cache TTL: 5 minutes
+		config:      config,
+		users:       newReadThrough(redisClient, config, clonePointer[domain.User]),
+		lists:       newReadThrough(redisClient, config, slices.Clone[[]domain.User]),
+		counts:      newReadThrough[int64](redisClient, config, nil),
 	}
 }
 
-func (r *cachedUserRepository) FindAll(ctx context.Context) ([]domain.User, error) {
-	cacheKey := "users:all"
-
-	// Try to get from cache
-	cached, err := r.redisClient.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var users []domain.User
-		if err := json.Unmarshal([]byte(cached), &users); err == nil {
-			log.Printf("✓ Redis Cache HIT: %s", cacheKey)
-			return users, nil
-		}
+func (r *cachedUserRepository) FindAll(ctx context.Context, query domain.UserListQuery) ([]domain.User, error) {
+	cacheKey, err := getUserListCacheKey(ctx, r.redisClient, r.config.L1, "list", query)
+	if err != nil {
+		return r.baseRepo.FindAll(ctx, query)
 	}
 
-	// Cache miss, get from database
-	log.Printf("✗ Redis Cache MISS: %s - Fetching from MySQL", cacheKey)
-	users, err := r.baseRepo.FindAll(ctx)
+	return r.lists.Get(ctx, cacheKey, func(ctx context.Context) ([]domain.User, error) {
+		return r.baseRepo.FindAll(ctx, query)
+	})
+}
+
+func (r *cachedUserRepository) Count(ctx context.Context, filter domain.UserListFilter) (int64, error) {
+	cacheKey, err := getUserListCacheKey(ctx, r.redisClient, r.config.L1, "count", filter)
 	if err != nil {
-		return nil, err
+		return r.baseRepo.Count(ctx, filter)
 	}
 
-	// Store in cache
-	data, _ := json.Marshal(users)
-	r.redisClient.Set(ctx, cacheKey, data, r.ttl)
-	log.Printf("→ Redis Cache SET: %s (TTL: %v)", cacheKey, r.ttl)
-
-	return users, nil
+	return r.counts.Get(ctx, cacheKey, func(ctx context.Context) (int64, error) {
+		return r.baseRepo.Count(ctx, filter)
+	})
 }
 
 func (r *cachedUserRepository) FindByID(ctx context.Context, id int64) (*domain.User, error) {
 	cacheKey := getCacheKey(id)
 
-	// Try to get from cache
-	cached, err := r.redisClient.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var user domain.User
-		if err := json.Unmarshal([]byte(cached), &user); err == nil {
-			log.Printf("✓ Redis Cache HIT: %s", cacheKey)
-			return &user, nil
-		}
-	}
-
-	// Cache miss, get from database
-	log.Printf("✗ Redis Cache MISS: %s - Fetching from MySQL", cacheKey)
-	user, err := r.baseRepo.FindByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-
-	// Store in cache
-	data, _ := json.Marshal(user)
-	r.redisClient.Set(ctx, cacheKey, data, r.ttl)
-	log.Printf("→ Redis Cache SET: %s (TTL: %v)", cacheKey, r.ttl)
+	return r.users.Get(ctx, cacheKey, func(ctx context.Context) (*domain.User, error) {
+		return r.baseRepo.FindByID(ctx, id)
+	})
+}
 
-	return user, nil
+func (r *cachedUserRepository) FindByUsernames(ctx context.Context, usernames []string) ([]domain.User, error) {
+	return r.baseRepo.FindByUsernames(ctx, usernames)
 }
 
 func (r *cachedUserRepository) Create(ctx context.Context, user *domain.User) error {
@@ -100,9 +88,9 @@ func (r *cachedUserRepository) Create(ctx context.Context, user *domain.User) er
 		return err
 	}
 
-	// Invalidate list cache
-	r.redisClient.Del(ctx, "users:all")
-	log.Printf("⚠ Redis Cache INVALIDATE: users:all (User created: ID=%d)", user.ID)
+	// Invalidate list caches
+	invalidateUserListCaches(ctx, r.redisClient)
+	log.Printf("⚠ Redis Cache INVALIDATE: users:list:* (User created: ID=%d)", user.ID)
 
 	return nil
 }
@@ -113,10 +101,11 @@ func (r *cachedUserRepository) Update(ctx context.Context, user *domain.User) er
 		return err
 	}
 
-	// Invalidate caches
-	r.redisClient.Del(ctx, getCacheKey(user.ID))
-	r.redisClient.Del(ctx, "users:all")
-	log.Printf("⚠ Redis Cache INVALIDATE: user:%d, users:all (User updated)", user.ID)
+	// Invalidate caches (ユーザー名は詳細と投稿にも含まれる)
+	deleteCacheKeys(ctx, r.redisClient, getCacheKey(user.ID), getUserDetailCacheKey(user.ID))
+	invalidateUserListCaches(ctx, r.redisClient)
+	invalidateUserPostCaches(ctx, r.redisClient, user.ID)
+	log.Printf("⚠ Redis Cache INVALIDATE: user:%d, user:%d:detail, users:list:* (User updated)", user.ID, user.ID)
 
 	return nil
 }
@@ -127,10 +116,12 @@ func (r *cachedUserRepository) Delete(ctx context.Context, id int64) error {
 		return err
 	}
 
-	// Invalidate caches
-	r.redisClient.Del(ctx, getCacheKey(id))
-	r.redisClient.Del(ctx, "users:all")
-	log.Printf("⚠ Redis Cache INVALIDATE: user:%d, users:all (User deleted)", id)
+	// Invalidate caches (論理削除したユーザーの投稿は一覧から除外されるため一覧系も削除)
+	deleteCacheKeys(ctx, r.redisClient, getCacheKey(id), getUserDetailCacheKey(id))
+	invalidateUserListCaches(ctx, r.redisClient)
+	invalidatePostListCaches(ctx, r.redisClient)
+	invalidateFeedCaches(ctx, r.redisClient)
+	log.Printf("⚠ Redis Cache INVALIDATE: user:%d, user:%d:detail, users:list:*, posts:*, feed:* (User deleted)", id, id)
 
 	return nil
 }
@@ -138,3 +129,23 @@ func (r *cachedUserRepository) Delete(ctx context.Context, id int64) error {
 func getCacheKey(id int64) string {
 	return fmt.Sprintf("user:%d", id)
 }
+
+// userListVersionKey は一覧キャッシュの世代番号。
+// 一覧のキーは条件の組み合わせごとに増えるため、削除せず世代を進めて古いキーをTTLで消す
+const userListVersionKey = "users:list:version"
+
+// getUserListCacheKey は世代番号と検索条件のハッシュからキーを作ります（例: users:list:3:9f86d081...）
+func getUserListCacheKey(ctx context.Context, redisClient *redis.Client, l1 *cache.LRU, kind string, condition interface{}) (string, error) {
+	versions, err := getListVersions(ctx, redisClient, l1, userListVersionKey)
+	if err != nil {
+		return "", err
+	}
+	data, _ := json.Marshal(condition)
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("users:%s:%s:%s", kind, versions[0], hex.EncodeToString(sum[:16])), nil
+}
+
+// invalidateUserListCaches はユーザー一覧・件数のキャッシュを全て無効化します
+func invalidateUserListCaches(ctx context.Context, redisClient *redis.Client) {
+	incrListVersions(ctx, redisClient, userListVersionKey)
+}
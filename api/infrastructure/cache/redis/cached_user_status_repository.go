@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"context"
+	"log"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// cachedUserStatusRepository はアカウント状態の変更時にユーザー・投稿一覧・タイムラインのキャッシュを無効化するDecoratorです
+// （停止中・削除済みのユーザーとその投稿は一覧から除外されるため）
+type cachedUserStatusRepository struct {
+	baseRepo    domain.UserStatusRepository
+	redisClient *redis.Client
+}
+
+// NewCachedUserStatusRepository creates a user status repository that invalidates user and post list caches
+func NewCachedUserStatusRepository(baseRepo domain.UserStatusRepository, redisClient *redis.Client) domain.UserStatusRepository {
+	return &cachedUserStatusRepository{
+		baseRepo:    baseRepo,
+		redisClient: redisClient,
+	}
+}
+
+func (r *cachedUserStatusRepository) FindStatus(ctx context.Context, userID int64) (string, error) {
+	return r.baseRepo.FindStatus(ctx, userID)
+}
+
+func (r *cachedUserStatusRepository) ChangeStatus(ctx context.Context, change *domain.UserStatusChange) error {
+	if err := r.baseRepo.ChangeStatus(ctx, change); err != nil {
+		return err
+	}
+
+	deleteCacheKeys(ctx, r.redisClient, getCacheKey(change.UserID), getUserDetailCacheKey(change.UserID))
+	invalidateUserListCaches(ctx, r.redisClient)
+
+	// 一覧に出るかどうかが変わる場合だけ投稿一覧とタイムラインを作り直す
+	if domain.IsUserStatusHidden(change.FromStatus) != domain.IsUserStatusHidden(change.ToStatus) {
+		invalidatePostListCaches(ctx, r.redisClient)
+		invalidateFeedCaches(ctx, r.redisClient)
+		log.Printf("⚠ Redis Cache INVALIDATE: user:%d, user:%d:detail, users:list:*, posts:*, feed:* (User %s → %s)", change.UserID, change.UserID, change.FromStatus, change.ToStatus)
+		return nil
+	}
+
+	log.Printf("⚠ Redis Cache INVALIDATE: user:%d, user:%d:detail, users:list:* (User %s → %s)", change.UserID, change.UserID, change.FromStatus, change.ToStatus)
+	return nil
+}
+
+func (r *cachedUserStatusRepository) FindHistory(ctx context.Context, userID int64, limit int) ([]domain.UserStatusChange, error) {
+	return r.baseRepo.FindHistory(ctx, userID, limit)
+}
@@ -0,0 +1,485 @@
+package redis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// fakeRedis はテスト用のインメモリRedisサーバーです（RESP2）。
+// リポジトリが使うコマンドだけを実装し、有効期限は扱いません
+type fakeRedis struct {
+	mu          sync.Mutex
+	values      map[string]string
+	hashes      map[string]map[string]string
+	sets        map[string]map[string]struct{}
+	zsets       map[string]map[string]float64
+	subscribers map[string]map[*fakeRedisConn]struct{}
+	commands    []string
+}
+
+type fakeRedisConn struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+type fakeStatus string
+
+// newFakeRedis はfakeRedisを起動し、接続したクライアントを返します
+func newFakeRedis(t *testing.T) (*fakeRedis, *redis.Client) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	f := &fakeRedis{
+		values:      make(map[string]string),
+		hashes:      make(map[string]map[string]string),
+		sets:        make(map[string]map[string]struct{}),
+		zsets:       make(map[string]map[string]float64),
+		subscribers: make(map[string]map[*fakeRedisConn]struct{}),
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{Addr: listener.Addr().String()})
+	t.Cleanup(func() {
+		client.Close()
+		listener.Close()
+	})
+	return f, client
+}
+
+// executed は実行されたコマンド名（小文字）を返します
+func (f *fakeRedis) executed() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.commands...)
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+
+	c := &fakeRedisConn{w: bufio.NewWriter(conn)}
+	defer f.unsubscribe(c)
+
+	r := bufio.NewReader(conn)
+	var queued [][]string
+	inMulti := false
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+
+		name := strings.ToLower(args[0])
+		switch {
+		case name == "multi":
+			inMulti = true
+			c.write(fakeStatus("OK"))
+		case name == "exec":
+			f.mu.Lock()
+			replies := make([]interface{}, len(queued))
+			for i, cmd := range queued {
+				replies[i] = f.exec(cmd)
+			}
+			f.mu.Unlock()
+			queued, inMulti = nil, false
+			c.write(replies)
+		case inMulti:
+			queued = append(queued, args)
+			c.write(fakeStatus("QUEUED"))
+		case name == "subscribe":
+			f.subscribe(c, args[1:])
+		default:
+			f.mu.Lock()
+			reply := f.exec(args)
+			f.mu.Unlock()
+			c.write(reply)
+		}
+	}
+}
+
+func (f *fakeRedis) subscribe(c *fakeRedisConn, channels []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, channel := range channels {
+		if f.subscribers[channel] == nil {
+			f.subscribers[channel] = make(map[*fakeRedisConn]struct{})
+		}
+		f.subscribers[channel][c] = struct{}{}
+		c.write([]interface{}{"subscribe", channel, int64(i + 1)})
+	}
+}
+
+func (f *fakeRedis) unsubscribe(c *fakeRedisConn) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, conns := range f.subscribers {
+		delete(conns, c)
+	}
+}
+
+// exec はf.muを持った状態で1つのコマンドを実行します
+func (f *fakeRedis) exec(args []string) interface{} {
+	name := strings.ToLower(args[0])
+	f.commands = append(f.commands, name)
+
+	switch name {
+	case "ping":
+		return fakeStatus("PONG")
+	case "get":
+		if v, ok := f.values[args[1]]; ok {
+			return v
+		}
+		return nil
+	case "getdel":
+		v, ok := f.values[args[1]]
+		if !ok {
+			return nil
+		}
+		delete(f.values, args[1])
+		return v
+	case "mget":
+		replies := make([]interface{}, len(args)-1)
+		for i, key := range args[1:] {
+			if v, ok := f.values[key]; ok {
+				replies[i] = v
+			}
+		}
+		return replies
+	case "set":
+		for _, opt := range args[3:] {
+			if strings.EqualFold(opt, "nx") && f.exists(args[1]) {
+				return nil
+			}
+		}
+		f.values[args[1]] = args[2]
+		return fakeStatus("OK")
+	case "incr", "incrby":
+		delta := int64(1)
+		if name == "incrby" {
+			delta, _ = strconv.ParseInt(args[2], 10, 64)
+		}
+		n, _ := strconv.ParseInt(f.values[args[1]], 10, 64)
+		n += delta
+		f.values[args[1]] = strconv.FormatInt(n, 10)
+		return n
+	case "del", "unlink":
+		var n int64
+		for _, key := range args[1:] {
+			if f.exists(key) {
+				n++
+			}
+			f.delete(key)
+		}
+		return n
+	case "exists":
+		var n int64
+		for _, key := range args[1:] {
+			if f.exists(key) {
+				n++
+			}
+		}
+		return n
+	case "expire", "pexpire", "expireat", "pexpireat":
+		if f.exists(args[1]) {
+			return int64(1)
+		}
+		return int64(0)
+	case "rename":
+		if !f.exists(args[1]) {
+			return errors.New("ERR no such key")
+		}
+		f.delete(args[2])
+		if v, ok := f.values[args[1]]; ok {
+			f.values[args[2]] = v
+		}
+		if h, ok := f.hashes[args[1]]; ok {
+			f.hashes[args[2]] = h
+		}
+		if s, ok := f.sets[args[1]]; ok {
+			f.sets[args[2]] = s
+		}
+		if z, ok := f.zsets[args[1]]; ok {
+			f.zsets[args[2]] = z
+		}
+		f.delete(args[1])
+		return fakeStatus("OK")
+	case "hincrby":
+		h := f.hash(args[1])
+		n, _ := strconv.ParseInt(h[args[2]], 10, 64)
+		delta, _ := strconv.ParseInt(args[3], 10, 64)
+		h[args[2]] = strconv.FormatInt(n+delta, 10)
+		return n + delta
+	case "hgetall":
+		var replies []interface{}
+		for field, v := range f.hashes[args[1]] {
+			replies = append(replies, field, v)
+		}
+		return replies
+	case "hmget":
+		replies := make([]interface{}, len(args)-2)
+		for i, field := range args[2:] {
+			if v, ok := f.hashes[args[1]][field]; ok {
+				replies[i] = v
+			}
+		}
+		return replies
+	case "sadd":
+		s := f.set(args[1])
+		var n int64
+		for _, member := range args[2:] {
+			if _, ok := s[member]; !ok {
+				s[member] = struct{}{}
+				n++
+			}
+		}
+		return n
+	case "srem":
+		var n int64
+		for _, member := range args[2:] {
+			if _, ok := f.sets[args[1]][member]; ok {
+				delete(f.sets[args[1]], member)
+				n++
+			}
+		}
+		if len(f.sets[args[1]]) == 0 {
+			delete(f.sets, args[1])
+		}
+		return n
+	case "smembers":
+		replies := []interface{}{}
+		for member := range f.sets[args[1]] {
+			replies = append(replies, member)
+		}
+		return replies
+	case "zadd":
+		z := f.zset(args[1])
+		rest := args[2:]
+		for len(rest) > 0 && !isNumber(rest[0]) {
+			rest = rest[1:] // NX/XX/GT/LT/CHは使わないため無視する
+		}
+		var n int64
+		for i := 0; i+1 < len(rest); i += 2 {
+			score, _ := strconv.ParseFloat(rest[i], 64)
+			if _, ok := z[rest[i+1]]; !ok {
+				n++
+			}
+			z[rest[i+1]] = score
+		}
+		return n
+	case "zrem":
+		var n int64
+		for _, member := range args[2:] {
+			if _, ok := f.zsets[args[1]][member]; ok {
+				delete(f.zsets[args[1]], member)
+				n++
+			}
+		}
+		if len(f.zsets[args[1]]) == 0 {
+			delete(f.zsets, args[1])
+		}
+		return n
+	case "zcard":
+		return int64(len(f.zsets[args[1]]))
+	case "zrevrange", "zrange":
+		members := f.sortedMembers(args[1])
+		if name == "zrevrange" {
+			for i, j := 0, len(members)-1; i < j; i, j = i+1, j-1 {
+				members[i], members[j] = members[j], members[i]
+			}
+		}
+		start, stop := rankRange(args[2], args[3], len(members))
+		replies := []interface{}{}
+		for _, member := range members[start:stop] {
+			replies = append(replies, member)
+		}
+		return replies
+	case "zremrangebyrank":
+		members := f.sortedMembers(args[1])
+		start, stop := rankRange(args[2], args[3], len(members))
+		for _, member := range members[start:stop] {
+			delete(f.zsets[args[1]], member)
+		}
+		return int64(stop - start)
+	case "publish":
+		var n int64
+		for c := range f.subscribers[args[1]] {
+			c.write([]interface{}{"message", args[1], args[2]})
+			n++
+		}
+		return n
+	}
+
+	return fmt.Errorf("ERR unknown command '%s'", args[0])
+}
+
+func (f *fakeRedis) exists(key string) bool {
+	_, v := f.values[key]
+	_, h := f.hashes[key]
+	_, s := f.sets[key]
+	_, z := f.zsets[key]
+	return v || h || s || z
+}
+
+func (f *fakeRedis) delete(key string) {
+	delete(f.values, key)
+	delete(f.hashes, key)
+	delete(f.sets, key)
+	delete(f.zsets, key)
+}
+
+func (f *fakeRedis) hash(key string) map[string]string {
+	if f.hashes[key] == nil {
+		f.hashes[key] = make(map[string]string)
+	}
+	return f.hashes[key]
+}
+
+func (f *fakeRedis) set(key string) map[string]struct{} {
+	if f.sets[key] == nil {
+		f.sets[key] = make(map[string]struct{})
+	}
+	return f.sets[key]
+}
+
+func (f *fakeRedis) zset(key string) map[string]float64 {
+	if f.zsets[key] == nil {
+		f.zsets[key] = make(map[string]float64)
+	}
+	return f.zsets[key]
+}
+
+// sortedMembers はRedisと同じくスコアの昇順（同じスコアはメンバーの辞書順）でメンバーを返します
+func (f *fakeRedis) sortedMembers(key string) []string {
+	z := f.zsets[key]
+	members := make([]string, 0, len(z))
+	for member := range z {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if z[members[i]] != z[members[j]] {
+			return z[members[i]] < z[members[j]]
+		}
+		return members[i] < members[j]
+	})
+	return members
+}
+
+// rankRange はRedisのstart/stop（負数は末尾から、stopを含む）をスライスの範囲に変換します
+func rankRange(startArg, stopArg string, n int) (int, int) {
+	start, _ := strconv.Atoi(startArg)
+	stop, _ := strconv.Atoi(stopArg)
+	if start < 0 {
+		start += n
+	}
+	if stop < 0 {
+		stop += n
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= n {
+		stop = n - 1
+	}
+	if start > stop {
+		return 0, 0
+	}
+	return start, stop + 1
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid array header %q", line)
+	}
+	args := make([]string, n)
+	for i := range args {
+		header, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimPrefix(header, "$"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid bulk header %q", header)
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (c *fakeRedisConn) write(reply interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	writeReply(c.w, reply)
+	c.w.Flush()
+}
+
+func writeReply(w *bufio.Writer, reply interface{}) {
+	switch v := reply.(type) {
+	case nil:
+		w.WriteString("$-1\r\n")
+	case fakeStatus:
+		fmt.Fprintf(w, "+%s\r\n", v)
+	case error:
+		fmt.Fprintf(w, "-%s\r\n", v)
+	case int64:
+		fmt.Fprintf(w, ":%d\r\n", v)
+	case string:
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+	case []interface{}:
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, item := range v {
+			writeReply(w, item)
+		}
+	default:
+		panic(fmt.Sprintf("fakeRedis: unsupported reply %T", reply))
+	}
+}
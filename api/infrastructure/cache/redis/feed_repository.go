@@ -0,0 +1,266 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+const (
+	// feedMaxLength はRedisに保持するタイムラインの最大件数（これより古いページはMySQLから取得）
+	feedMaxLength = 800
+	// feedTTL は読まれなくなったタイムラインを破棄するまでの時間
+	feedTTL = 24 * time.Hour
+)
+
+// redisFeedRepository はユーザーごとのタイムラインをSorted Set（feed:{version}:{userID}）で保持する（fan-out-on-write）。
+// メンバーは桁を揃えた投稿ID、スコアはpublished_at。投稿本体はpostRepo（post:{id}キャッシュ）から取得します。
+// タイムラインが存在しない場合はbaseRepo（fan-out-on-read）から再構築します。
+type redisFeedRepository struct {
+	baseRepo    domain.FeedRepository
+	postRepo    domain.PostRepository
+	redisClient *redis.Client
+}
+
+// NewRedisFeedRepository creates a fan-out-on-write feed repository backed by Redis sorted sets
+func NewRedisFeedRepository(baseRepo domain.FeedRepository, postRepo domain.PostRepository, redisClient *redis.Client) domain.FeedRepository {
+	return &redisFeedRepository{
+		baseRepo:    baseRepo,
+		postRepo:    postRepo,
+		redisClient: redisClient,
+	}
+}
+
+func (r *redisFeedRepository) FindFeed(ctx context.Context, userID int64, limit, offset int) ([]domain.PostWithDetails, error) {
+	if offset+limit > feedMaxLength {
+		return r.baseRepo.FindFeed(ctx, userID, limit, offset)
+	}
+
+	cacheKey := getFeedCacheKey(getFeedVersion(ctx, r.redisClient), userID)
+
+	exists, err := r.redisClient.Exists(ctx, cacheKey).Result()
+	if err != nil || exists == 0 {
+		log.Printf("✗ Redis Cache MISS: %s - Rebuilding timeline from MySQL", cacheKey)
+		return r.rebuild(ctx, cacheKey, userID, limit, offset)
+	}
+
+	ids, err := r.redisClient.ZRevRange(ctx, cacheKey, int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return r.baseRepo.FindFeed(ctx, userID, limit, offset)
+	}
+	log.Printf("✓ Redis Cache HIT: %s (%d posts)", cacheKey, len(ids))
+	r.redisClient.Expire(ctx, cacheKey, feedTTL)
+
+	posts := make([]domain.PostWithDetails, 0, len(ids))
+	for _, member := range ids {
+		id, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		post, err := r.postRepo.FindByIDWithDetails(ctx, id)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// 書き込み後に非公開になった投稿は読み取り時にも除外する
+		if post.Status != domain.PostStatusPublished {
+			continue
+		}
+		posts = append(posts, *post)
+	}
+
+	return posts, nil
+}
+
+// rebuild はMySQLからタイムラインを取得してSorted Set（cacheKey）に書き戻し、要求されたページを返します
+func (r *redisFeedRepository) rebuild(ctx context.Context, cacheKey string, userID int64, limit, offset int) ([]domain.PostWithDetails, error) {
+	posts, err := r.baseRepo.FindFeed(ctx, userID, feedMaxLength, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(posts) > 0 {
+		members := make([]*redis.Z, 0, len(posts))
+		for _, post := range posts {
+			members = append(members, feedMember(&post.Post))
+		}
+
+		// 削除せずに追加する。MySQLから読んだ後に他のリクエストの再構築と配信が済んでいても、その投稿を消さないため
+		// （非公開になった投稿が残っても読み取り時に除外される）
+		pipe := r.redisClient.TxPipeline()
+		pipe.ZAdd(ctx, cacheKey, members...)
+		pipe.ZRemRangeByRank(ctx, cacheKey, 0, -feedMaxLength-1)
+		pipe.Expire(ctx, cacheKey, feedTTL)
+		if _, err := pipe.Exec(ctx); err == nil {
+			log.Printf("→ Redis Cache SET: %s (%d posts, TTL: %v)", cacheKey, len(posts), feedTTL)
+		}
+	}
+
+	if offset >= len(posts) {
+		return []domain.PostWithDetails{}, nil
+	}
+	end := offset + limit
+	if end > len(posts) {
+		end = len(posts)
+	}
+
+	return posts[offset:end], nil
+}
+
+// fanoutPostRepository は投稿の公開・非公開をフォロワーのタイムラインへ配信するDecoratorです。
+// 公開時は既に存在するタイムライン（feed:{version}:{followerID}）にのみ追加し、
+// 存在しないタイムラインは次回の読み取り時に再構築されます。
+type fanoutPostRepository struct {
+	domain.PostRepository
+	followRepo  domain.FollowRepository
+	redisClient *redis.Client
+}
+
+// NewFanoutPostRepository creates a post repository that pushes status changes to follower timelines
+func NewFanoutPostRepository(baseRepo domain.PostRepository, followRepo domain.FollowRepository, redisClient *redis.Client) domain.PostRepository {
+	return &fanoutPostRepository{
+		PostRepository: baseRepo,
+		followRepo:     followRepo,
+		redisClient:    redisClient,
+	}
+}
+
+func (r *fanoutPostRepository) Create(ctx context.Context, post *domain.Post, tagIDs []int64) error {
+	err := r.PostRepository.Create(ctx, post, tagIDs)
+	if err != nil {
+		return err
+	}
+
+	if post.Status == domain.PostStatusPublished {
+		r.fanout(ctx, post.ID)
+	}
+
+	return nil
+}
+
+func (r *fanoutPostRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	err := r.PostRepository.UpdateStatus(ctx, id, status)
+	if err != nil {
+		return err
+	}
+
+	r.fanout(ctx, id)
+	return nil
+}
+
+func (r *fanoutPostRepository) Delete(ctx context.Context, id int64) error {
+	err := r.PostRepository.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	r.fanout(ctx, id)
+	return nil
+}
+
+// fanout は投稿の現在の状態に合わせてフォロワーのタイムラインへ追加・削除します。
+// 配信の失敗は書き込み自体を失敗させず、ログに残します
+func (r *fanoutPostRepository) fanout(ctx context.Context, postID int64) {
+	post, err := r.PostRepository.FindByID(ctx, postID)
+	if err != nil {
+		log.Printf("Warning: feed fan-out skipped for post %d: %v", postID, err)
+		return
+	}
+
+	followerIDs, err := r.followRepo.FindFollowerIDs(ctx, post.UserID)
+	if err != nil {
+		log.Printf("Warning: feed fan-out skipped for post %d: %v", postID, err)
+		return
+	}
+	if len(followerIDs) == 0 {
+		return
+	}
+
+	version := getFeedVersion(ctx, r.redisClient)
+	keys := make([]string, len(followerIDs))
+	for i, id := range followerIDs {
+		keys[i] = getFeedCacheKey(version, id)
+	}
+
+	published := post.Status == domain.PostStatusPublished && post.PublishedAt != nil
+	if !published {
+		pipe := r.redisClient.Pipeline()
+		for _, key := range keys {
+			pipe.ZRem(ctx, key, getFeedMember(post.ID))
+		}
+		pipe.Exec(ctx)
+		log.Printf("⚠ Redis Cache INVALIDATE: post %d removed from %d timelines", post.ID, len(keys))
+		return
+	}
+
+	// 既存のタイムラインにだけ追加する（未構築のものを部分的に作らない）
+	pipe := r.redisClient.Pipeline()
+	existsCmds := make([]*redis.IntCmd, len(keys))
+	for i, key := range keys {
+		existsCmds[i] = pipe.Exists(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Warning: feed fan-out skipped for post %d: %v", postID, err)
+		return
+	}
+
+	member := feedMember(post)
+	pushed := 0
+	pipe = r.redisClient.Pipeline()
+	for i, key := range keys {
+		if existsCmds[i].Val() == 0 {
+			continue
+		}
+		pipe.ZAdd(ctx, key, member)
+		pipe.ZRemRangeByRank(ctx, key, 0, -feedMaxLength-1)
+		pushed++
+	}
+	if pushed > 0 {
+		pipe.Exec(ctx)
+	}
+	log.Printf("→ Redis Cache SET: post %d pushed to %d/%d timelines", post.ID, pushed, len(keys))
+}
+
+func feedMember(post *domain.Post) *redis.Z {
+	score := float64(post.CreatedAt.Unix())
+	if post.PublishedAt != nil {
+		score = float64(post.PublishedAt.Unix())
+	}
+	return &redis.Z{Score: score, Member: getFeedMember(post.ID)}
+}
+
+// getFeedMember は投稿IDを桁を揃えた文字列にします。スコア（公開時刻）が同じメンバーは辞書順に並ぶため、
+// 桁を揃えてMySQLのfan-out-on-readと同じIDの降順にする
+func getFeedMember(postID int64) string {
+	return fmt.Sprintf("%020d", postID)
+}
+
+// feedVersionKey は全タイムラインの世代番号。タイムラインのキーに含め、世代を進めると全て再構築される（古いキーはfeedTTLで消える）
+const feedVersionKey = "feed:version"
+
+// invalidateFeedCaches は全タイムラインの世代を進め、次回の読み取り時にMySQLから再構築させます。
+// 投稿者の停止・削除のように多数のタイムラインに影響する、まれな変更でのみ使います
+func invalidateFeedCaches(ctx context.Context, redisClient *redis.Client) {
+	redisClient.Incr(ctx, feedVersionKey)
+}
+
+func getFeedVersion(ctx context.Context, redisClient *redis.Client) int64 {
+	version, err := redisClient.Get(ctx, feedVersionKey).Int64()
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+func getFeedCacheKey(version, userID int64) string {
+	return fmt.Sprintf("feed:%d:%d", version, userID)
+}
@@ -0,0 +1,263 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// memoryPostRepository は投稿をメモリに保持します（フィードのテストで使うメソッドのみ実装）
+type memoryPostRepository struct {
+	domain.PostRepository
+	posts map[int64]*domain.Post
+	now   time.Time
+}
+
+func (r *memoryPostRepository) FindByID(ctx context.Context, id int64) (*domain.Post, error) {
+	post, ok := r.posts[id]
+	if !ok {
+		return nil, domain.NotFoundError("post")
+	}
+	copied := *post
+	return &copied, nil
+}
+
+func (r *memoryPostRepository) FindByIDWithDetails(ctx context.Context, id int64) (*domain.PostWithDetails, error) {
+	post, err := r.FindByID(ctx, id)
+	if err != nil || post.Status != domain.PostStatusPublished {
+		return nil, domain.NotFoundError("post")
+	}
+	return &domain.PostWithDetails{Post: *post}, nil
+}
+
+func (r *memoryPostRepository) Create(ctx context.Context, post *domain.Post, tagIDs []int64) error {
+	post.ID = int64(len(r.posts) + 1)
+	post.CreatedAt = r.now
+	if post.Status == domain.PostStatusPublished {
+		publishedAt := r.now
+		post.PublishedAt = &publishedAt
+	}
+	copied := *post
+	r.posts[post.ID] = &copied
+	return nil
+}
+
+func (r *memoryPostRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	post := r.posts[id]
+	post.Status = status
+	if status == domain.PostStatusPublished && post.PublishedAt == nil {
+		publishedAt := r.now
+		post.PublishedAt = &publishedAt
+	}
+	return nil
+}
+
+func (r *memoryPostRepository) Delete(ctx context.Context, id int64) error {
+	r.posts[id].Status = domain.PostStatusDeleted
+	return nil
+}
+
+// memoryFollowRepository はフォロー関係をメモリに保持します（follower → following）
+type memoryFollowRepository struct {
+	domain.FollowRepository
+	following map[int64][]int64
+}
+
+func (r *memoryFollowRepository) FindFollowerIDs(ctx context.Context, userID int64) ([]int64, error) {
+	var ids []int64
+	for followerID, following := range r.following {
+		for _, id := range following {
+			if id == userID {
+				ids = append(ids, followerID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// memoryFeedRepository はMySQLのfan-out-on-readと同じ条件・順序（published_at DESC, id DESC）でタイムラインを組み立てます
+type memoryFeedRepository struct {
+	posts   *memoryPostRepository
+	follows *memoryFollowRepository
+}
+
+func (r *memoryFeedRepository) FindFeed(ctx context.Context, userID int64, limit, offset int) ([]domain.PostWithDetails, error) {
+	authors := map[int64]bool{}
+	for _, id := range r.follows.following[userID] {
+		authors[id] = true
+	}
+
+	var posts []domain.PostWithDetails
+	for _, post := range r.posts.posts {
+		if authors[post.UserID] && post.Status == domain.PostStatusPublished && post.PublishedAt != nil {
+			posts = append(posts, domain.PostWithDetails{Post: *post})
+		}
+	}
+	sort.Slice(posts, func(i, j int) bool {
+		if !posts[i].PublishedAt.Equal(*posts[j].PublishedAt) {
+			return posts[i].PublishedAt.After(*posts[j].PublishedAt)
+		}
+		return posts[i].ID > posts[j].ID
+	})
+
+	if offset >= len(posts) {
+		return nil, nil
+	}
+	posts = posts[offset:]
+	if len(posts) > limit {
+		posts = posts[:limit]
+	}
+	return posts, nil
+}
+
+func feedPostIDs(posts []domain.PostWithDetails) []int64 {
+	ids := make([]int64, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+	return ids
+}
+
+func TestFanoutOnWriteMatchesFanoutOnRead(t *testing.T) {
+	_, redisClient := newFakeRedis(t)
+	ctx := context.Background()
+
+	posts := &memoryPostRepository{
+		posts: make(map[int64]*domain.Post),
+		now:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	follows := &memoryFollowRepository{following: map[int64][]int64{
+		1: {10, 11},
+		2: {11},
+	}}
+	readRepo := &memoryFeedRepository{posts: posts, follows: follows}
+	writeRepo := NewRedisFeedRepository(readRepo, posts, redisClient)
+	postRepo := NewFanoutPostRepository(posts, follows, redisClient)
+
+	// publish は投稿を作成し、フォロワーのタイムラインへ配信します。同じ秒に公開される投稿を含めるため、2件ごとに時刻を進めます
+	publish := func(authorID int64, status string) int64 {
+		t.Helper()
+		if len(posts.posts)%2 == 0 {
+			posts.now = posts.now.Add(time.Second)
+		}
+		post := &domain.Post{UserID: authorID, Status: status}
+		if err := postRepo.Create(ctx, post, nil); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return post.ID
+	}
+
+	// IDが1桁から2桁になる範囲（9と10）を同じ秒に公開する
+	for i := 0; i < 6; i++ {
+		publish(10+int64(i%2), domain.PostStatusPublished)
+	}
+
+	assertSameFeed := func(step string) {
+		t.Helper()
+		for _, userID := range []int64{1, 2} {
+			for _, page := range []struct{ limit, offset int }{{4, 0}, {4, 4}, {50, 0}} {
+				want, _ := readRepo.FindFeed(ctx, userID, page.limit, page.offset)
+				got, err := writeRepo.FindFeed(ctx, userID, page.limit, page.offset)
+				if err != nil {
+					t.Fatalf("%s: Expected no error, got %v", step, err)
+				}
+				if fmt.Sprint(feedPostIDs(got)) != fmt.Sprint(feedPostIDs(want)) {
+					t.Errorf("%s: user %d limit=%d offset=%d: fan-out-on-write returned %v, fan-out-on-read %v",
+						step, userID, page.limit, page.offset, feedPostIDs(got), feedPostIDs(want))
+				}
+			}
+		}
+	}
+
+	// タイムラインが無い状態（MySQLから再構築）
+	assertSameFeed("rebuild")
+
+	// 構築済みのタイムラインへの配信
+	draftID := publish(10, domain.PostStatusDraft)
+	for i := 0; i < 6; i++ {
+		publish(10+int64(i%2), domain.PostStatusPublished)
+	}
+	assertSameFeed("fan-out")
+
+	// 下書きの公開、公開中の投稿のアーカイブ・削除
+	posts.now = posts.now.Add(time.Second)
+	for _, change := range []struct {
+		id     int64
+		status string
+	}{
+		{draftID, domain.PostStatusPublished},
+		{2, domain.PostStatusArchived},
+	} {
+		if err := postRepo.UpdateStatus(ctx, change.id, change.status); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if err := postRepo.Delete(ctx, 9); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assertSameFeed("status changes")
+}
+
+// interleavedFeedRepository はMySQLからの読み取り直後に、一度だけafterReadを実行します
+type interleavedFeedRepository struct {
+	domain.FeedRepository
+	afterRead func()
+}
+
+func (r *interleavedFeedRepository) FindFeed(ctx context.Context, userID int64, limit, offset int) ([]domain.PostWithDetails, error) {
+	posts, err := r.FeedRepository.FindFeed(ctx, userID, limit, offset)
+	if afterRead := r.afterRead; afterRead != nil {
+		r.afterRead = nil
+		afterRead()
+	}
+	return posts, err
+}
+
+func TestRebuildKeepsPostsFannedOutDuringRebuild(t *testing.T) {
+	_, redisClient := newFakeRedis(t)
+	ctx := context.Background()
+
+	posts := &memoryPostRepository{
+		posts: make(map[int64]*domain.Post),
+		now:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	follows := &memoryFollowRepository{following: map[int64][]int64{1: {10}}}
+	readRepo := &interleavedFeedRepository{FeedRepository: &memoryFeedRepository{posts: posts, follows: follows}}
+	writeRepo := NewRedisFeedRepository(readRepo, posts, redisClient)
+	postRepo := NewFanoutPostRepository(posts, follows, redisClient)
+
+	publish := func() int64 {
+		t.Helper()
+		posts.now = posts.now.Add(time.Second)
+		post := &domain.Post{UserID: 10, Status: domain.PostStatusPublished}
+		if err := postRepo.Create(ctx, post, nil); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return post.ID
+	}
+	first := publish()
+
+	// 再構築がMySQLから読んだ後、書き込む前に、他のリクエストの再構築と新しい投稿の配信が済む
+	var second int64
+	readRepo.afterRead = func() {
+		if _, err := writeRepo.FindFeed(ctx, 1, 20, 0); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		second = publish()
+	}
+	if _, err := writeRepo.FindFeed(ctx, 1, 20, 0); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	got, err := writeRepo.FindFeed(ctx, 1, 20, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if fmt.Sprint(feedPostIDs(got)) != fmt.Sprint([]int64{second, first}) {
+		t.Errorf("Expected the post fanned out during the rebuild to stay in the timeline, got %v", feedPostIDs(got))
+	}
+}
@@ -0,0 +1,121 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+const (
+	// notificationChannel は全インスタンスが購読する通知イベントのチャンネル
+	notificationChannel = "notifications:events"
+	// subscriberBuffer は接続ごとの未送信イベントの上限（超えた接続は切断し、Last-Event-IDで再開させる）
+	subscriberBuffer = 64
+)
+
+// notificationEvent はRedis pub/subで配信する通知イベント
+type notificationEvent struct {
+	UserID       int64                   `json:"userId"`
+	Notification domain.UserNotification `json:"notification"`
+}
+
+// notificationBroker はRedis pub/subで通知を全APIインスタンスへ配信します。
+// インスタンスごとに1つだけRedisを購読し、受信したイベントをローカルの接続（userID単位）へ振り分けます。
+type notificationBroker struct {
+	redisClient *redis.Client
+	startOnce   sync.Once
+
+	mu          sync.Mutex
+	subscribers map[int64]map[chan domain.UserNotification]struct{}
+}
+
+// NewNotificationBroker creates a notification broker backed by Redis pub/sub
+func NewNotificationBroker(redisClient *redis.Client) domain.NotificationBroker {
+	return &notificationBroker{
+		redisClient: redisClient,
+		subscribers: make(map[int64]map[chan domain.UserNotification]struct{}),
+	}
+}
+
+func (b *notificationBroker) Publish(ctx context.Context, userID int64, notification *domain.UserNotification) error {
+	data, err := json.Marshal(notificationEvent{UserID: userID, Notification: *notification})
+	if err != nil {
+		return err
+	}
+
+	return b.redisClient.Publish(ctx, notificationChannel, data).Err()
+}
+
+func (b *notificationBroker) Subscribe(ctx context.Context, userID int64) (<-chan domain.UserNotification, error) {
+	b.startOnce.Do(b.start)
+
+	ch := make(chan domain.UserNotification, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan domain.UserNotification]struct{})
+	}
+	b.subscribers[userID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.remove(userID, ch)
+	}()
+
+	return ch, nil
+}
+
+// start はRedisの購読を開始し、受信したイベントをローカルの接続へ振り分けます
+func (b *notificationBroker) start() {
+	pubsub := b.redisClient.Subscribe(context.Background(), notificationChannel)
+	log.Printf("Subscribed to Redis channel: %s", notificationChannel)
+
+	go func() {
+		for msg := range pubsub.Channel() {
+			var event notificationEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				log.Printf("Warning: invalid notification event: %v", err)
+				continue
+			}
+			b.dispatch(event)
+		}
+	}()
+}
+
+func (b *notificationBroker) dispatch(event notificationEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[event.UserID] {
+		select {
+		case ch <- event.Notification:
+		default:
+			// 送信が追いつかない接続は閉じる（クライアントはLast-Event-IDで再接続して取りこぼしを取得）
+			b.removeLocked(event.UserID, ch)
+		}
+	}
+}
+
+func (b *notificationBroker) remove(userID int64, ch chan domain.UserNotification) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removeLocked(userID, ch)
+}
+
+func (b *notificationBroker) removeLocked(userID int64, ch chan domain.UserNotification) {
+	subs := b.subscribers[userID]
+	if _, ok := subs[ch]; !ok {
+		return
+	}
+
+	delete(subs, ch)
+	close(ch)
+	if len(subs) == 0 {
+		delete(b.subscribers, userID)
+	}
+}
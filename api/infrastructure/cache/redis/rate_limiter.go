@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// rateLimiter はRedisのINCRによる固定ウィンドウのレートリミッターです。
+// INCRと同じトランザクションでEXPIRE NX（Redis 7+）を送り、ウィンドウ最初のリクエストでだけTTLを付けます。
+// キーの期限切れでカウントがリセットされます
+type rateLimiter struct {
+	redisClient *redis.Client
+}
+
+// NewRateLimiter creates a Redis-backed fixed window rate limiter
+func NewRateLimiter(redisClient *redis.Client) domain.RateLimiter {
+	return &rateLimiter{redisClient: redisClient}
+}
+
+func (l *rateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	redisKey := getRateLimitKey(key)
+
+	pipe := l.redisClient.TxPipeline()
+	incr := pipe.Incr(ctx, redisKey)
+	pipe.ExpireNX(ctx, redisKey, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, fmt.Errorf("failed to increment rate limit: %w", err)
+	}
+
+	count := incr.Val()
+
+	if count > int64(limit) {
+		log.Printf("⚠ Redis RATE LIMIT: %s (%d/%d)", redisKey, count, limit)
+		return false, nil
+	}
+	return true, nil
+}
+
+func getRateLimitKey(key string) string {
+	return fmt.Sprintf("rate_limit:%s", key)
+}
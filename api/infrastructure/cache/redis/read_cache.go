@@ -0,0 +1,103 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/infrastructure/cache"
+)
+
+// ReadCacheConfig はread-throughキャッシュの有効期限とスタンピード対策の設定
+type ReadCacheConfig struct {
+	// SoftTTL を過ぎた値は古いまま返し、裏で1回だけ再取得する
+	SoftTTL time.Duration
+	// HardTTL はRedisのキー自体の有効期限（SoftTTL以上）。過ぎた値は返さない
+	HardTTL time.Duration
+	// LockTTL が正の場合、キャッシュミス時にRedisのロックで複数インスタンスからの同時読み込みを1つに絞る
+	LockTTL time.Duration
+	// L1 が設定されている場合、Redisの前にプロセス内のLRUでデコード済みの値と一覧の世代番号を返す（SubscribeL1Invalidationsで無効化を受け取ること）
+	L1 *cache.LRU
+}
+
+// DefaultReadCacheConfig は5分で再取得し、さらに5分間は古い値を返す設定（分散ロックなし）
+func DefaultReadCacheConfig() ReadCacheConfig {
+	return ReadCacheConfig{
+		SoftTTL: 5 * time.Minute,
+		HardTTL: 10 * time.Minute,
+	}
+}
+
+func (c ReadCacheConfig) ttl() cache.TTL {
+	return cache.TTL{Soft: c.SoftTTL, Hard: c.HardTTL}
+}
+
+// newReadThrough はRedisに値を保存する型付きのread-throughキャッシュを作ります（MySQLから読み込む前提のログを出力）。
+// cloneはL1の値を呼び出し側の変更から守るための複製で、変更されない型（int64など）ではnilを渡します
+func newReadThrough[T any](redisClient *redis.Client, config ReadCacheConfig, clone func(T) T) *cache.ReadThrough[T] {
+	return cache.New(newRedisStore(redisClient), cache.Options[T]{
+		TTL:     cache.FixedTTL(config.ttl()),
+		LockTTL: config.LockTTL,
+		L1:      config.L1,
+		Clone:   clone,
+		Hooks:   cache.LogHooks("MySQL"),
+	})
+}
+
+// clonePointer はポインタの指す構造体を複製します。
+// 浅い複製のため、呼び出し側は入れ子のスライス（投稿のタグなど）を変更しない前提です
+func clonePointer[E any](value *E) *E {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+// redisStore はcache.StoreのRedis実装です。ロックは {key}:lock へのSET NXで、解除は自分のトークンの場合だけ行います
+type redisStore struct {
+	redisClient *redis.Client
+}
+
+func newRedisStore(redisClient *redis.Client) cache.Store {
+	return &redisStore{redisClient: redisClient}
+}
+
+func (s *redisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.redisClient.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (s *redisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.redisClient.Set(ctx, key, value, ttl).Err()
+}
+
+func (s *redisStore) Lock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	token, err := newLockToken()
+	if err != nil {
+		return nil, false, err
+	}
+
+	lockKey := getCacheLockKey(key)
+	acquired, err := s.redisClient.SetNX(ctx, lockKey, token, ttl).Result()
+	if err != nil || !acquired {
+		return nil, false, err
+	}
+
+	unlock := func() {
+		unlockScript.Run(context.Background(), s.redisClient, []string{lockKey}, token)
+	}
+	return unlock, true, nil
+}
+
+func getCacheLockKey(key string) string {
+	return fmt.Sprintf("%s:lock", key)
+}
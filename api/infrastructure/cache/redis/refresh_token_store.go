@@ -0,0 +1,105 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// refreshTokenStore は有効なリフレッシュトークンのjtiをRedisに保持します。
+// キーが存在する間だけトークンは有効で、失効はキー削除で行います。
+// ユーザーごとのSETで発行済みjtiを追跡し、RevokeAllで一括失効できるようにしています。
+// RevokeAllはセッションバージョン（session_version:{userID}）も上げ、発行済みのアクセストークンも無効にします。
+type refreshTokenStore struct {
+	redisClient *redis.Client
+}
+
+// NewRefreshTokenStore creates a Redis-backed refresh token store
+func NewRefreshTokenStore(redisClient *redis.Client) domain.RefreshTokenStore {
+	return &refreshTokenStore{redisClient: redisClient}
+}
+
+func (s *refreshTokenStore) Save(ctx context.Context, userID int64, tokenID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return domain.ErrInvalidToken
+	}
+
+	setKey := getRefreshTokenSetKey(userID)
+	pipe := s.redisClient.TxPipeline()
+	pipe.Set(ctx, getRefreshTokenKey(userID, tokenID), 1, ttl)
+	pipe.SAdd(ctx, setKey, tokenID)
+	// SETは最も新しいトークンが切れるまで保持する
+	pipe.ExpireAt(ctx, setKey, expiresAt)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
+	return nil
+}
+
+func (s *refreshTokenStore) Revoke(ctx context.Context, userID int64, tokenID string) error {
+	pipe := s.redisClient.TxPipeline()
+	deleted := pipe.Del(ctx, getRefreshTokenKey(userID, tokenID))
+	pipe.SRem(ctx, getRefreshTokenSetKey(userID), tokenID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	// 同時に失効させた場合はDELで実際に削除した1つだけが成功する
+	if deleted.Val() == 0 {
+		return domain.ErrInvalidToken
+	}
+
+	return nil
+}
+
+func (s *refreshTokenStore) RevokeAll(ctx context.Context, userID int64) error {
+	setKey := getRefreshTokenSetKey(userID)
+	tokenIDs, err := s.redisClient.SMembers(ctx, setKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list refresh tokens: %w", err)
+	}
+
+	keys := make([]string, 0, len(tokenIDs)+1)
+	for _, tokenID := range tokenIDs {
+		keys = append(keys, getRefreshTokenKey(userID, tokenID))
+	}
+	keys = append(keys, setKey)
+
+	pipe := s.redisClient.TxPipeline()
+	pipe.Del(ctx, keys...)
+	pipe.Incr(ctx, getSessionVersionKey(userID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	log.Printf("⚠ Redis REVOKE: %d refresh tokens for user %d", len(tokenIDs), userID)
+	return nil
+}
+
+func (s *refreshTokenStore) SessionVersion(ctx context.Context, userID int64) (int64, error) {
+	version, err := s.redisClient.Get(ctx, getSessionVersionKey(userID)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get session version: %w", err)
+	}
+	return version, nil
+}
+
+func getRefreshTokenKey(userID int64, tokenID string) string {
+	return fmt.Sprintf("refresh_token:%d:%s", userID, tokenID)
+}
+
+func getRefreshTokenSetKey(userID int64) string {
+	return fmt.Sprintf("refresh_tokens:%d", userID)
+}
+
+func getSessionVersionKey(userID int64) string {
+	return fmt.Sprintf("session_version:%d", userID)
+}
@@ -0,0 +1,92 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+func TestRefreshTokenStoreRevokeSucceedsOnce(t *testing.T) {
+	_, redisClient := newFakeRedis(t)
+	store := NewRefreshTokenStore(redisClient)
+	ctx := context.Background()
+
+	if err := store.Save(ctx, 1, "jti1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := store.Revoke(ctx, 1, "jti1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := store.Revoke(ctx, 1, "jti1"); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for a revoked token, got %v", err)
+	}
+	if err := store.Revoke(ctx, 1, "unknown"); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for an unknown token, got %v", err)
+	}
+}
+
+func TestRefreshTokenStoreConcurrentRevoke(t *testing.T) {
+	_, redisClient := newFakeRedis(t)
+	store := NewRefreshTokenStore(redisClient)
+	ctx := context.Background()
+
+	if err := store.Save(ctx, 1, "jti1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	const attempts = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- store.Revoke(ctx, 1, "jti1")
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, domain.ErrInvalidToken):
+			t.Errorf("Expected ErrInvalidToken, got %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("Expected exactly 1 revoke to succeed, got %d", succeeded)
+	}
+}
+
+func TestRefreshTokenStoreRevokeAllBumpsSessionVersion(t *testing.T) {
+	_, redisClient := newFakeRedis(t)
+	store := NewRefreshTokenStore(redisClient)
+	ctx := context.Background()
+
+	if version, err := store.SessionVersion(ctx, 1); err != nil || version != 0 {
+		t.Fatalf("Expected version 0, got %d (%v)", version, err)
+	}
+	if err := store.Save(ctx, 1, "jti1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := store.RevokeAll(ctx, 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if version, err := store.SessionVersion(ctx, 1); err != nil || version != 1 {
+		t.Errorf("Expected version 1 after RevokeAll, got %d (%v)", version, err)
+	}
+	if version, _ := store.SessionVersion(ctx, 2); version != 0 {
+		t.Errorf("Expected other users to keep version 0, got %d", version)
+	}
+	if err := store.Revoke(ctx, 1, "jti1"); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for a token revoked by RevokeAll, got %v", err)
+	}
+}
@@ -0,0 +1,232 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+const (
+	// viewsPendingKey は未反映の閲覧数を投稿IDごとに保持するHash（HINCRBYで加算）
+	viewsPendingKey = "posts:views:pending"
+	// viewsFlushingKey はフラッシュ中の閲覧数。DBへの反映に失敗した場合は残し、次回のフラッシュで再送する
+	viewsFlushingKey = "posts:views:flushing"
+	// viewsFlushLockKey は複数インスタンスのフラッシュを1つに絞るロック。フラッシュ中はTTLの1/3ごとに延長する
+	viewsFlushLockKey = "posts:views:flush_lock"
+	viewsFlushLockTTL = 30 * time.Second
+)
+
+// unlockScript はロックの値（トークン）が自分のものの場合だけ削除します
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// renewLockScript はロックの値（トークン）が自分のものの場合だけ有効期限（ミリ秒）を延ばします
+var renewLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// clearFlushedViewsScript はロック（KEYS[1]）が自分のものの場合だけフラッシュ済みの閲覧数（KEYS[2]）を削除します。
+// ロックを失っていれば-1を返します
+var clearFlushedViewsScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[2])
+end
+return -1
+`)
+
+// redisViewCounter は投稿の閲覧数をRedisのHashに溜め、Flushでまとめてpost repositoryへ反映します。
+// dedupWindowが正の場合は、同じ閲覧者の同じ投稿への閲覧をウィンドウごとのHyperLogLog
+// （post:{id}:viewers:{bucket}）で判定し、1回だけ数えます
+type redisViewCounter struct {
+	redisClient *redis.Client
+	postRepo    domain.PostRepository
+	dedupWindow time.Duration
+}
+
+// NewRedisViewCounter creates a view counter that buffers views in Redis (dedupWindow 0 disables deduplication)
+func NewRedisViewCounter(redisClient *redis.Client, postRepo domain.PostRepository, dedupWindow time.Duration) domain.ViewCounter {
+	return &redisViewCounter{
+		redisClient: redisClient,
+		postRepo:    postRepo,
+		dedupWindow: dedupWindow,
+	}
+}
+
+func (c *redisViewCounter) Record(ctx context.Context, postID int64, viewer string) error {
+	if c.dedupWindow > 0 && viewer != "" {
+		viewersKey := getPostViewersKey(postID, time.Now().UnixNano()/int64(c.dedupWindow))
+
+		pipe := c.redisClient.TxPipeline()
+		added := pipe.PFAdd(ctx, viewersKey, viewer)
+		pipe.Expire(ctx, viewersKey, c.dedupWindow)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("failed to record viewer: %w", err)
+		}
+		if added.Val() == 0 {
+			return nil
+		}
+	}
+
+	if err := c.redisClient.HIncrBy(ctx, viewsPendingKey, strconv.FormatInt(postID, 10), 1).Err(); err != nil {
+		return fmt.Errorf("failed to record view: %w", err)
+	}
+
+	return nil
+}
+
+func (c *redisViewCounter) Pending(ctx context.Context, postIDs []int64) (map[int64]int64, error) {
+	pending := make(map[int64]int64, len(postIDs))
+	if len(postIDs) == 0 {
+		return pending, nil
+	}
+
+	fields := make([]string, len(postIDs))
+	for i, id := range postIDs {
+		fields[i] = strconv.FormatInt(id, 10)
+	}
+
+	pipe := c.redisClient.Pipeline()
+	buffered := pipe.HMGet(ctx, viewsPendingKey, fields...)
+	flushing := pipe.HMGet(ctx, viewsFlushingKey, fields...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get pending views: %w", err)
+	}
+
+	for _, values := range [][]interface{}{buffered.Val(), flushing.Val()} {
+		for i, value := range values {
+			s, ok := value.(string)
+			if !ok {
+				continue
+			}
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				pending[postIDs[i]] += n
+			}
+		}
+	}
+
+	return pending, nil
+}
+
+func (c *redisViewCounter) Flush(ctx context.Context) (int, error) {
+	token, err := newLockToken()
+	if err != nil {
+		return 0, err
+	}
+	locked, err := c.redisClient.SetNX(ctx, viewsFlushLockKey, token, viewsFlushLockTTL).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire flush lock: %w", err)
+	}
+	if !locked {
+		// 他のインスタンスがフラッシュ中
+		return 0, nil
+	}
+	defer unlockScript.Run(context.Background(), c.redisClient, []string{viewsFlushLockKey}, token)
+
+	// 反映が長引いてもロックが切れて他のインスタンスが同じ分を再送しないよう、終わるまでロックを延長する。
+	// 延長できなかった場合は反映を中止する（コミット前ならロールバックされ、次回再送する）
+	flushCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go c.renewFlushLock(flushCtx, cancel, token)
+
+	// 前回反映に失敗した分が残っていればそれを先に送る。なければ溜まった分をフラッシュ用のキーへ移す
+	// （RENAME後のHINCRBYは新しいHashに加算される）
+	n, err := c.redisClient.Exists(ctx, viewsFlushingKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check flushing views: %w", err)
+	}
+	if n == 0 {
+		n, err = c.redisClient.Exists(ctx, viewsPendingKey).Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to check pending views: %w", err)
+		}
+		if n == 0 {
+			return 0, nil
+		}
+		if err := c.redisClient.Rename(ctx, viewsPendingKey, viewsFlushingKey).Err(); err != nil {
+			return 0, fmt.Errorf("failed to move pending views: %w", err)
+		}
+	}
+
+	values, err := c.redisClient.HGetAll(ctx, viewsFlushingKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get flushing views: %w", err)
+	}
+
+	deltas := make(map[int64]int64, len(values))
+	for field, value := range values {
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			continue
+		}
+		delta, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || delta <= 0 {
+			continue
+		}
+		deltas[id] = delta
+	}
+
+	if err := c.postRepo.AddViewCounts(flushCtx, deltas); err != nil {
+		return 0, fmt.Errorf("failed to flush views: %w", err)
+	}
+
+	// DBへの反映後・削除前に落ちた場合は次回同じ分を再送する（at-least-once）
+	cleared, err := clearFlushedViewsScript.Run(ctx, c.redisClient, []string{viewsFlushLockKey, viewsFlushingKey}, token).Int()
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear flushed views: %w", err)
+	}
+	if cleared < 0 {
+		return 0, fmt.Errorf("failed to clear flushed views: flush lock was lost")
+	}
+	log.Printf("→ Redis VIEWS FLUSH: %d posts", len(deltas))
+
+	return len(deltas), nil
+}
+
+// renewFlushLock はctxが終わるまでフラッシュのロックを延長し続け、延長できなければcancelを呼びます
+func (c *redisViewCounter) renewFlushLock(ctx context.Context, cancel context.CancelFunc, token string) {
+	ticker := time.NewTicker(viewsFlushLockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			renewed, err := renewLockScript.Run(ctx, c.redisClient, []string{viewsFlushLockKey}, token, viewsFlushLockTTL.Milliseconds()).Int()
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil || renewed == 0 {
+				log.Printf("Warning: failed to renew views flush lock, aborting flush: %v", err)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func getPostViewersKey(postID, bucket int64) string {
+	return fmt.Sprintf("post:%d:viewers:%d", postID, bucket)
+}
@@ -0,0 +1,136 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+const (
+	// maxSourcePixels はデコードを許可する最大画素数（小さなファイルで巨大な画像を展開させない）
+	maxSourcePixels = 40_000_000
+	maxSourceSide   = 10_000
+	jpegQuality     = 85
+)
+
+type thumbnailer struct{}
+
+// NewThumbnailer creates an image processor for JPEG, PNG and GIF using only the standard library
+func NewThumbnailer() domain.ImageProcessor {
+	return thumbnailer{}
+}
+
+// Thumbnails center-crops the image to a square and resizes it to each size.
+// JPEG is re-encoded as JPEG; PNG and GIF (first frame) as PNG to keep transparency
+func (thumbnailer) Thumbnails(data []byte, sizes []int) ([]domain.Thumbnail, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: unsupported image format", domain.ErrInvalidAvatar)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width > maxSourceSide || config.Height > maxSourceSide ||
+		config.Width*config.Height > maxSourcePixels {
+		return nil, fmt.Errorf("%w: image dimensions %dx%d are too large", domain.ErrInvalidAvatar, config.Width, config.Height)
+	}
+
+	var src image.Image
+	switch format {
+	case "jpeg":
+		src, err = jpeg.Decode(bytes.NewReader(data))
+	case "png":
+		src, err = png.Decode(bytes.NewReader(data))
+	case "gif":
+		src, err = gif.Decode(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("%w: unsupported image format %s", domain.ErrInvalidAvatar, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode %s", domain.ErrInvalidAvatar, format)
+	}
+
+	square := cropSquare(src)
+
+	thumbnails := make([]domain.Thumbnail, 0, len(sizes))
+	for _, size := range sizes {
+		resized := resize(square, size)
+
+		var buf bytes.Buffer
+		thumbnail := domain.Thumbnail{Size: size}
+		if format == "jpeg" {
+			thumbnail.ContentType, thumbnail.Extension = "image/jpeg", "jpg"
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: jpegQuality})
+		} else {
+			thumbnail.ContentType, thumbnail.Extension = "image/png", "png"
+			err = png.Encode(&buf, resized)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+		}
+		thumbnail.Data = buf.Bytes()
+		thumbnails = append(thumbnails, thumbnail)
+	}
+
+	return thumbnails, nil
+}
+
+// cropSquare は中央の正方形を切り出し、アルファ乗算済みのRGBAに変換します
+func cropSquare(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	origin := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), src, origin, draw.Src)
+	return dst
+}
+
+// resize は正方形画像をsize×sizeに縮小・拡大します。
+// 出力の各画素に対応する元画像の範囲を平均する（縮小時はボックスフィルタ、拡大時は最近傍になる）
+func resize(src *image.RGBA, size int) *image.RGBA {
+	srcSide := src.Bounds().Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for y := 0; y < size; y++ {
+		sy0 := y * srcSide / size
+		sy1 := (y + 1) * srcSide / size
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+		for x := 0; x < size; x++ {
+			sx0 := x * srcSide / size
+			sx1 := (x + 1) * srcSide / size
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+
+			var r, g, b, a, n uint32
+			for sy := sy0; sy < sy1; sy++ {
+				offset := src.PixOffset(sx0, sy)
+				for sx := sx0; sx < sx1; sx++ {
+					r += uint32(src.Pix[offset])
+					g += uint32(src.Pix[offset+1])
+					b += uint32(src.Pix[offset+2])
+					a += uint32(src.Pix[offset+3])
+					offset += 4
+					n++
+				}
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
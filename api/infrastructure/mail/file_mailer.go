@@ -0,0 +1,40 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9@._-]`)
+
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer creates a mailer that writes each message to an .eml file in dir (for local development)
+func NewFileMailer(dir, from string) domain.Mailer {
+	return &fileMailer{dir: dir, from: from}
+}
+
+func (m *fileMailer) Send(ctx context.Context, message domain.EmailMessage) error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), unsafeFileChars.ReplaceAllString(message.To, "_"))
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, buildMessage(m.from, message), 0o644); err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+
+	log.Printf("✉ Mail captured: %s (%s)", path, message.Subject)
+	return nil
+}
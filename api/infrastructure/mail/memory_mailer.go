@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// MemoryMailer は送信したメールをメモリに保持するMailerです（テスト用）
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []domain.EmailMessage
+}
+
+// NewMemoryMailer creates a mailer that keeps sent messages in memory
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, message domain.EmailMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+// Messages returns a copy of the messages sent so far
+func (m *MemoryMailer) Messages() []domain.EmailMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	messages := make([]domain.EmailMessage, len(m.messages))
+	copy(messages, m.messages)
+	return messages
+}
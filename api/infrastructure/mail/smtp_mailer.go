@@ -0,0 +1,60 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// SMTPConfig はSMTPサーバーの接続設定
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string // 空の場合は認証しない
+	Password string
+	From     string
+}
+
+type smtpMailer struct {
+	config SMTPConfig
+}
+
+// NewSMTPMailer creates a mailer that delivers through an SMTP server (STARTTLS when offered)
+func NewSMTPMailer(config SMTPConfig) domain.Mailer {
+	return &smtpMailer{config: config}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, message domain.EmailMessage) error {
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	addr := net.JoinHostPort(m.config.Host, m.config.Port)
+	if err := smtp.SendMail(addr, auth, m.config.From, []string{message.To}, buildMessage(m.config.From, message)); err != nil {
+		return fmt.Errorf("failed to send mail via SMTP: %w", err)
+	}
+
+	return nil
+}
+
+// buildMessage はRFC 5322形式のメッセージ（UTF-8プレーンテキスト）を組み立てます
+func buildMessage(from string, message domain.EmailMessage) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", message.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(message.Body)
+	return buf.Bytes()
+}
@@ -0,0 +1,192 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *sql.DB) domain.APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// apiKeyColumns はSELECTする列（scanAPIKeyと順序を合わせる）
+const apiKeyColumns = `id, user_id, name, prefix, secret_hash, scopes, last_used_at, revoked_at, created_at`
+
+// Create inserts a new API key (scopes are stored comma separated)
+func (r *apiKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "api_keys",
+			Operation:  "INSERT",
+		}
+		defer segment.End()
+	}
+
+	key.CreatedAt = time.Now()
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO api_keys (user_id, name, prefix, secret_hash, scopes, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		key.UserID, key.Name, key.Prefix, key.SecretHash, strings.Join(key.Scopes, ","), key.CreatedAt,
+	)
+	if err != nil {
+		return translateError(err, "api key", "failed to insert api key")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	key.ID = id
+
+	return nil
+}
+
+// FindByPrefix returns the key including revoked ones (domain.ErrNotFound if not found)
+func (r *apiKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "api_keys",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE prefix = ?`
+	return scanAPIKey(r.db.QueryRowContext(ctx, query, prefix))
+}
+
+// FindByID returns the key including revoked ones (domain.ErrNotFound if not found)
+func (r *apiKeyRepository) FindByID(ctx context.Context, id int64) (*domain.APIKey, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "api_keys",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = ?`
+	return scanAPIKey(r.db.QueryRowContext(ctx, query, id))
+}
+
+// FindByUserID returns the user's keys, newest first
+func (r *apiKeyRepository) FindByUserID(ctx context.Context, userID int64) ([]domain.APIKey, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "api_keys",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE user_id = ? ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Revoke sets revoked_at (revoking twice keeps the first timestamp)
+func (r *apiKeyRepository) Revoke(ctx context.Context, key *domain.APIKey) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "api_keys",
+			Operation:  "UPDATE",
+		}
+		defer segment.End()
+	}
+
+	now := time.Now()
+	_, err := r.db.ExecContext(ctx, `UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`, now, key.ID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if key.RevokedAt == nil {
+		key.RevokedAt = &now
+	}
+
+	return nil
+}
+
+// TouchLastUsed sets last_used_at
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, key *domain.APIKey, at time.Time) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "api_keys",
+			Operation:  "UPDATE",
+		}
+		defer segment.End()
+	}
+
+	if _, err := r.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = ? WHERE id = ?`, at, key.ID); err != nil {
+		return fmt.Errorf("failed to update api key last used: %w", err)
+	}
+	key.LastUsedAt = &at
+
+	return nil
+}
+
+// rowScanner は*sql.Rowと*sql.Rowsの共通インターフェース
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	var key domain.APIKey
+	var scopes string
+	err := row.Scan(
+		&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.SecretHash, &scopes,
+		&key.LastUsedAt, &key.RevokedAt, &key.CreatedAt,
+	)
+	if err != nil {
+		return nil, translateError(err, "api key", "failed to scan api key")
+	}
+
+	key.Scopes = []string{}
+	if scopes != "" {
+		key.Scopes = strings.Split(scopes, ",")
+	}
+
+	return &key, nil
+}
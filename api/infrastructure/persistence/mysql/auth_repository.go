@@ -0,0 +1,106 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+type authRepository struct {
+	db *sql.DB
+}
+
+// NewAuthRepository creates a new auth repository
+func NewAuthRepository(db *sql.DB) domain.AuthRepository {
+	return &authRepository{db: db}
+}
+
+// CreateUser inserts a user with a password hash (ErrUserAlreadyExists on duplicate username/email)
+func (r *authRepository) CreateUser(ctx context.Context, user *domain.User, passwordHash string) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "users",
+			Operation:  "INSERT",
+		}
+		defer segment.End()
+	}
+
+	query := `INSERT INTO users (username, email, password_hash, status, email_verified, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	user.Status = domain.UserStatusActive
+	user.EmailVerified = false
+
+	result, err := r.db.ExecContext(ctx, query, user.Username, user.Email, passwordHash, user.Status, user.EmailVerified, user.CreatedAt, user.UpdatedAt)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return domain.ErrUserAlreadyExists
+		}
+		return fmt.Errorf("failed to insert user: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	user.ID = id
+
+	return nil
+}
+
+// FindCredentials retrieves credentials by username or email (domain.ErrNotFound if no user matches)
+func (r *authRepository) FindCredentials(ctx context.Context, login string) (*domain.UserCredentials, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "users",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	query := `SELECT id, username, email, password_hash, status FROM users WHERE username = ? OR email = ? LIMIT 1`
+
+	var creds domain.UserCredentials
+	err := r.db.QueryRowContext(ctx, query, login, login).Scan(
+		&creds.UserID, &creds.Username, &creds.Email, &creds.PasswordHash, &creds.Status,
+	)
+	if err != nil {
+		return nil, translateError(err, "user", "failed to query credentials")
+	}
+
+	return &creds, nil
+}
+
+// UpdateLastLogin sets users.last_login_at
+func (r *authRepository) UpdateLastLogin(ctx context.Context, userID int64, at time.Time) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "users",
+			Operation:  "UPDATE",
+		}
+		defer segment.End()
+	}
+
+	query := `UPDATE users SET last_login_at = ? WHERE id = ?`
+
+	if _, err := r.db.ExecContext(ctx, query, at, userID); err != nil {
+		return fmt.Errorf("failed to update last login: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,292 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+type commentRepository struct {
+	db *sql.DB
+}
+
+// NewCommentRepository creates a new comment repository
+func NewCommentRepository(db *sql.DB) domain.CommentRepository {
+	return &commentRepository{db: db}
+}
+
+// FindByID retrieves a comment by ID regardless of its status
+func (r *commentRepository) FindByID(ctx context.Context, id int64) (*domain.Comment, error) {
+	query := `
+		SELECT id, post_id, user_id, parent_id, content, status, like_count, is_edited, created_at, updated_at
+		FROM comments
+		WHERE id = ?
+	`
+
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "comments",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	var comment domain.Comment
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&comment.ID, &comment.PostID, &comment.UserID, &comment.ParentID,
+		&comment.Content, &comment.Status, &comment.LikeCount, &comment.IsEdited,
+		&comment.CreatedAt, &comment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, translateError(err, "comment", "failed to query comment")
+	}
+
+	return &comment, nil
+}
+
+// FindApprovedThreadByPostID retrieves a page of approved root comments with all approved descendants
+func (r *commentRepository) FindApprovedThreadByPostID(ctx context.Context, postID int64, limit, offset int) ([]domain.CommentWithAuthor, error) {
+	// ルートコメントをページングし、再帰CTEで返信ツリーを取得
+	query := `
+		WITH RECURSIVE roots AS (
+			SELECT id
+			FROM comments
+			WHERE post_id = ? AND parent_id IS NULL AND status = 'approved'
+			ORDER BY created_at ASC, id ASC
+			LIMIT ? OFFSET ?
+		), thread AS (
+			SELECT c.id
+			FROM comments c
+			INNER JOIN roots r ON c.id = r.id
+			UNION ALL
+			SELECT c.id
+			FROM comments c
+			INNER JOIN thread t ON c.parent_id = t.id
+			WHERE c.status = 'approved'
+		)
+		SELECT
+			c.id, c.post_id, c.user_id, c.parent_id, c.content, c.status,
+			c.like_count, c.is_edited, c.created_at, c.updated_at,
+			u.username as author_username,
+			up.display_name as author_display_name,
+			up.avatar_url as author_avatar_url
+		FROM thread t
+		INNER JOIN comments c ON c.id = t.id
+		INNER JOIN users u ON c.user_id = u.id
+		LEFT JOIN user_profiles up ON u.id = up.user_id
+		ORDER BY c.created_at ASC, c.id ASC
+	`
+
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "comments",
+			Operation:  "SELECT_RECURSIVE",
+		}
+		defer segment.End()
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, postID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comment thread: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCommentsWithAuthor(rows)
+}
+
+// CountApprovedRootsByPostID returns the number of approved root comments of a post
+func (r *commentRepository) CountApprovedRootsByPostID(ctx context.Context, postID int64) (int64, error) {
+	query := `SELECT COUNT(*) FROM comments WHERE post_id = ? AND parent_id IS NULL AND status = 'approved'`
+
+	var count int64
+	err := r.db.QueryRowContext(ctx, query, postID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	return count, nil
+}
+
+// FindByStatus retrieves comments in the given status for moderation (oldest first)
+func (r *commentRepository) FindByStatus(ctx context.Context, status string, limit, offset int) ([]domain.CommentWithAuthor, error) {
+	query := `
+		SELECT
+			c.id, c.post_id, c.user_id, c.parent_id, c.content, c.status,
+			c.like_count, c.is_edited, c.created_at, c.updated_at,
+			u.username as author_username,
+			up.display_name as author_display_name,
+			up.avatar_url as author_avatar_url
+		FROM comments c
+		INNER JOIN users u ON c.user_id = u.id
+		LEFT JOIN user_profiles up ON u.id = up.user_id
+		WHERE c.status = ?
+		ORDER BY c.created_at ASC, c.id ASC
+		LIMIT ? OFFSET ?
+	`
+
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "comments",
+			Operation:  "SELECT_WITH_JOIN",
+		}
+		defer segment.End()
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments by status: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCommentsWithAuthor(rows)
+}
+
+// Create inserts a new comment (approved comments are counted in posts.comment_count)
+func (r *commentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "comments",
+			Operation:  "INSERT",
+		}
+		defer segment.End()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	comment.CreatedAt = now
+	comment.UpdatedAt = now
+
+	query := `
+		INSERT INTO comments (post_id, user_id, parent_id, content, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := tx.ExecContext(ctx, query,
+		comment.PostID, comment.UserID, comment.ParentID, comment.Content, comment.Status,
+		comment.CreatedAt, comment.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert comment: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get comment ID: %w", err)
+	}
+	comment.ID = id
+
+	if comment.Status == domain.CommentStatusApproved {
+		if err := adjustPostCommentCount(ctx, tx, comment.PostID, 1); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStatus changes the comment status and keeps posts.comment_count in sync
+func (r *commentRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "comments",
+			Operation:  "UPDATE",
+		}
+		defer segment.End()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 同時モデレーションでカウンタがずれないよう行ロックを取得
+	var postID int64
+	var current string
+	err = tx.QueryRowContext(ctx, `SELECT post_id, status FROM comments WHERE id = ? FOR UPDATE`, id).Scan(&postID, &current)
+	if err != nil {
+		return translateError(err, "comment", "failed to query comment")
+	}
+	if current == status {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE comments SET status = ?, updated_at = NOW() WHERE id = ?`, status, id); err != nil {
+		return fmt.Errorf("failed to update comment status: %w", err)
+	}
+
+	delta := 0
+	if current == domain.CommentStatusApproved {
+		delta--
+	}
+	if status == domain.CommentStatusApproved {
+		delta++
+	}
+	if delta != 0 {
+		if err := adjustPostCommentCount(ctx, tx, postID, delta); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Helper function to add delta to posts.comment_count (never below zero)
+func adjustPostCommentCount(ctx context.Context, tx *sql.Tx, postID int64, delta int) error {
+	query := `UPDATE posts SET comment_count = GREATEST(comment_count + ?, 0) WHERE id = ?`
+	if _, err := tx.ExecContext(ctx, query, delta, postID); err != nil {
+		return fmt.Errorf("failed to update comment count: %w", err)
+	}
+	return nil
+}
+
+// Helper function to scan comments joined with author information
+func scanCommentsWithAuthor(rows *sql.Rows) ([]domain.CommentWithAuthor, error) {
+	var comments []domain.CommentWithAuthor
+	for rows.Next() {
+		var comment domain.CommentWithAuthor
+		err := rows.Scan(
+			&comment.ID, &comment.PostID, &comment.UserID, &comment.ParentID,
+			&comment.Content, &comment.Status, &comment.LikeCount, &comment.IsEdited,
+			&comment.CreatedAt, &comment.UpdatedAt,
+			&comment.AuthorUsername, &comment.AuthorDisplayName, &comment.AuthorAvatarURL,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
@@ -0,0 +1,138 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+type emailVerificationRepository struct {
+	db *sql.DB
+}
+
+// NewEmailVerificationRepository creates a new email verification repository
+func NewEmailVerificationRepository(db *sql.DB) domain.EmailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+// Create stores a new token and invalidates the user's earlier unused tokens
+func (r *emailVerificationRepository) Create(ctx context.Context, token *domain.EmailVerificationToken) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "email_verification_tokens",
+			Operation:  "INSERT",
+		}
+		defer segment.End()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 再送時は古いトークンを使えないようにする
+	_, err = tx.ExecContext(ctx, `UPDATE email_verification_tokens SET used_at = NOW() WHERE user_id = ? AND used_at IS NULL`, token.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate verification tokens: %w", err)
+	}
+
+	token.CreatedAt = time.Now()
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO email_verification_tokens (user_id, token_hash, expires_at, created_at) VALUES (?, ?, ?, ?)`,
+		token.UserID, token.TokenHash, token.ExpiresAt, token.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert verification token: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	token.ID = id
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Consume marks the token used and the user's email verified, returning the user ID
+func (r *emailVerificationRepository) Consume(ctx context.Context, tokenHash string, now time.Time) (int64, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "email_verification_tokens",
+			Operation:  "UPDATE",
+		}
+		defer segment.End()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 同じトークンの同時使用を防ぐため行ロックを取る
+	var token domain.EmailVerificationToken
+	err = tx.QueryRowContext(ctx,
+		`SELECT id, user_id, expires_at, used_at FROM email_verification_tokens WHERE token_hash = ? FOR UPDATE`,
+		tokenHash,
+	).Scan(&token.ID, &token.UserID, &token.ExpiresAt, &token.UsedAt)
+	if err == sql.ErrNoRows {
+		return 0, domain.ErrInvalidVerificationToken
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to query verification token: %w", err)
+	}
+	if token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+		return 0, domain.ErrInvalidVerificationToken
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE email_verification_tokens SET used_at = ? WHERE id = ?`, now, token.ID); err != nil {
+		return 0, fmt.Errorf("failed to mark verification token used: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET email_verified = TRUE WHERE id = ?`, token.UserID); err != nil {
+		return 0, fmt.Errorf("failed to mark email verified: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return token.UserID, nil
+}
+
+// IsEmailVerified reports whether users.email_verified is set
+func (r *emailVerificationRepository) IsEmailVerified(ctx context.Context, userID int64) (bool, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "users",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	var verified bool
+	err := r.db.QueryRowContext(ctx, `SELECT email_verified FROM users WHERE id = ?`, userID).Scan(&verified)
+	if err != nil {
+		return false, translateError(err, "user", "failed to query email verification")
+	}
+
+	return verified, nil
+}
@@ -0,0 +1,52 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+const (
+	// mysqlErrDuplicateEntry はUNIQUE制約違反のMySQLエラー番号
+	mysqlErrDuplicateEntry = 1062
+	// mysqlErrParse は構文エラーのMySQLエラー番号（BOOLEAN MODEの全文検索で検索式が不正な場合も含む）
+	mysqlErrParse = 1064
+	// mysqlErrNoReferencedRow は外部キー制約違反（参照先の行が存在しない）のMySQLエラー番号（ER_NO_REFERENCED_ROW_2）
+	mysqlErrNoReferencedRow = 1452
+)
+
+// translateError はドライバーのエラーをドメインエラーに変換します。
+// sql.ErrNoRowsは「<resource> not found」（domain.ErrNotFound）、重複キーは「<resource> already exists」（domain.ErrConflict）、
+// 外部キー違反は「<resource> refers to a missing record」（domain.ErrValidation）になり、それ以外のエラーはopを付けてラップします
+func translateError(err error, resource, op string) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.NotFoundError(resource)
+	case isDuplicateEntry(err):
+		return domain.ConflictError(resource)
+	case isNoReferencedRow(err):
+		return domain.InvalidReferenceError(resource)
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// isDuplicateEntry reports whether err is a MySQL duplicate key error
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
+
+// isNoReferencedRow reports whether err is a MySQL foreign key error for a missing referenced row
+func isNoReferencedRow(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrNoReferencedRow
+}
+
+// isParseError reports whether err is a MySQL syntax error
+func isParseError(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrParse
+}
@@ -0,0 +1,38 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+		code string
+	}{
+		{"no rows", sql.ErrNoRows, domain.ErrNotFound, "not_found"},
+		{"duplicate entry", &mysqldriver.MySQLError{Number: mysqlErrDuplicateEntry}, domain.ErrConflict, "conflict"},
+		{"foreign key", &mysqldriver.MySQLError{Number: mysqlErrNoReferencedRow}, domain.ErrValidation, "invalid_reference"},
+	}
+
+	for _, tt := range tests {
+		err := translateError(tt.err, "post", "failed to insert post")
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.want, err)
+		}
+		if code := domain.CodeOf(err); code != tt.code {
+			t.Errorf("%s: Expected code %q, got %q", tt.name, tt.code, code)
+		}
+	}
+
+	other := errors.New("connection refused")
+	if err := translateError(other, "post", "failed to insert post"); !errors.Is(err, other) || domain.KindOf(err) != domain.KindInternal {
+		t.Errorf("Expected other errors to be wrapped as internal, got %v", err)
+	}
+}
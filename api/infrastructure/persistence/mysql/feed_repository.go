@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// feedRepository はフォロー中の著者の投稿をリクエストごとにJOINで集める（fan-out-on-read）
+type feedRepository struct {
+	posts *postRepository // タグ読み込みなどのヘルパーを共有
+}
+
+// NewFeedRepository creates a new fan-out-on-read feed repository
+func NewFeedRepository(db *sql.DB) domain.FeedRepository {
+	return &feedRepository{posts: &postRepository{db: db}}
+}
+
+// FindFeed retrieves published posts by authors userID follows, newest first
+func (r *feedRepository) FindFeed(ctx context.Context, userID int64, limit, offset int) ([]domain.PostWithDetails, error) {
+	query := `
+		SELECT 
+			p.id, p.user_id, p.category_id, p.title, p.slug, p.content, p.excerpt,
+			p.status, p.published_at, p.view_count, p.like_count, p.comment_count,
+			p.is_featured, p.created_at, p.updated_at,
+			u.username as author_username,
+			up.display_name as author_display_name,
+			up.avatar_url as author_avatar_url,
+			c.name as category_name,
+			c.slug as category_slug
+		FROM user_follows f
+		INNER JOIN posts p ON p.user_id = f.following_id
+		INNER JOIN users u ON p.user_id = u.id
+		LEFT JOIN user_profiles up ON u.id = up.user_id
+		LEFT JOIN categories c ON p.category_id = c.id
+		WHERE f.follower_id = ? AND p.status = 'published' AND p.published_at IS NOT NULL AND u.status NOT IN ('suspended', 'deleted')
+		ORDER BY p.published_at DESC, p.id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "posts",
+			Operation:  "SELECT_FEED",
+		}
+		defer segment.End()
+	}
+
+	rows, err := r.posts.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query feed: %w", err)
+	}
+	defer rows.Close()
+
+	return r.posts.scanPostsWithTags(ctx, rows)
+}
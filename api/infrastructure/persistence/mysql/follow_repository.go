@@ -0,0 +1,198 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+type followRepository struct {
+	db *sql.DB
+}
+
+// NewFollowRepository creates a new follow repository
+func NewFollowRepository(db *sql.DB) domain.FollowRepository {
+	return &followRepository{db: db}
+}
+
+// Create inserts a follow relation (ErrAlreadyFollowing if it exists)
+func (r *followRepository) Create(ctx context.Context, followerID, followingID int64) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_follows",
+			Operation:  "INSERT",
+		}
+		defer segment.End()
+	}
+
+	// 主キー(follower_id, following_id)の重複はIGNOREで検出する
+	query := `INSERT IGNORE INTO user_follows (follower_id, following_id) VALUES (?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query, followerID, followingID)
+	if err != nil {
+		return fmt.Errorf("failed to insert follow: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return domain.ErrAlreadyFollowing
+	}
+
+	return nil
+}
+
+// Delete removes a follow relation (ErrNotFollowing if it does not exist)
+func (r *followRepository) Delete(ctx context.Context, followerID, followingID int64) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_follows",
+			Operation:  "DELETE",
+		}
+		defer segment.End()
+	}
+
+	query := `DELETE FROM user_follows WHERE follower_id = ? AND following_id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, followerID, followingID)
+	if err != nil {
+		return fmt.Errorf("failed to delete follow: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return domain.ErrNotFollowing
+	}
+
+	return nil
+}
+
+// Exists reports whether followerID follows followingID
+func (r *followRepository) Exists(ctx context.Context, followerID, followingID int64) (bool, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_follows",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	query := `SELECT EXISTS(SELECT 1 FROM user_follows WHERE follower_id = ? AND following_id = ?)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, followerID, followingID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to query follow: %w", err)
+	}
+
+	return exists, nil
+}
+
+// FindFollowers retrieves users following userID, newest first, after the cursor
+func (r *followRepository) FindFollowers(ctx context.Context, userID int64, after *domain.FollowCursor, limit int) ([]domain.FollowUser, error) {
+	return r.findFollowUsers(ctx, "follower_id", "following_id", userID, after, limit)
+}
+
+// FindFollowing retrieves users followed by userID, newest first, after the cursor
+func (r *followRepository) FindFollowing(ctx context.Context, userID int64, after *domain.FollowCursor, limit int) ([]domain.FollowUser, error) {
+	return r.findFollowUsers(ctx, "following_id", "follower_id", userID, after, limit)
+}
+
+// FindFollowerIDs retrieves the IDs of all users following userID
+func (r *followRepository) FindFollowerIDs(ctx context.Context, userID int64) ([]int64, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_follows",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	query := `SELECT follower_id FROM user_follows WHERE following_id = ?`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query followers: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan follower: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// findFollowUsers はuser_followsをusers/user_profilesとJOINしてカーソルページングで取得します。
+// listColumnは一覧に出すユーザー側の列、filterColumnは絞り込む側の列（どちらも固定値のみ渡す）
+func (r *followRepository) findFollowUsers(ctx context.Context, listColumn, filterColumn string, userID int64, after *domain.FollowCursor, limit int) ([]domain.FollowUser, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_follows",
+			Operation:  "SELECT_WITH_JOIN",
+		}
+		defer segment.End()
+	}
+
+	args := []interface{}{userID}
+	cursorClause := ""
+	if after != nil {
+		cursorClause = fmt.Sprintf(`AND (f.created_at < ? OR (f.created_at = ? AND f.%s < ?))`, listColumn)
+		args = append(args, after.FollowedAt, after.FollowedAt, after.UserID)
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT u.id, u.username, up.display_name, up.avatar_url, f.created_at
+		FROM user_follows f
+		INNER JOIN users u ON f.%[1]s = u.id
+		LEFT JOIN user_profiles up ON u.id = up.user_id
+		WHERE f.%[2]s = ? %[3]s
+		ORDER BY f.created_at DESC, f.%[1]s DESC
+		LIMIT ?
+	`, listColumn, filterColumn, cursorClause)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query follows: %w", err)
+	}
+	defer rows.Close()
+
+	var users []domain.FollowUser
+	for rows.Next() {
+		var user domain.FollowUser
+		if err := rows.Scan(&user.ID, &user.Username, &user.DisplayName, &user.AvatarURL, &user.FollowedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan follow: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
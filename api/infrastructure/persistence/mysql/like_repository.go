@@ -0,0 +1,190 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// likeCountTables はlikeable_typeごとのlike_countを持つテーブル
+var likeCountTables = map[string]string{
+	domain.LikeableTypePost:    "posts",
+	domain.LikeableTypeComment: "comments",
+}
+
+type likeRepository struct {
+	db *sql.DB
+}
+
+// NewLikeRepository creates a new like repository
+func NewLikeRepository(db *sql.DB) domain.LikeRepository {
+	return &likeRepository{db: db}
+}
+
+// Like adds a like and increments like_count in the same transaction (no-op if already liked)
+func (r *likeRepository) Like(ctx context.Context, userID int64, likeableType string, likeableID int64) (*domain.LikeStatus, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "likes",
+			Operation:  "INSERT",
+		}
+		defer segment.End()
+	}
+
+	query := `INSERT IGNORE INTO likes (user_id, likeable_type, likeable_id) VALUES (?, ?, ?)`
+	return r.toggle(ctx, query, 1, userID, likeableType, likeableID)
+}
+
+// Unlike removes a like and decrements like_count in the same transaction (no-op if not liked)
+func (r *likeRepository) Unlike(ctx context.Context, userID int64, likeableType string, likeableID int64) (*domain.LikeStatus, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "likes",
+			Operation:  "DELETE",
+		}
+		defer segment.End()
+	}
+
+	query := `DELETE FROM likes WHERE user_id = ? AND likeable_type = ? AND likeable_id = ?`
+	return r.toggle(ctx, query, -1, userID, likeableType, likeableID)
+}
+
+// toggle は対象行をロックしてからlikesを変更し、行が変わった場合だけlike_countをdelta分更新します。
+// 同じユーザーの連続リクエストでもカウンタが二重に変わらない（冪等）
+func (r *likeRepository) toggle(ctx context.Context, query string, delta int, userID int64, likeableType string, likeableID int64) (*domain.LikeStatus, error) {
+	table, ok := likeCountTables[likeableType]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown likeable type %q", domain.ErrInvalidLikeInput, likeableType)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var likeCount int32
+	lockQuery := fmt.Sprintf(`SELECT like_count FROM %s WHERE id = ? FOR UPDATE`, table)
+	if err := tx.QueryRowContext(ctx, lockQuery, likeableID).Scan(&likeCount); err != nil {
+		return nil, translateError(err, likeableType, "failed to lock "+likeableType)
+	}
+
+	result, err := tx.ExecContext(ctx, query, userID, likeableType, likeableID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update like: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if affected > 0 {
+		updateQuery := fmt.Sprintf(`UPDATE %s SET like_count = GREATEST(like_count + ?, 0) WHERE id = ?`, table)
+		if _, err := tx.ExecContext(ctx, updateQuery, delta, likeableID); err != nil {
+			return nil, fmt.Errorf("failed to update like count: %w", err)
+		}
+		likeCount += int32(delta)
+		if likeCount < 0 {
+			likeCount = 0
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &domain.LikeStatus{Liked: delta > 0, LikeCount: likeCount, Changed: affected > 0}, nil
+}
+
+// FindLikers retrieves users who liked the target, newest first
+func (r *likeRepository) FindLikers(ctx context.Context, likeableType string, likeableID int64, limit, offset int) ([]domain.Liker, error) {
+	query := `
+		SELECT u.id, u.username, up.display_name, up.avatar_url, l.created_at
+		FROM likes l
+		INNER JOIN users u ON l.user_id = u.id
+		LEFT JOIN user_profiles up ON u.id = up.user_id
+		WHERE l.likeable_type = ? AND l.likeable_id = ?
+		ORDER BY l.created_at DESC, l.id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "likes",
+			Operation:  "SELECT_WITH_JOIN",
+		}
+		defer segment.End()
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, likeableType, likeableID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query likes: %w", err)
+	}
+	defer rows.Close()
+
+	var likers []domain.Liker
+	for rows.Next() {
+		var liker domain.Liker
+		if err := rows.Scan(&liker.ID, &liker.Username, &liker.DisplayName, &liker.AvatarURL, &liker.LikedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan like: %w", err)
+		}
+		likers = append(likers, liker)
+	}
+
+	return likers, rows.Err()
+}
+
+// FindLikedIDs reports which of likeableIDs userID has liked
+func (r *likeRepository) FindLikedIDs(ctx context.Context, userID int64, likeableType string, likeableIDs []int64) (map[int64]bool, error) {
+	liked := make(map[int64]bool, len(likeableIDs))
+	if len(likeableIDs) == 0 {
+		return liked, nil
+	}
+
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "likes",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	placeholders, idArgs := int64InClause(likeableIDs)
+	query := fmt.Sprintf(`
+		SELECT likeable_id FROM likes
+		WHERE user_id = ? AND likeable_type = ? AND likeable_id IN (%s)
+	`, placeholders)
+
+	args := append([]interface{}{userID, likeableType}, idArgs...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query likes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan like: %w", err)
+		}
+		liked[id] = true
+	}
+
+	return liked, rows.Err()
+}
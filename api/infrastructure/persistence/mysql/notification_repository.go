@@ -0,0 +1,270 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+type notificationRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *sql.DB) domain.NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// Create inserts a notification for userID (sets ID and CreatedAt)
+func (r *notificationRepository) Create(ctx context.Context, userID int64, notification *domain.UserNotification) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "notifications",
+			Operation:  "INSERT",
+		}
+		defer segment.End()
+	}
+
+	notification.CreatedAt = time.Now()
+	notification.IsRead = false
+	notification.ReadAt = nil
+
+	query := `
+		INSERT INTO notifications (user_id, type, title, message, link_url, is_read, created_at)
+		VALUES (?, ?, ?, ?, ?, FALSE, ?)
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		userID, notification.Type, notification.Title, notification.Message, notification.LinkURL, notification.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert notification: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get notification ID: %w", err)
+	}
+	notification.ID = id
+
+	return nil
+}
+
+// FindByUserID retrieves userID's notifications matching the filter, newest first
+func (r *notificationRepository) FindByUserID(ctx context.Context, userID int64, filter string, limit, offset int) ([]domain.UserNotification, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "notifications",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, type, title, message, link_url, is_read, created_at, read_at
+		FROM notifications
+		WHERE user_id = ? %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, notificationFilterClause(filter))
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []domain.UserNotification
+	for rows.Next() {
+		var notification domain.UserNotification
+		err := rows.Scan(
+			&notification.ID, &notification.Type, &notification.Title, &notification.Message,
+			&notification.LinkURL, &notification.IsRead, &notification.CreatedAt, &notification.ReadAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, rows.Err()
+}
+
+// CountByUserID counts userID's notifications matching the filter
+func (r *notificationRepository) CountByUserID(ctx context.Context, userID int64, filter string) (int64, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "notifications",
+			Operation:  "COUNT",
+		}
+		defer segment.End()
+	}
+
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM notifications WHERE user_id = ? %s`, notificationFilterClause(filter))
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+// FindByID retrieves a notification owned by userID (domain.ErrNotFound if not found)
+func (r *notificationRepository) FindByID(ctx context.Context, userID, id int64) (*domain.UserNotification, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "notifications",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	query := `
+		SELECT id, type, title, message, link_url, is_read, created_at, read_at
+		FROM notifications
+		WHERE id = ? AND user_id = ?
+	`
+
+	var notification domain.UserNotification
+	err := r.db.QueryRowContext(ctx, query, id, userID).Scan(
+		&notification.ID, &notification.Type, &notification.Title, &notification.Message,
+		&notification.LinkURL, &notification.IsRead, &notification.CreatedAt, &notification.ReadAt,
+	)
+	if err != nil {
+		return nil, translateError(err, "notification", "failed to query notification")
+	}
+
+	return &notification, nil
+}
+
+// MarkRead marks a notification read and sets read_at (domain.ErrNotFound if not found)
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, id int64) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "notifications",
+			Operation:  "UPDATE",
+		}
+		defer segment.End()
+	}
+
+	// 既読済みの場合はread_atを変更しない
+	query := `
+		UPDATE notifications
+		SET is_read = TRUE, read_at = COALESCE(read_at, NOW())
+		WHERE id = ? AND user_id = ?
+	`
+	if _, err := r.db.ExecContext(ctx, query, id, userID); err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+
+	// 既読済みの行はRowsAffectedが0になるため、存在確認は別に行う
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM notifications WHERE id = ? AND user_id = ?)`, id, userID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to query notification: %w", err)
+	}
+	if !exists {
+		return domain.NotFoundError("notification")
+	}
+
+	return nil
+}
+
+// MarkAllRead marks all of userID's unread notifications read and returns how many changed
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "notifications",
+			Operation:  "UPDATE",
+		}
+		defer segment.End()
+	}
+
+	query := `UPDATE notifications SET is_read = TRUE, read_at = NOW() WHERE user_id = ? AND is_read = FALSE`
+	result, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected, nil
+}
+
+// FindAfterID retrieves userID's notifications with ID greater than afterID, oldest first
+func (r *notificationRepository) FindAfterID(ctx context.Context, userID, afterID int64, limit int) ([]domain.UserNotification, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "notifications",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	query := `
+		SELECT id, type, title, message, link_url, is_read, created_at, read_at
+		FROM notifications
+		WHERE user_id = ? AND id > ?
+		ORDER BY id ASC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []domain.UserNotification
+	for rows.Next() {
+		var notification domain.UserNotification
+		err := rows.Scan(
+			&notification.ID, &notification.Type, &notification.Title, &notification.Message,
+			&notification.LinkURL, &notification.IsRead, &notification.CreatedAt, &notification.ReadAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, rows.Err()
+}
+
+// notificationFilterClause は既読フィルタのWHERE句（固定文字列のみ）を返します
+func notificationFilterClause(filter string) string {
+	switch filter {
+	case domain.NotificationFilterUnread:
+		return "AND is_read = FALSE"
+	case domain.NotificationFilterRead:
+		return "AND is_read = TRUE"
+	}
+	return ""
+}
@@ -0,0 +1,117 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+type passwordResetRepository struct {
+	db *sql.DB
+}
+
+// NewPasswordResetRepository creates a new password reset repository
+func NewPasswordResetRepository(db *sql.DB) domain.PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create stores a new token and invalidates the user's earlier unused tokens
+func (r *passwordResetRepository) Create(ctx context.Context, token *domain.PasswordResetToken) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "password_reset_tokens",
+			Operation:  "INSERT",
+		}
+		defer segment.End()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 有効なリンクは常に最新の1通だけにする
+	_, err = tx.ExecContext(ctx, `UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = ? AND used_at IS NULL`, token.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate reset tokens: %w", err)
+	}
+
+	token.CreatedAt = time.Now()
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO password_reset_tokens (user_id, token_hash, expires_at, created_at) VALUES (?, ?, ?, ?)`,
+		token.UserID, token.TokenHash, token.ExpiresAt, token.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert reset token: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	token.ID = id
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Reset consumes the token, replaces the user's password hash and invalidates the user's other unused tokens
+func (r *passwordResetRepository) Reset(ctx context.Context, tokenHash, passwordHash string, now time.Time) (int64, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "password_reset_tokens",
+			Operation:  "UPDATE",
+		}
+		defer segment.End()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 同じトークンの同時使用を防ぐため行ロックを取る
+	var token domain.PasswordResetToken
+	err = tx.QueryRowContext(ctx,
+		`SELECT id, user_id, expires_at, used_at FROM password_reset_tokens WHERE token_hash = ? FOR UPDATE`,
+		tokenHash,
+	).Scan(&token.ID, &token.UserID, &token.ExpiresAt, &token.UsedAt)
+	if err == sql.ErrNoRows {
+		return 0, domain.ErrInvalidResetToken
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to query reset token: %w", err)
+	}
+	if token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+		return 0, domain.ErrInvalidResetToken
+	}
+
+	// 使用したトークンを含め、このユーザーの未使用トークンを全て無効化する
+	if _, err := tx.ExecContext(ctx, `UPDATE password_reset_tokens SET used_at = ? WHERE user_id = ? AND used_at IS NULL`, now, token.UserID); err != nil {
+		return 0, fmt.Errorf("failed to invalidate reset tokens: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET password_hash = ? WHERE id = ?`, passwordHash, token.UserID); err != nil {
+		return 0, fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return token.UserID, nil
+}
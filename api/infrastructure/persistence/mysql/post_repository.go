@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/rssh-jp/test-api/api/domain"
@@ -35,7 +37,7 @@ func (r *postRepository) FindAllWithDetails(ctx context.Context, limit, offset i
 		INNER JOIN users u ON p.user_id = u.id
 		LEFT JOIN user_profiles up ON u.id = up.user_id
 		LEFT JOIN categories c ON p.category_id = c.id
-		WHERE p.status = 'published' AND p.published_at IS NOT NULL
+		WHERE p.status = 'published' AND p.published_at IS NOT NULL AND u.status NOT IN ('suspended', 'deleted')
 		ORDER BY p.published_at DESC
 		LIMIT ? OFFSET ?
 	`
@@ -139,7 +141,7 @@ func (r *postRepository) FindByIDWithDetails(ctx context.Context, id int64) (*do
 		&post.CategoryName, &post.CategorySlug,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query post: %w", err)
+		return nil, translateError(err, "post", "failed to query post")
 	}
 
 	// Load tags
@@ -200,7 +202,7 @@ func (r *postRepository) FindBySlugWithDetails(ctx context.Context, slug string)
 		&post.CategoryName, &post.CategorySlug,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query post: %w", err)
+		return nil, translateError(err, "post", "failed to query post")
 	}
 
 	// Load tags
@@ -236,7 +238,7 @@ func (r *postRepository) FindByCategoryWithDetails(ctx context.Context, category
 		INNER JOIN users u ON p.user_id = u.id
 		LEFT JOIN user_profiles up ON u.id = up.user_id
 		INNER JOIN categories c ON p.category_id = c.id
-		WHERE c.slug = ? AND p.status = 'published' AND p.published_at IS NOT NULL
+		WHERE c.slug = ? AND p.status = 'published' AND p.published_at IS NOT NULL AND u.status NOT IN ('suspended', 'deleted')
 		ORDER BY p.published_at DESC
 		LIMIT ? OFFSET ?
 	`
@@ -280,7 +282,7 @@ func (r *postRepository) FindByTagWithDetails(ctx context.Context, tagSlug strin
 		LEFT JOIN categories c ON p.category_id = c.id
 		INNER JOIN post_tags pt ON p.id = pt.post_id
 		INNER JOIN tags t ON pt.tag_id = t.id
-		WHERE t.slug = ? AND p.status = 'published' AND p.published_at IS NOT NULL
+		WHERE t.slug = ? AND p.status = 'published' AND p.published_at IS NOT NULL AND u.status NOT IN ('suspended', 'deleted')
 		ORDER BY p.published_at DESC
 		LIMIT ? OFFSET ?
 	`
@@ -322,7 +324,7 @@ func (r *postRepository) FindFeaturedWithDetails(ctx context.Context, limit int)
 		INNER JOIN users u ON p.user_id = u.id
 		LEFT JOIN user_profiles up ON u.id = up.user_id
 		LEFT JOIN categories c ON p.category_id = c.id
-		WHERE p.is_featured = TRUE AND p.status = 'published' AND p.published_at IS NOT NULL
+		WHERE p.is_featured = TRUE AND p.status = 'published' AND p.published_at IS NOT NULL AND u.status NOT IN ('suspended', 'deleted')
 		ORDER BY p.published_at DESC
 		LIMIT ?
 	`
@@ -348,9 +350,106 @@ func (r *postRepository) FindFeaturedWithDetails(ctx context.Context, limit int)
 	return r.scanPostsWithTags(ctx, rows)
 }
 
-// GetTotalCount returns total count of published posts
+// SearchWithDetails runs a full-text search (idx_fulltext_search) ordered by relevance
+func (r *postRepository) SearchWithDetails(ctx context.Context, query, mode string, limit, offset int) ([]domain.PostSearchResult, error) {
+	against := "IN NATURAL LANGUAGE MODE"
+	if mode == domain.SearchModeBoolean {
+		against = "IN BOOLEAN MODE"
+	}
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT 
+			p.id, p.user_id, p.category_id, p.title, p.slug, p.content, p.excerpt,
+			p.status, p.published_at, p.view_count, p.like_count, p.comment_count,
+			p.is_featured, p.created_at, p.updated_at,
+			u.username as author_username,
+			up.display_name as author_display_name,
+			up.avatar_url as author_avatar_url,
+			c.name as category_name,
+			c.slug as category_slug,
+			MATCH(p.title, p.content) AGAINST (? %[1]s) as relevance
+		FROM posts p
+		INNER JOIN users u ON p.user_id = u.id
+		LEFT JOIN user_profiles up ON u.id = up.user_id
+		LEFT JOIN categories c ON p.category_id = c.id
+		WHERE MATCH(p.title, p.content) AGAINST (? %[1]s)
+			AND p.status = 'published' AND p.published_at IS NOT NULL
+			AND u.status NOT IN ('suspended', 'deleted')
+		ORDER BY relevance DESC, p.published_at DESC
+		LIMIT ? OFFSET ?
+	`, against)
+
+	// NewRelic automatically traces this query via context from nrecho middleware
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "posts",
+			Operation:  "SELECT_FULLTEXT",
+		}
+		defer segment.End()
+	}
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, query, query, limit, offset)
+	if err != nil {
+		// Operators that don't form a valid boolean expression (e.g. a lone "+" or an unclosed parenthesis) are rejected by MySQL
+		if mode == domain.SearchModeBoolean && isParseError(err) {
+			return nil, fmt.Errorf("%w: malformed boolean query", domain.ErrInvalidSearchQuery)
+		}
+		return nil, fmt.Errorf("failed to search posts: %w", err)
+	}
+	defer rows.Close()
+
+	var results []domain.PostSearchResult
+	postIDs := []int64{}
+
+	for rows.Next() {
+		var result domain.PostSearchResult
+		err := rows.Scan(
+			&result.ID, &result.UserID, &result.CategoryID, &result.Title, &result.Slug,
+			&result.Content, &result.Excerpt, &result.Status, &result.PublishedAt,
+			&result.ViewCount, &result.LikeCount, &result.CommentCount, &result.IsFeatured,
+			&result.CreatedAt, &result.UpdatedAt,
+			&result.AuthorUsername, &result.AuthorDisplayName, &result.AuthorAvatarURL,
+			&result.CategoryName, &result.CategorySlug,
+			&result.Relevance,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, result)
+		postIDs = append(postIDs, result.ID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	// Load tags for all posts
+	if len(postIDs) > 0 {
+		tagsMap, err := r.loadTagsForPosts(ctx, postIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tags: %w", err)
+		}
+		for i := range results {
+			if tags, ok := tagsMap[results[i].ID]; ok {
+				results[i].Tags = tags
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// GetTotalCount returns total count of published posts by visible authors
 func (r *postRepository) GetTotalCount(ctx context.Context) (int64, error) {
-	query := `SELECT COUNT(*) FROM posts WHERE status = 'published' AND published_at IS NOT NULL`
+	query := `
+		SELECT COUNT(*)
+		FROM posts p
+		INNER JOIN users u ON p.user_id = u.id
+		WHERE p.status = 'published' AND p.published_at IS NOT NULL AND u.status NOT IN ('suspended', 'deleted')
+	`
 
 	var count int64
 	err := r.db.QueryRowContext(ctx, query).Scan(&count)
@@ -361,9 +460,22 @@ func (r *postRepository) GetTotalCount(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
-// IncrementViewCount increments the view count for a post
-func (r *postRepository) IncrementViewCount(ctx context.Context, postID int64) error {
-	query := `UPDATE posts SET view_count = view_count + 1 WHERE id = ?`
+// viewCountBatchSize はAddViewCountsで1つのUPDATEにまとめる投稿数
+const viewCountBatchSize = 500
+
+// AddViewCounts adds the buffered view deltas to the posts' view counts,
+// viewCountBatchSize posts per UPDATE within a single transaction
+func (r *postRepository) AddViewCounts(ctx context.Context, deltas map[int64]int64) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	// 行ロックの順序を揃えるためIDの昇順で更新する
+	ids := make([]int64, 0, len(deltas))
+	for id := range deltas {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
 
 	// NewRelic automatically traces this query via context from nrecho middleware
 	txn := newrelic.FromContext(ctx)
@@ -377,9 +489,37 @@ func (r *postRepository) IncrementViewCount(ctx context.Context, postID int64) e
 		defer segment.End()
 	}
 
-	_, err := r.db.ExecContext(ctx, query, postID)
+	// 失敗時は全件を再送するため、一部のチャンクだけが反映されないよう1つのトランザクションで更新する
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to increment view count: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1文のプレースホルダー数を抑えるため viewCountBatchSize 件ずつ更新する
+	for start := 0; start < len(ids); start += viewCountBatchSize {
+		chunk := ids[start:min(start+viewCountBatchSize, len(ids))]
+
+		var cases strings.Builder
+		args := make([]interface{}, 0, len(chunk)*3)
+		for _, id := range chunk {
+			cases.WriteString(" WHEN ? THEN ?")
+			args = append(args, id, deltas[id])
+		}
+		inClause, idArgs := int64InClause(chunk)
+		args = append(args, idArgs...)
+
+		query := fmt.Sprintf(
+			`UPDATE posts SET view_count = view_count + CASE id%s END WHERE id IN (%s)`,
+			cases.String(), inClause,
+		)
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to add view counts: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
@@ -540,3 +680,304 @@ func (r *postRepository) scanPostsWithTags(ctx context.Context, rows *sql.Rows)
 
 	return posts, nil
 }
+
+// FindByID retrieves a post row by ID regardless of its status
+func (r *postRepository) FindByID(ctx context.Context, id int64) (*domain.Post, error) {
+	query := `
+		SELECT 
+			id, user_id, category_id, title, slug, content, excerpt,
+			status, published_at, view_count, like_count, comment_count,
+			is_featured, created_at, updated_at
+		FROM posts
+		WHERE id = ?
+	`
+
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "posts",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	var post domain.Post
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&post.ID, &post.UserID, &post.CategoryID, &post.Title, &post.Slug,
+		&post.Content, &post.Excerpt, &post.Status, &post.PublishedAt,
+		&post.ViewCount, &post.LikeCount, &post.CommentCount, &post.IsFeatured,
+		&post.CreatedAt, &post.UpdatedAt,
+	)
+	if err != nil {
+		return nil, translateError(err, "post", "failed to query post")
+	}
+
+	return &post, nil
+}
+
+// Create inserts a new post and links the given tags in a single transaction
+func (r *postRepository) Create(ctx context.Context, post *domain.Post, tagIDs []int64) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "posts",
+			Operation:  "INSERT",
+		}
+		defer segment.End()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	post.CreatedAt = now
+	post.UpdatedAt = now
+	if post.Status == domain.PostStatusPublished && post.PublishedAt == nil {
+		post.PublishedAt = &now
+	}
+
+	query := `
+		INSERT INTO posts (user_id, category_id, title, slug, content, excerpt, status, published_at, is_featured, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := tx.ExecContext(ctx, query,
+		post.UserID, post.CategoryID, post.Title, post.Slug, post.Content, post.Excerpt,
+		post.Status, post.PublishedAt, post.IsFeatured, post.CreatedAt, post.UpdatedAt,
+	)
+	if err != nil {
+		return translateError(err, "post", "failed to insert post")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get post ID: %w", err)
+	}
+	post.ID = id
+
+	if err := r.replacePostTags(ctx, tx, post.ID, tagIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Update updates post fields and replaces its tag links in a single transaction.
+// nil tagIDs keeps the current tag links.
+func (r *postRepository) Update(ctx context.Context, post *domain.Post, tagIDs []int64) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "posts",
+			Operation:  "UPDATE",
+		}
+		defer segment.End()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	post.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE posts
+		SET category_id = ?, title = ?, slug = ?, content = ?, excerpt = ?, is_featured = ?, updated_at = ?
+		WHERE id = ? AND status <> 'deleted'
+	`
+	result, err := tx.ExecContext(ctx, query,
+		post.CategoryID, post.Title, post.Slug, post.Content, post.Excerpt, post.IsFeatured, post.UpdatedAt,
+		post.ID,
+	)
+	if err != nil {
+		return translateError(err, "post", "failed to update post")
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		// MySQL reports 0 rows for an update that changes nothing, so check that the post still exists
+		var exists bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM posts WHERE id = ? AND status <> 'deleted')`, post.ID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check post: %w", err)
+		}
+		if !exists {
+			return domain.NotFoundError("post")
+		}
+	}
+
+	if tagIDs != nil {
+		if err := r.replacePostTags(ctx, tx, post.ID, tagIDs); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStatus changes the post status (sets published_at on first publish)
+func (r *postRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	query := `
+		UPDATE posts
+		SET status = ?,
+			published_at = CASE WHEN ? = 'published' THEN COALESCE(published_at, NOW()) ELSE published_at END,
+			updated_at = NOW()
+		WHERE id = ?
+	`
+
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "posts",
+			Operation:  "UPDATE",
+		}
+		defer segment.End()
+	}
+
+	_, err := r.db.ExecContext(ctx, query, status, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update post status: %w", err)
+	}
+
+	return nil
+}
+
+// Delete marks a post as deleted and unlinks its tags so tag usage counts stay correct
+func (r *postRepository) Delete(ctx context.Context, id int64) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "posts",
+			Operation:  "DELETE",
+		}
+		defer segment.End()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE posts SET status = 'deleted', updated_at = NOW() WHERE id = ? AND status <> 'deleted'`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete post: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return domain.NotFoundError("post")
+	}
+
+	if err := r.replacePostTags(ctx, tx, id, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Helper function to replace tag links of a post and keep tags.usage_count in sync
+func (r *postRepository) replacePostTags(ctx context.Context, tx *sql.Tx, postID int64, tagIDs []int64) error {
+	rows, err := tx.QueryContext(ctx, `SELECT tag_id FROM post_tags WHERE post_id = ? FOR UPDATE`, postID)
+	if err != nil {
+		return fmt.Errorf("failed to query post tags: %w", err)
+	}
+	current := make(map[int64]bool)
+	for rows.Next() {
+		var tagID int64
+		if err := rows.Scan(&tagID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan post tag: %w", err)
+		}
+		current[tagID] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	wanted := make(map[int64]bool)
+	var added []int64
+	for _, tagID := range tagIDs {
+		if wanted[tagID] {
+			continue
+		}
+		wanted[tagID] = true
+		if !current[tagID] {
+			added = append(added, tagID)
+		}
+	}
+	var removed []int64
+	for tagID := range current {
+		if !wanted[tagID] {
+			removed = append(removed, tagID)
+		}
+	}
+
+	for _, tagID := range added {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO post_tags (post_id, tag_id) VALUES (?, ?)`, postID, tagID); err != nil {
+			return translateError(err, "post tag", fmt.Sprintf("failed to link tag %d", tagID))
+		}
+	}
+	if len(added) > 0 {
+		if err := adjustTagUsage(ctx, tx, added, 1); err != nil {
+			return err
+		}
+	}
+
+	if len(removed) > 0 {
+		placeholders, args := int64InClause(removed)
+		query := fmt.Sprintf(`DELETE FROM post_tags WHERE post_id = ? AND tag_id IN (%s)`, placeholders)
+		if _, err := tx.ExecContext(ctx, query, append([]interface{}{postID}, args...)...); err != nil {
+			return fmt.Errorf("failed to unlink tags: %w", err)
+		}
+		if err := adjustTagUsage(ctx, tx, removed, -1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Helper function to add delta to tags.usage_count (never below zero)
+func adjustTagUsage(ctx context.Context, tx *sql.Tx, tagIDs []int64, delta int) error {
+	placeholders, args := int64InClause(tagIDs)
+	query := fmt.Sprintf(`UPDATE tags SET usage_count = GREATEST(usage_count + ?, 0) WHERE id IN (%s)`, placeholders)
+	if _, err := tx.ExecContext(ctx, query, append([]interface{}{delta}, args...)...); err != nil {
+		return fmt.Errorf("failed to update tag usage count: %w", err)
+	}
+	return nil
+}
+
+// Helper function to build placeholders and args for an IN clause
+func int64InClause(ids []int64) (string, []interface{}) {
+	placeholders := strings.Repeat("?,", len(ids))
+	placeholders = placeholders[:len(placeholders)-1] // Remove trailing comma
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return placeholders, args
+}
@@ -0,0 +1,139 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingDriver は実行されたSQLを記録するだけのdatabase/sqlドライバーです。failOnExecが1以上の場合はその回数目のExecを失敗させます
+type recordingDriver struct {
+	mu         sync.Mutex
+	execs      []recordedExec
+	commits    int
+	rollbacks  int
+	failOnExec int
+}
+
+type recordedExec struct {
+	query string
+	args  []driver.NamedValue
+}
+
+func newRecordingDB(t *testing.T, d *recordingDriver) *sql.DB {
+	t.Helper()
+	db := sql.OpenDB(d)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func (d *recordingDriver) Connect(context.Context) (driver.Conn, error) {
+	return &recordingConn{d: d}, nil
+}
+func (d *recordingDriver) Driver() driver.Driver            { return d }
+func (d *recordingDriver) Open(string) (driver.Conn, error) { return &recordingConn{d: d}, nil }
+
+type recordingConn struct {
+	d *recordingDriver
+}
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+func (c *recordingConn) Close() error              { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) { return &recordingTx{d: c.d}, nil }
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.execs = append(c.d.execs, recordedExec{query: query, args: args})
+	if len(c.d.execs) == c.d.failOnExec {
+		return nil, errors.New("exec failed")
+	}
+	return driver.RowsAffected(len(args) / 3), nil
+}
+
+type recordingTx struct {
+	d *recordingDriver
+}
+
+func (tx *recordingTx) Commit() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.commits++
+	return nil
+}
+
+func (tx *recordingTx) Rollback() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.rollbacks++
+	return nil
+}
+
+func TestAddViewCountsUpdatesInChunks(t *testing.T) {
+	d := &recordingDriver{}
+	repo := NewPostRepository(newRecordingDB(t, d))
+
+	deltas := make(map[int64]int64)
+	for id := int64(1); id <= viewCountBatchSize*2+1; id++ {
+		deltas[id] = id % 7
+	}
+	if err := repo.AddViewCounts(context.Background(), deltas); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(d.execs) != 3 {
+		t.Fatalf("Expected 3 UPDATE statements, got %d", len(d.execs))
+	}
+	if d.commits != 1 {
+		t.Errorf("Expected all chunks in 1 transaction, got %d commits", d.commits)
+	}
+
+	// 各文は (id, delta) の組とIN句のidを持ち、全体でIDの昇順に1回ずつ更新する
+	next := int64(1)
+	for i, exec := range d.execs {
+		if !strings.HasPrefix(exec.query, "UPDATE posts SET view_count = view_count + CASE id") {
+			t.Errorf("Unexpected query: %.60s", exec.query)
+		}
+		size := len(exec.args) / 3
+		if want := min(viewCountBatchSize, len(deltas)-int(next)+1); size != want || len(exec.args)%3 != 0 {
+			t.Fatalf("Statement %d: expected %d posts, got %d args", i, want, len(exec.args))
+		}
+		if strings.Count(exec.query, "?") != len(exec.args) {
+			t.Errorf("Statement %d: %d placeholders for %d args", i, strings.Count(exec.query, "?"), len(exec.args))
+		}
+		for j := 0; j < size; j++ {
+			id, delta, inID := exec.args[j*2].Value, exec.args[j*2+1].Value, exec.args[size*2+j].Value
+			if id != next || inID != next || delta != next%7 {
+				t.Fatalf("Statement %d: expected post %d (+%d), got %v (+%v) / IN %v", i, next, next%7, id, delta, inID)
+			}
+			next++
+		}
+	}
+	if next != int64(len(deltas))+1 {
+		t.Errorf("Expected %d posts updated, got %d", len(deltas), next-1)
+	}
+}
+
+func TestAddViewCountsRollsBackWhenAChunkFails(t *testing.T) {
+	d := &recordingDriver{failOnExec: 2}
+	repo := NewPostRepository(newRecordingDB(t, d))
+
+	deltas := make(map[int64]int64)
+	for id := int64(1); id <= viewCountBatchSize+1; id++ {
+		deltas[id] = 1
+	}
+	if err := repo.AddViewCounts(context.Background(), deltas); err == nil {
+		t.Fatal("Expected an error when a chunk fails")
+	}
+
+	// 呼び出し側が全件を再送するため、先に成功したチャンクも反映しない
+	if d.commits != 0 || d.rollbacks != 1 {
+		t.Errorf("Expected a rollback without commit, got %d commits and %d rollbacks", d.commits, d.rollbacks)
+	}
+}
@@ -0,0 +1,130 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+type roleRepository struct {
+	db *sql.DB
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository(db *sql.DB) domain.RoleRepository {
+	return &roleRepository{db: db}
+}
+
+// FindByUserID retrieves the roles and permissions granted to the user (empty if none)
+func (r *roleRepository) FindByUserID(ctx context.Context, userID int64) (*domain.UserRoles, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_roles",
+			Operation:  "SELECT_WITH_JOIN",
+		}
+		defer segment.End()
+	}
+
+	// ロールに権限がない場合も名前を返すためLEFT JOIN
+	query := `
+		SELECT r.name, p.name
+		FROM user_roles ur
+		INNER JOIN roles r ON ur.role_id = r.id
+		LEFT JOIN role_permissions rp ON r.id = rp.role_id
+		LEFT JOIN permissions p ON rp.permission_id = p.id
+		WHERE ur.user_id = ?
+		ORDER BY r.name, p.name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user roles: %w", err)
+	}
+	defer rows.Close()
+
+	userRoles := &domain.UserRoles{
+		UserID:      userID,
+		Roles:       []string{},
+		Permissions: []string{},
+	}
+	seenRoles := make(map[string]bool)
+	seenPermissions := make(map[string]bool)
+	for rows.Next() {
+		var role string
+		var permission sql.NullString
+		if err := rows.Scan(&role, &permission); err != nil {
+			return nil, fmt.Errorf("failed to scan user role: %w", err)
+		}
+		if !seenRoles[role] {
+			seenRoles[role] = true
+			userRoles.Roles = append(userRoles.Roles, role)
+		}
+		if permission.Valid && !seenPermissions[permission.String] {
+			seenPermissions[permission.String] = true
+			userRoles.Permissions = append(userRoles.Permissions, permission.String)
+		}
+	}
+
+	return userRoles, rows.Err()
+}
+
+// Assign grants a role to the user (ErrRoleNotFound if the role does not exist, no-op if already granted)
+func (r *roleRepository) Assign(ctx context.Context, userID int64, role string) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_roles",
+			Operation:  "INSERT",
+		}
+		defer segment.End()
+	}
+
+	var roleID int64
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM roles WHERE name = ?`, role).Scan(&roleID)
+	if err == sql.ErrNoRows {
+		return domain.ErrRoleNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to query role: %w", err)
+	}
+
+	query := `INSERT IGNORE INTO user_roles (user_id, role_id) VALUES (?, ?)`
+	if _, err := r.db.ExecContext(ctx, query, userID, roleID); err != nil {
+		return fmt.Errorf("failed to assign role: %w", err)
+	}
+
+	return nil
+}
+
+// Revoke removes a role from the user (no-op if not granted)
+func (r *roleRepository) Revoke(ctx context.Context, userID int64, role string) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_roles",
+			Operation:  "DELETE",
+		}
+		defer segment.End()
+	}
+
+	query := `
+		DELETE ur FROM user_roles ur
+		INNER JOIN roles r ON ur.role_id = r.id
+		WHERE ur.user_id = ? AND r.name = ?
+	`
+	if _, err := r.db.ExecContext(ctx, query, userID, role); err != nil {
+		return fmt.Errorf("failed to revoke role: %w", err)
+	}
+
+	return nil
+}
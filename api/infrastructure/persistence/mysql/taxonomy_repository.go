@@ -0,0 +1,84 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+type taxonomyRepository struct {
+	db *sql.DB
+}
+
+// NewTaxonomyRepository creates a new taxonomy (category and tag) repository
+func NewTaxonomyRepository(db *sql.DB) domain.TaxonomyRepository {
+	return &taxonomyRepository{db: db}
+}
+
+// CategoryExists reports whether the category exists
+func (r *taxonomyRepository) CategoryExists(ctx context.Context, id int64) (bool, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "categories",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM categories WHERE id = ?)`, id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check category: %w", err)
+	}
+	return exists, nil
+}
+
+// FindExistingTagIDs returns the IDs among ids that exist
+func (r *taxonomyRepository) FindExistingTagIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "tags",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`SELECT id FROM tags WHERE id IN (%s)`, placeholders), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	var existing []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		existing = append(existing, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tags: %w", err)
+	}
+	return existing, nil
+}
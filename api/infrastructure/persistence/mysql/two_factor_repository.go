@@ -0,0 +1,221 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+type twoFactorRepository struct {
+	db *sql.DB
+}
+
+// NewTwoFactorRepository creates a new two-factor repository
+func NewTwoFactorRepository(db *sql.DB) domain.TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+// FindByUserID returns the user's settings (domain.ErrNotFound if 2FA was never enrolled)
+func (r *twoFactorRepository) FindByUserID(ctx context.Context, userID int64) (*domain.TwoFactorSettings, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_two_factor",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	query := `
+		SELECT user_id, secret, last_used_step, enabled_at, created_at, updated_at
+		FROM user_two_factor
+		WHERE user_id = ?
+	`
+
+	var settings domain.TwoFactorSettings
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&settings.UserID, &settings.Secret, &settings.LastUsedStep,
+		&settings.EnabledAt, &settings.CreatedAt, &settings.UpdatedAt,
+	)
+	if err != nil {
+		return nil, translateError(err, "two-factor settings", "failed to query two-factor settings")
+	}
+
+	return &settings, nil
+}
+
+// SaveSecret stores a pending secret, replacing any earlier unfinished enrollment
+func (r *twoFactorRepository) SaveSecret(ctx context.Context, userID int64, secret string) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_two_factor",
+			Operation:  "UPSERT",
+		}
+		defer segment.End()
+	}
+
+	// 有効化済みの行は上書きしない（無効化してから登録し直す）
+	query := `
+		INSERT INTO user_two_factor (user_id, secret, last_used_step, enabled_at)
+		VALUES (?, ?, 0, NULL)
+		ON DUPLICATE KEY UPDATE
+			secret = IF(enabled_at IS NULL, VALUES(secret), secret),
+			last_used_step = IF(enabled_at IS NULL, 0, last_used_step)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, secret); err != nil {
+		return fmt.Errorf("failed to save two-factor secret: %w", err)
+	}
+
+	return nil
+}
+
+// Enable marks 2FA enabled, records the step of the confirming code and replaces the recovery codes
+func (r *twoFactorRepository) Enable(ctx context.Context, userID int64, step int64, recoveryCodeHashes []string) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_two_factor",
+			Operation:  "UPDATE",
+		}
+		defer segment.End()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE user_two_factor SET enabled_at = NOW(), last_used_step = ? WHERE user_id = ? AND enabled_at IS NULL`,
+		step, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enable two-factor: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	for _, codeHash := range recoveryCodeHashes {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO user_recovery_codes (user_id, code_hash) VALUES (?, ?)`, userID, codeHash); err != nil {
+			return fmt.Errorf("failed to insert recovery code: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Disable removes the secret and recovery codes
+func (r *twoFactorRepository) Disable(ctx context.Context, userID int64) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_two_factor",
+			Operation:  "DELETE",
+		}
+		defer segment.End()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_two_factor WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete two-factor settings: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UseStep records step as used; it returns false if step is not newer than the last used one
+func (r *twoFactorRepository) UseStep(ctx context.Context, userID int64, step int64) (bool, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_two_factor",
+			Operation:  "UPDATE",
+		}
+		defer segment.End()
+	}
+
+	// 条件付きUPDATEで、同じコードの同時使用も1回だけ成功させる
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE user_two_factor SET last_used_step = ? WHERE user_id = ? AND last_used_step < ?`,
+		step, userID, step,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update last used step: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// ConsumeRecoveryCode marks an unused recovery code used; it returns false if none matched
+func (r *twoFactorRepository) ConsumeRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_recovery_codes",
+			Operation:  "UPDATE",
+		}
+		defer segment.End()
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE user_recovery_codes SET used_at = NOW() WHERE user_id = ? AND code_hash = ? AND used_at IS NULL`,
+		userID, codeHash,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
@@ -36,6 +36,8 @@ func (r *userDetailRepository) FindDetailByID(ctx context.Context, id int64) (*d
 	query := `
 		SELECT 
 			u.id, u.username, u.email, u.status, u.email_verified, u.last_login_at, u.created_at, u.updated_at,
+			-- 2段階認証（有効化済みのみ）
+			EXISTS(SELECT 1 FROM user_two_factor WHERE user_id = u.id AND enabled_at IS NOT NULL) as two_factor_enabled,
 			p.first_name, p.last_name, p.display_name, p.bio, p.avatar_url, p.birth_date, 
 			p.gender, p.country_code, p.timezone, p.language_code, p.phone_number, p.website_url,
 			-- フォロー統計（サブクエリ）
@@ -55,7 +57,7 @@ func (r *userDetailRepository) FindDetailByID(ctx context.Context, id int64) (*d
 	var profile domain.UserProfile
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&detail.ID, &detail.Username, &detail.Email, &detail.Status, &detail.EmailVerified, 
-		&detail.LastLoginAt, &detail.CreatedAt, &detail.UpdatedAt,
+		&detail.LastLoginAt, &detail.CreatedAt, &detail.UpdatedAt, &detail.TwoFactorEnabled,
 		&profile.FirstName, &profile.LastName, &profile.DisplayName, &profile.Bio, &profile.AvatarURL,
 		&profile.BirthDate, &profile.Gender, &profile.CountryCode, &profile.Timezone, 
 		&profile.Language, &profile.PhoneNumber, &profile.WebsiteURL,
@@ -65,7 +67,7 @@ func (r *userDetailRepository) FindDetailByID(ctx context.Context, id int64) (*d
 		&detail.Stats.CommentCount,
 	)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "user", "failed to query user detail")
 	}
 	detail.Profile = &profile
 
@@ -159,7 +161,7 @@ func (r *userDetailRepository) FindDetailByUsername(ctx context.Context, usernam
 	query := `SELECT id FROM users WHERE username = ?`
 	err := r.db.QueryRowContext(ctx, query, username).Scan(&id)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "user", "failed to query user")
 	}
 	
 	// IDで詳細情報を取得
@@ -0,0 +1,124 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+type userProfileRepository struct {
+	db *sql.DB
+}
+
+// NewUserProfileRepository creates a new user profile repository
+func NewUserProfileRepository(db *sql.DB) domain.UserProfileRepository {
+	return &userProfileRepository{db: db}
+}
+
+// FindByUserID returns the profile (empty if the user has no profile row; domain.ErrNotFound if the user does not exist)
+func (r *userProfileRepository) FindByUserID(ctx context.Context, userID int64) (*domain.UserProfile, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_profiles",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	// ユーザーの存在確認を兼ねてusersからLEFT JOINする
+	query := `
+		SELECT
+			p.first_name, p.last_name, p.display_name, p.bio, p.avatar_url,
+			DATE_FORMAT(p.birth_date, '%Y-%m-%d'), p.gender, p.country_code, p.timezone,
+			p.language_code, p.phone_number, p.website_url
+		FROM users u
+		LEFT JOIN user_profiles p ON u.id = p.user_id
+		WHERE u.id = ?
+	`
+
+	var profile domain.UserProfile
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&profile.FirstName, &profile.LastName, &profile.DisplayName, &profile.Bio, &profile.AvatarURL,
+		&profile.BirthDate, &profile.Gender, &profile.CountryCode, &profile.Timezone,
+		&profile.Language, &profile.PhoneNumber, &profile.WebsiteURL,
+	)
+	if err != nil {
+		return nil, translateError(err, "user", "failed to query profile")
+	}
+
+	return &profile, nil
+}
+
+// Upsert inserts or replaces the editable profile fields (avatar_url is left unchanged)
+func (r *userProfileRepository) Upsert(ctx context.Context, userID int64, profile *domain.UserProfile) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_profiles",
+			Operation:  "UPSERT",
+		}
+		defer segment.End()
+	}
+
+	query := `
+		INSERT INTO user_profiles (
+			user_id, first_name, last_name, display_name, bio, birth_date, gender,
+			country_code, timezone, language_code, phone_number, website_url
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			first_name = VALUES(first_name),
+			last_name = VALUES(last_name),
+			display_name = VALUES(display_name),
+			bio = VALUES(bio),
+			birth_date = VALUES(birth_date),
+			gender = VALUES(gender),
+			country_code = VALUES(country_code),
+			timezone = VALUES(timezone),
+			language_code = VALUES(language_code),
+			phone_number = VALUES(phone_number),
+			website_url = VALUES(website_url)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		userID, profile.FirstName, profile.LastName, profile.DisplayName, profile.Bio, profile.BirthDate, profile.Gender,
+		profile.CountryCode, profile.Timezone, profile.Language, profile.PhoneNumber, profile.WebsiteURL,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert profile: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateAvatarURL sets avatar_url, creating the profile row if needed (nil clears it)
+func (r *userProfileRepository) UpdateAvatarURL(ctx context.Context, userID int64, avatarURL *string) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_profiles",
+			Operation:  "UPSERT",
+		}
+		defer segment.End()
+	}
+
+	query := `
+		INSERT INTO user_profiles (user_id, avatar_url) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE avatar_url = VALUES(avatar_url)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, avatarURL); err != nil {
+		return fmt.Errorf("failed to update avatar url: %w", err)
+	}
+
+	return nil
+}
@@ -3,6 +3,8 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/newrelic/go-agent/v3/newrelic"
@@ -18,7 +20,30 @@ func NewUserRepository(db *sql.DB) domain.UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) FindAll(ctx context.Context) ([]domain.User, error) {
+// userSortColumns は並び替えに使える列（クエリに埋め込むため固定値のみ）
+var userSortColumns = map[string]string{
+	domain.UserSortCreatedAt: "u.created_at",
+	domain.UserSortUsername:  "u.username",
+	domain.UserSortID:        "u.id",
+}
+
+// userSelectColumns はdomain.Userに読み込む列（scanUserと同じ順序）。
+// 生年月日はuser_profilesにあるためLEFT JOINする
+const userSelectColumns = `u.id, u.username, u.email, u.status, u.email_verified, u.last_login_at,
+	DATE_FORMAT(p.birth_date, '%Y-%m-%d'), u.created_at, u.updated_at
+	FROM users u
+	LEFT JOIN user_profiles p ON u.id = p.user_id`
+
+func scanUser(row rowScanner) (domain.User, error) {
+	var user domain.User
+	err := row.Scan(
+		&user.ID, &user.Username, &user.Email, &user.Status, &user.EmailVerified, &user.LastLoginAt,
+		&user.BirthDate, &user.CreatedAt, &user.UpdatedAt,
+	)
+	return user, err
+}
+
+func (r *userRepository) FindAll(ctx context.Context, query domain.UserListQuery) ([]domain.User, error) {
 	txn := newrelic.FromContext(ctx)
 	if txn != nil {
 		segment := &newrelic.DatastoreSegment{
@@ -30,28 +55,125 @@ func (r *userRepository) FindAll(ctx context.Context) ([]domain.User, error) {
 		defer segment.End()
 	}
 
-	query := `SELECT id, username, email, created_at, updated_at FROM users ORDER BY created_at DESC`
-	
-	rows, err := r.db.QueryContext(ctx, query)
+	column, ok := userSortColumns[query.Sort]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown sort field %q", domain.ErrInvalidUserListQuery, query.Sort)
+	}
+	direction, comparison := "ASC", ">"
+	if query.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	conditions, args := userListConditions(query.Filter)
+
+	// 並び順の列が同じ値の行はIDで順序を決める
+	if query.After != nil {
+		switch query.Sort {
+		case domain.UserSortID:
+			conditions = append(conditions, fmt.Sprintf("u.id %s ?", comparison))
+			args = append(args, query.After.ID)
+		default:
+			var value interface{} = query.After.CreatedAt
+			if query.Sort == domain.UserSortUsername {
+				value = query.After.Username
+			}
+			conditions = append(conditions, fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND u.id %[2]s ?))", column, comparison))
+			args = append(args, value, value, query.After.ID)
+		}
+	}
+
+	orderBy := fmt.Sprintf("%s %s", column, direction)
+	if query.Sort != domain.UserSortID {
+		orderBy += fmt.Sprintf(", u.id %s", direction)
+	}
+
+	sqlQuery := fmt.Sprintf(`SELECT %s %s ORDER BY %s LIMIT ? OFFSET ?`, userSelectColumns, whereClause(conditions), orderBy)
+	args = append(args, query.Limit, query.Offset)
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to query users: %w", err)
 	}
 	defer rows.Close()
 
 	var users []domain.User
 	for rows.Next() {
-		var user domain.User
-		err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt)
+		user, err := scanUser(rows)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
-		// Note: age is not in the database schema, so we don't set it
 		users = append(users, user)
 	}
 
 	return users, rows.Err()
 }
 
+// Count returns the number of users matching the filter
+func (r *userRepository) Count(ctx context.Context, filter domain.UserListFilter) (int64, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "users",
+			Operation:  "COUNT",
+		}
+		defer segment.End()
+	}
+
+	conditions, args := userListConditions(filter)
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM users u %s`, whereClause(conditions))
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	return count, nil
+}
+
+// userListConditions は一覧の絞り込み条件をWHERE句の条件と引数に変換します
+func userListConditions(filter domain.UserListFilter) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if len(filter.Statuses) > 0 {
+		placeholders := strings.Repeat("?,", len(filter.Statuses))
+		conditions = append(conditions, fmt.Sprintf("u.status IN (%s)", placeholders[:len(placeholders)-1]))
+		for _, status := range filter.Statuses {
+			args = append(args, status)
+		}
+	}
+	if filter.EmailVerified != nil {
+		conditions = append(conditions, "u.email_verified = ?")
+		args = append(args, *filter.EmailVerified)
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "u.created_at >= ?")
+		args = append(args, *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "u.created_at < ?")
+		args = append(args, *filter.CreatedTo)
+	}
+	if filter.UsernamePrefix != "" {
+		conditions = append(conditions, "u.username LIKE ?")
+		args = append(args, likePrefixEscaper.Replace(filter.UsernamePrefix)+"%")
+	}
+
+	return conditions, args
+}
+
+// likePrefixEscaper はLIKEの前方一致でワイルドカードをエスケープします
+var likePrefixEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(conditions, " AND ")
+}
+
 func (r *userRepository) FindByID(ctx context.Context, id int64) (*domain.User, error) {
 	txn := newrelic.FromContext(ctx)
 	if txn != nil {
@@ -64,17 +186,60 @@ func (r *userRepository) FindByID(ctx context.Context, id int64) (*domain.User,
 		defer segment.End()
 	}
 
-	query := `SELECT id, username, email, created_at, updated_at FROM users WHERE id = ?`
+	query := `SELECT ` + userSelectColumns + ` WHERE u.id = ?`
 	
-	var user domain.User
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt)
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "user", "failed to query user")
 	}
-	// Note: age is not in the database schema, so we don't set it
 	return &user, nil
 }
 
+func (r *userRepository) FindByUsernames(ctx context.Context, usernames []string) ([]domain.User, error) {
+	if len(usernames) == 0 {
+		return []domain.User{}, nil
+	}
+
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "users",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	placeholders := strings.Repeat("?,", len(usernames))
+	placeholders = placeholders[:len(placeholders)-1]
+	args := make([]interface{}, len(usernames))
+	for i, username := range usernames {
+		args[i] = username
+	}
+
+	query := fmt.Sprintf(`SELECT %s WHERE u.username IN (%s)`, userSelectColumns, placeholders)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// Create inserts the user and its birth date (ErrUserAlreadyExists on duplicate username/email).
+// Users created here have no usable password and sign in after a password reset
 func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 	txn := newrelic.FromContext(ctx)
 	if txn != nil {
@@ -87,29 +252,51 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 		defer segment.End()
 	}
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `INSERT INTO users (username, email, password_hash, status, email_verified, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
 	now := time.Now()
 	user.CreatedAt = now
 	user.UpdatedAt = now
+	if user.Status == "" {
+		user.Status = domain.UserStatusActive
+	}
 
-	// Use default values for required fields that don't exist in domain.User
+	// bcryptとして検証できない値にしてパスワードでのログインを不可にする
 	passwordHash := "$2a$10$defaultpasswordhash" // Default placeholder
-	status := "active"
-	emailVerified := 0
 
-	result, err := r.db.ExecContext(ctx, query, user.Name, user.Email, passwordHash, status, emailVerified, user.CreatedAt, user.UpdatedAt)
+	result, err := tx.ExecContext(ctx, query, user.Username, user.Email, passwordHash, user.Status, user.EmailVerified, user.CreatedAt, user.UpdatedAt)
 	if err != nil {
-		return err
+		if isDuplicateEntry(err) {
+			return domain.ErrUserAlreadyExists
+		}
+		return fmt.Errorf("failed to insert user: %w", err)
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get last insert id: %w", err)
 	}
 	user.ID = id
+
+	if user.BirthDate != nil {
+		if err := saveBirthDate(ctx, tx, user.ID, user.BirthDate); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
+// Update saves username, email, email_verified and the birth date (status is changed by UserStatusRepository)
 func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 	txn := newrelic.FromContext(ctx)
 	if txn != nil {
@@ -122,13 +309,56 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 		defer segment.End()
 	}
 
-	query := `UPDATE users SET username = ?, email = ?, updated_at = ? WHERE id = ?`
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE users SET username = ?, email = ?, email_verified = ?, updated_at = ? WHERE id = ?`
 	user.UpdatedAt = time.Now()
 
-	_, err := r.db.ExecContext(ctx, query, user.Name, user.Email, user.UpdatedAt, user.ID)
-	return err
+	if _, err := tx.ExecContext(ctx, query, user.Username, user.Email, user.EmailVerified, user.UpdatedAt, user.ID); err != nil {
+		if isDuplicateEntry(err) {
+			return domain.ErrUserAlreadyExists
+		}
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if err := saveBirthDate(ctx, tx, user.ID, user.BirthDate); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// saveBirthDate はuser_profiles.birth_dateを保存します（nilはクリア。プロフィール行がなければ作成）
+func saveBirthDate(ctx context.Context, tx *sql.Tx, userID int64, birthDate *string) error {
+	query := `
+		INSERT INTO user_profiles (user_id, birth_date) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE birth_date = VALUES(birth_date)
+	`
+	if birthDate == nil {
+		// プロフィール行を作らずにクリアする
+		query = `UPDATE user_profiles SET birth_date = ? WHERE user_id = ?`
+		if _, err := tx.ExecContext(ctx, query, nil, userID); err != nil {
+			return fmt.Errorf("failed to clear birth date: %w", err)
+		}
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, query, userID, *birthDate); err != nil {
+		return fmt.Errorf("failed to save birth date: %w", err)
+	}
+	return nil
 }
 
+// Delete soft-deletes the user (status = 'deleted') and records the change.
+// Posts and comments are kept so the account can be restored
 func (r *userRepository) Delete(ctx context.Context, id int64) error {
 	txn := newrelic.FromContext(ctx)
 	if txn != nil {
@@ -136,13 +366,38 @@ func (r *userRepository) Delete(ctx context.Context, id int64) error {
 			StartTime:  txn.StartSegmentNow(),
 			Product:    newrelic.DatastoreMySQL,
 			Collection: "users",
-			Operation:  "DELETE",
+			Operation:  "UPDATE",
 		}
 		defer segment.End()
 	}
 
-	query := `DELETE FROM users WHERE id = ?`
-	
-	_, err := r.db.ExecContext(ctx, query, id)
-	return err
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	if err := tx.QueryRowContext(ctx, `SELECT status FROM users WHERE id = ? FOR UPDATE`, id).Scan(&status); err != nil {
+		return translateError(err, "user", "failed to query user")
+	}
+	// 削除済みのユーザーは存在しないものとして扱う
+	if status == domain.UserStatusDeleted {
+		return domain.NotFoundError("user")
+	}
+
+	change := &domain.UserStatusChange{
+		UserID:     id,
+		FromStatus: status,
+		ToStatus:   domain.UserStatusDeleted,
+	}
+	if err := changeUserStatus(ctx, tx, change); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,147 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+type userStatusRepository struct {
+	db *sql.DB
+}
+
+// NewUserStatusRepository creates a new user status repository
+func NewUserStatusRepository(db *sql.DB) domain.UserStatusRepository {
+	return &userStatusRepository{db: db}
+}
+
+// FindStatus returns users.status (domain.ErrNotFound if the user does not exist)
+func (r *userStatusRepository) FindStatus(ctx context.Context, userID int64) (string, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "users",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	var status string
+	if err := r.db.QueryRowContext(ctx, `SELECT status FROM users WHERE id = ?`, userID).Scan(&status); err != nil {
+		return "", translateError(err, "user", "failed to query user status")
+	}
+
+	return status, nil
+}
+
+// ChangeStatus updates users.status and inserts a history row in one transaction
+func (r *userStatusRepository) ChangeStatus(ctx context.Context, change *domain.UserStatusChange) error {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "users",
+			Operation:  "UPDATE",
+		}
+		defer segment.End()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := changeUserStatus(ctx, tx, change); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// FindHistory retrieves status changes for the user, newest first
+func (r *userStatusRepository) FindHistory(ctx context.Context, userID int64, limit int) ([]domain.UserStatusChange, error) {
+	txn := newrelic.FromContext(ctx)
+	if txn != nil {
+		segment := &newrelic.DatastoreSegment{
+			StartTime:  txn.StartSegmentNow(),
+			Product:    newrelic.DatastoreMySQL,
+			Collection: "user_status_history",
+			Operation:  "SELECT",
+		}
+		defer segment.End()
+	}
+
+	query := `
+		SELECT id, user_id, from_status, to_status, reason, changed_by, created_at
+		FROM user_status_history
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []domain.UserStatusChange
+	for rows.Next() {
+		var change domain.UserStatusChange
+		if err := rows.Scan(&change.ID, &change.UserID, &change.FromStatus, &change.ToStatus, &change.Reason, &change.ChangedBy, &change.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan status history: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// changeUserStatus はFromStatusのままの場合だけusers.statusを更新し、履歴を記録します
+// （同時に別の変更が行われていた場合はErrInvalidUserStatusTransition）
+func changeUserStatus(ctx context.Context, tx *sql.Tx, change *domain.UserStatusChange) error {
+	result, err := tx.ExecContext(ctx,
+		`UPDATE users SET status = ? WHERE id = ? AND status = ?`,
+		change.ToStatus, change.UserID, change.FromStatus,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update user status: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return domain.ErrInvalidUserStatusTransition
+	}
+
+	change.CreatedAt = time.Now()
+	result, err = tx.ExecContext(ctx,
+		`INSERT INTO user_status_history (user_id, from_status, to_status, reason, changed_by, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		change.UserID, change.FromStatus, change.ToStatus, change.Reason, change.ChangedBy, change.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert status history: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	change.ID = id
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// S3Config holds settings for an S3-compatible object storage (AWS S3, MinIO, R2, ...)
+type S3Config struct {
+	Endpoint        string // e.g. https://s3.ap-northeast-1.amazonaws.com, http://minio:9000
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// UsePathStyle uses {endpoint}/{bucket}/{key} instead of {bucket}.{host}/{key} (required by MinIO)
+	UsePathStyle bool
+	// PublicBaseURL is used for URL() when objects are served through a CDN; defaults to the object URL
+	PublicBaseURL string
+	// CacheControl is stored with each object so the storage returns it as a response header
+	CacheControl string
+}
+
+type s3BlobStore struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3BlobStore creates a blob store backed by an S3-compatible API (requests are signed with AWS Signature V4)
+func NewS3BlobStore(config S3Config) (domain.BlobStore, error) {
+	endpoint, err := url.Parse(strings.TrimRight(config.Endpoint, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint: %q", config.Endpoint)
+	}
+	if config.Bucket == "" || config.Region == "" {
+		return nil, fmt.Errorf("S3 bucket and region are required")
+	}
+
+	return &s3BlobStore{
+		config:   config,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		now:      time.Now,
+	}, nil
+}
+
+func (s *s3BlobStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	headers := http.Header{}
+	headers.Set("Content-Type", contentType)
+	if s.config.CacheControl != "" {
+		headers.Set("Cache-Control", s.config.CacheControl)
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, key, headers, data)
+	if err != nil {
+		return fmt.Errorf("failed to put object: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to put object: %s: %s", resp.Status, body)
+	}
+	return nil
+}
+
+func (s *s3BlobStore) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, http.Header{}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// S3は存在しないキーでも204を返すが、互換実装の404も成功として扱う
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to delete object: %s: %s", resp.Status, body)
+	}
+	return nil
+}
+
+func (s *s3BlobStore) URL(key string) string {
+	if s.config.PublicBaseURL != "" {
+		return strings.TrimRight(s.config.PublicBaseURL, "/") + "/" + key
+	}
+	return s.objectURL(key).String()
+}
+
+func (s *s3BlobStore) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	if s.config.UsePathStyle {
+		u.Path = u.Path + "/" + s.config.Bucket + "/" + key
+	} else {
+		u.Host = s.config.Bucket + "." + u.Host
+		u.Path = u.Path + "/" + key
+	}
+	return &u
+}
+
+func (s *s3BlobStore) do(ctx context.Context, method, key string, headers http.Header, body []byte) (*http.Response, error) {
+	u := s.objectURL(key)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	s.sign(req, body, s.now().UTC())
+
+	return s.client.Do(req)
+}
+
+// sign はAWS Signature Version 4でリクエストに署名します
+// （https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html）
+func (s *s3BlobStore) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	names := make([]string, 0, len(req.Header))
+	canonicalHeaders := make(map[string]string, len(req.Header))
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		names = append(names, lower)
+		canonicalHeaders[lower] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	sort.Strings(names)
+
+	var headerBlock strings.Builder
+	for _, name := range names {
+		headerBlock.WriteString(name + ":" + canonicalHeaders[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		headerBlock.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/usecase"
+)
+
+// AccountStatusHandlerV2 はフレームワーク非依存のアカウント状態管理ハンドラー
+type AccountStatusHandlerV2 struct {
+	usecase usecase.AccountStatusUsecase
+}
+
+// NewAccountStatusHandlerV2 creates a new framework-independent account status handler
+func NewAccountStatusHandlerV2(usecase usecase.AccountStatusUsecase) *AccountStatusHandlerV2 {
+	return &AccountStatusHandlerV2{usecase: usecase}
+}
+
+// statusChangeRequest は状態変更リクエストのボディ
+type statusChangeRequest struct {
+	Reason string `json:"reason"`
+}
+
+// DeactivateUser はアカウントを休止状態にします（フレームワーク非依存）
+func (h *AccountStatusHandlerV2) DeactivateUser(ctx HTTPContext, userID int64) error {
+	return h.changeStatus(ctx, userID, h.usecase.DeactivateUser, "Failed to deactivate user")
+}
+
+// SuspendUser はアカウントを停止します（フレームワーク非依存）
+func (h *AccountStatusHandlerV2) SuspendUser(ctx HTTPContext, userID int64) error {
+	return h.changeStatus(ctx, userID, h.usecase.SuspendUser, "Failed to suspend user")
+}
+
+// ReactivateUser は休止・停止中のアカウントを再開します（フレームワーク非依存）
+func (h *AccountStatusHandlerV2) ReactivateUser(ctx HTTPContext, userID int64) error {
+	return h.changeStatus(ctx, userID, h.usecase.ReactivateUser, "Failed to reactivate user")
+}
+
+// RestoreUser は論理削除されたアカウントを復元します（フレームワーク非依存）
+func (h *AccountStatusHandlerV2) RestoreUser(ctx HTTPContext, userID int64) error {
+	return h.changeStatus(ctx, userID, h.usecase.RestoreUser, "Failed to restore user")
+}
+
+// GetStatusHistory はアカウント状態の変更履歴を取得します（フレームワーク非依存）
+func (h *AccountStatusHandlerV2) GetStatusHistory(ctx HTTPContext, userID int64) error {
+	changes, err := h.usecase.GetStatusHistory(ctx.Context(), userID)
+	if err != nil {
+		return writeError(ctx, err, "Failed to retrieve status history")
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"history": changes,
+	})
+}
+
+// changeStatus はリクエストボディの理由を読み取り、状態変更を実行して変更履歴を返します
+func (h *AccountStatusHandlerV2) changeStatus(ctx HTTPContext, userID int64, change func(ctx context.Context, userID int64, reason string) (*domain.UserStatusChange, error), message string) error {
+	var req statusChangeRequest
+	if err := ctx.Bind(&req); err != nil {
+		return writeError(ctx, domain.ErrInvalidUserStatusInput, "")
+	}
+
+	result, err := change(ctx.Context(), userID, req.Reason)
+	if err != nil {
+		return writeError(ctx, err, message)
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
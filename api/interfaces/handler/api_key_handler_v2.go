@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/usecase"
+)
+
+// APIKeyHandlerV2 はフレームワーク非依存のAPIキー管理ハンドラー
+type APIKeyHandlerV2 struct {
+	usecase usecase.APIKeyUsecase
+}
+
+// NewAPIKeyHandlerV2 creates a new framework-independent API key handler
+func NewAPIKeyHandlerV2(usecase usecase.APIKeyUsecase) *APIKeyHandlerV2 {
+	return &APIKeyHandlerV2{usecase: usecase}
+}
+
+// CreateAPIKey はAPIキーを発行します（フレームワーク非依存）。シークレットはこのレスポンスでのみ返します
+func (h *APIKeyHandlerV2) CreateAPIKey(ctx HTTPContext, userID int64) error {
+	var input usecase.CreateAPIKeyInput
+	if err := ctx.Bind(&input); err != nil {
+		return writeError(ctx, domain.ErrInvalidAPIKeyInput, "")
+	}
+
+	key, err := h.usecase.CreateAPIKey(ctx.Context(), userID, input)
+	if err != nil {
+		return writeError(ctx, err, "Failed to create API key")
+	}
+
+	return ctx.JSON(http.StatusCreated, key)
+}
+
+// ListAPIKeys はユーザーのAPIキー一覧を取得します（フレームワーク非依存）
+func (h *APIKeyHandlerV2) ListAPIKeys(ctx HTTPContext, userID int64) error {
+	keys, err := h.usecase.ListAPIKeys(ctx.Context(), userID)
+	if err != nil {
+		return writeError(ctx, err, "Failed to retrieve API keys")
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"apiKeys": keys,
+	})
+}
+
+// RevokeAPIKey はAPIキーを失効させます（フレームワーク非依存）
+func (h *APIKeyHandlerV2) RevokeAPIKey(ctx HTTPContext, userID, keyID int64) error {
+	if err := h.usecase.RevokeAPIKey(ctx.Context(), userID, keyID); err != nil {
+		return writeError(ctx, err, "Failed to revoke API key")
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
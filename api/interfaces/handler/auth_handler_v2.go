@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/usecase"
+)
+
+// AuthHandlerV2 はフレームワーク非依存の認証ハンドラー
+type AuthHandlerV2 struct {
+	usecase usecase.AuthUsecase
+}
+
+// NewAuthHandlerV2 creates a new framework-independent auth handler
+func NewAuthHandlerV2(usecase usecase.AuthUsecase) *AuthHandlerV2 {
+	return &AuthHandlerV2{usecase: usecase}
+}
+
+// refreshTokenRequest は /auth/refresh, /auth/logout のリクエストボディ
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// twoFactorLoginRequest は /auth/login/2fa のリクエストボディ（codeはTOTPまたはリカバリーコード）
+type twoFactorLoginRequest struct {
+	ChallengeToken string `json:"challengeToken"`
+	Code           string `json:"code"`
+}
+
+// Register はユーザーを登録してトークンを発行します（フレームワーク非依存）
+func (h *AuthHandlerV2) Register(ctx HTTPContext) error {
+	var input usecase.RegisterInput
+	if err := ctx.Bind(&input); err != nil {
+		return writeError(ctx, domain.ErrInvalidAuthInput, "")
+	}
+
+	user, tokens, err := h.usecase.Register(ctx.Context(), input)
+	if err != nil {
+		return writeError(ctx, err, "Failed to register user")
+	}
+
+	return ctx.JSON(http.StatusCreated, map[string]interface{}{
+		"user":   toAPIUser(user),
+		"tokens": tokens,
+	})
+}
+
+// Login はパスワードを検証してトークンを発行します（フレームワーク非依存）。
+// 2段階認証が有効なアカウントにはトークンの代わりにチャレンジを返します
+func (h *AuthHandlerV2) Login(ctx HTTPContext) error {
+	var input usecase.LoginInput
+	if err := ctx.Bind(&input); err != nil {
+		return writeError(ctx, domain.ErrInvalidAuthInput, "")
+	}
+
+	result, err := h.usecase.Login(ctx.Context(), input)
+	if err != nil {
+		return writeError(ctx, err, "Failed to log in")
+	}
+	if result.Challenge != nil {
+		return ctx.JSON(http.StatusOK, result.Challenge)
+	}
+
+	return ctx.JSON(http.StatusOK, result.Tokens)
+}
+
+// LoginTwoFactor はチャレンジトークンと2段階認証コードを検証してトークンを発行します（フレームワーク非依存）
+func (h *AuthHandlerV2) LoginTwoFactor(ctx HTTPContext) error {
+	var req twoFactorLoginRequest
+	if err := ctx.Bind(&req); err != nil {
+		return writeError(ctx, domain.ErrInvalidAuthInput, "")
+	}
+
+	tokens, err := h.usecase.CompleteTwoFactorLogin(ctx.Context(), req.ChallengeToken, req.Code)
+	if err != nil {
+		return writeError(ctx, err, "Failed to log in")
+	}
+
+	return ctx.JSON(http.StatusOK, tokens)
+}
+
+// Refresh はリフレッシュトークンをローテーションします（フレームワーク非依存）
+func (h *AuthHandlerV2) Refresh(ctx HTTPContext) error {
+	var req refreshTokenRequest
+	if err := ctx.Bind(&req); err != nil {
+		return writeError(ctx, domain.ErrInvalidAuthInput, "")
+	}
+
+	tokens, err := h.usecase.Refresh(ctx.Context(), req.RefreshToken)
+	if err != nil {
+		return writeError(ctx, err, "Failed to refresh token")
+	}
+
+	return ctx.JSON(http.StatusOK, tokens)
+}
+
+// Logout はリフレッシュトークンを失効させます（フレームワーク非依存）
+func (h *AuthHandlerV2) Logout(ctx HTTPContext) error {
+	var req refreshTokenRequest
+	if err := ctx.Bind(&req); err != nil {
+		return writeError(ctx, domain.ErrInvalidAuthInput, "")
+	}
+
+	if err := h.usecase.Logout(ctx.Context(), req.RefreshToken); err != nil {
+		return writeError(ctx, err, "Failed to log out")
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Me は認証済みユーザーを返します（フレームワーク非依存）
+func (h *AuthHandlerV2) Me(ctx HTTPContext) error {
+	user, ok := domain.AuthUserFromContext(ctx.Context())
+	if !ok {
+		return writeError(ctx, domain.ErrInvalidToken, "")
+	}
+
+	return ctx.JSON(http.StatusOK, user)
+}
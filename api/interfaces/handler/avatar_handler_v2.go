@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/gen"
+	"github.com/rssh-jp/test-api/api/usecase"
+)
+
+const (
+	// avatarFormField はアバター画像を送るmultipartのフィールド名
+	avatarFormField = "avatar"
+	// avatarMultipartOverhead はファイル以外のmultipartヘッダー等に許容するサイズ
+	avatarMultipartOverhead = 64 << 10
+)
+
+// AvatarHandlerV2 はフレームワーク非依存のアバター画像ハンドラー
+type AvatarHandlerV2 struct {
+	usecase usecase.AvatarUsecase
+}
+
+// NewAvatarHandlerV2 creates a new framework-independent avatar handler
+func NewAvatarHandlerV2(usecase usecase.AvatarUsecase) *AvatarHandlerV2 {
+	return &AvatarHandlerV2{usecase: usecase}
+}
+
+// UploadAvatar はmultipart/form-dataのavatarフィールドの画像をアップロードします（フレームワーク非依存）
+func (h *AvatarHandlerV2) UploadAvatar(ctx HTTPContext, userID int64) error {
+	req := ctx.Request()
+	req.Body = http.MaxBytesReader(ctx.Response(), req.Body, usecase.MaxAvatarBytes+avatarMultipartOverhead)
+
+	file, _, err := req.FormFile(avatarFormField)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return writeAvatarTooLarge(ctx)
+		}
+		return writeError(ctx, fmt.Errorf("%w: multipart field %q is required", domain.ErrInvalidAvatar, avatarFormField), "")
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, usecase.MaxAvatarBytes+1))
+	if err != nil {
+		return writeError(ctx, fmt.Errorf("%w: failed to read file", domain.ErrInvalidAvatar), "")
+	}
+	if len(data) > usecase.MaxAvatarBytes {
+		return writeAvatarTooLarge(ctx)
+	}
+
+	avatar, err := h.usecase.UploadAvatar(ctx.Context(), userID, data)
+	if err != nil {
+		return writeError(ctx, err, "Failed to upload avatar")
+	}
+
+	return ctx.JSON(http.StatusOK, avatar)
+}
+
+// DeleteAvatar はアバター画像を削除します（フレームワーク非依存）
+func (h *AvatarHandlerV2) DeleteAvatar(ctx HTTPContext, userID int64) error {
+	if err := h.usecase.DeleteAvatar(ctx.Context(), userID); err != nil {
+		return writeError(ctx, err, "Failed to delete avatar")
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func writeAvatarTooLarge(ctx HTTPContext) error {
+	code := "payload_too_large"
+	return ctx.JSON(http.StatusRequestEntityTooLarge, gen.Error{
+		Code:    &code,
+		Message: fmt.Sprintf("avatar must be at most %d bytes", usecase.MaxAvatarBytes),
+	})
+}
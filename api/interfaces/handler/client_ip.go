@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// NewIPExtractor は信頼するプロキシ（CIDRのカンマ区切り）からの接続の場合だけX-Forwarded-Forを使うIPExtractorを返します。
+// 空の場合はヘッダーを使わず接続元のIPを返します（クライアントが自由に書き換えられるため）。
+// c.RealIP() はこの設定に従うため、IP単位のレート制限や閲覧数の重複判定をヘッダーの偽装で回避できません
+func NewIPExtractor(trustedProxies string) (echo.IPExtractor, error) {
+	if strings.TrimSpace(trustedProxies) == "" {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	// Echoのデフォルト（ループバック・リンクローカル・プライベートアドレスを信頼）は使わない
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, cidr := range strings.Split(trustedProxies, ",") {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
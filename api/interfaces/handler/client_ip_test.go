@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// visitorOf は remoteAddr から X-Forwarded-For 付きで届いたリクエストの訪問者（閲覧数の重複判定に使うIP）を返します
+func visitorOf(t *testing.T, extractor echo.IPExtractor, remoteAddr, forwardedFor string) string {
+	t.Helper()
+	e := echo.New()
+	e.IPExtractor = extractor
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/1", nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+	}
+	req.Header.Set(echo.HeaderXRealIP, "198.51.100.99")
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	setVisitor(c)
+	return domain.VisitorFromContext(c.Request().Context())
+}
+
+func TestNewIPExtractorIgnoresForwardedHeadersByDefault(t *testing.T) {
+	extractor, err := NewIPExtractor("")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if visitor := visitorOf(t, extractor, "203.0.113.5:4321", "198.51.100.1"); visitor != "203.0.113.5" {
+		t.Errorf("Expected the peer address, got %s", visitor)
+	}
+	// プライベートアドレスからの接続でもヘッダーは信頼しない
+	if visitor := visitorOf(t, extractor, "10.0.0.2:4321", "198.51.100.1"); visitor != "10.0.0.2" {
+		t.Errorf("Expected the peer address, got %s", visitor)
+	}
+}
+
+func TestNewIPExtractorTrustsOnlyConfiguredProxies(t *testing.T) {
+	extractor, err := NewIPExtractor("10.0.0.0/8, 192.0.2.0/24")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 信頼するプロキシ経由なら、プロキシを除いた最も右のアドレスがクライアント
+	if visitor := visitorOf(t, extractor, "10.0.0.2:4321", "198.51.100.7, 192.0.2.10"); visitor != "198.51.100.7" {
+		t.Errorf("Expected the forwarded client address, got %s", visitor)
+	}
+	// クライアントが先頭に書き足したアドレスは使わない
+	if visitor := visitorOf(t, extractor, "10.0.0.2:4321", "1.1.1.1, 198.51.100.7"); visitor != "198.51.100.7" {
+		t.Errorf("Expected the address appended by the proxy, got %s", visitor)
+	}
+	// 信頼していない接続元のヘッダーは無視する
+	if visitor := visitorOf(t, extractor, "203.0.113.5:4321", "198.51.100.7"); visitor != "203.0.113.5" {
+		t.Errorf("Expected the peer address for an untrusted peer, got %s", visitor)
+	}
+}
+
+func TestNewIPExtractorRejectsInvalidCIDR(t *testing.T) {
+	if _, err := NewIPExtractor("10.0.0.1"); err == nil {
+		t.Error("Expected an error for an address without a prefix length")
+	}
+}
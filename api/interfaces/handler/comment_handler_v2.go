@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/usecase"
+)
+
+// CommentHandlerV2 はフレームワーク非依存のコメントハンドラー
+type CommentHandlerV2 struct {
+	usecase usecase.CommentUsecase
+}
+
+// NewCommentHandlerV2 creates a new framework-independent comment handler
+func NewCommentHandlerV2(usecase usecase.CommentUsecase) *CommentHandlerV2 {
+	return &CommentHandlerV2{usecase: usecase}
+}
+
+// postCommentRequest は POST /posts/:id/comments のリクエストボディ
+type postCommentRequest struct {
+	UserID   int64  `json:"userId"`
+	ParentID *int64 `json:"parentId,omitempty"`
+	Content  string `json:"content"`
+}
+
+// GetComments は投稿のコメントツリーを取得します（フレームワーク非依存）
+func (h *CommentHandlerV2) GetComments(ctx HTTPContext, postID int64, page, pageSize int) error {
+	reqCtx := ctx.Context()
+
+	comments, total, err := h.usecase.GetCommentTree(reqCtx, postID, page, pageSize)
+	if err != nil {
+		return writeError(ctx, err, "Failed to retrieve comments")
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"comments": comments,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// PostComment はコメント・返信を投稿します（フレームワーク非依存）
+func (h *CommentHandlerV2) PostComment(ctx HTTPContext, postID int64) error {
+	var req postCommentRequest
+	if err := ctx.Bind(&req); err != nil {
+		return writeError(ctx, domain.ErrInvalidCommentInput, "")
+	}
+
+	reqCtx := ctx.Context()
+
+	comment, err := h.usecase.PostComment(reqCtx, postID, req.UserID, req.ParentID, req.Content)
+	if err != nil {
+		return writeError(ctx, err, "Failed to post comment")
+	}
+
+	return ctx.JSON(http.StatusCreated, comment)
+}
+
+// GetModerationQueue はモデレーション対象のコメント一覧を取得します（フレームワーク非依存）
+func (h *CommentHandlerV2) GetModerationQueue(ctx HTTPContext, status string, page, pageSize int) error {
+	reqCtx := ctx.Context()
+
+	comments, err := h.usecase.GetModerationQueue(reqCtx, status, page, pageSize)
+	if err != nil {
+		return writeError(ctx, err, "Failed to retrieve comments")
+	}
+	if comments == nil {
+		comments = []domain.CommentWithAuthor{}
+	}
+
+	return ctx.JSON(http.StatusOK, comments)
+}
+
+// ApproveComment はコメントを承認します（フレームワーク非依存）
+func (h *CommentHandlerV2) ApproveComment(ctx HTTPContext, id int64) error {
+	comment, err := h.usecase.ApproveComment(ctx.Context(), id)
+	if err != nil {
+		return writeError(ctx, err, "Failed to approve comment")
+	}
+
+	return ctx.JSON(http.StatusOK, comment)
+}
+
+// RejectComment はコメントを却下します（フレームワーク非依存）
+func (h *CommentHandlerV2) RejectComment(ctx HTTPContext, id int64) error {
+	comment, err := h.usecase.RejectComment(ctx.Context(), id)
+	if err != nil {
+		return writeError(ctx, err, "Failed to reject comment")
+	}
+
+	return ctx.JSON(http.StatusOK, comment)
+}
+
+// MarkCommentAsSpam はコメントをスパムとしてマークします（フレームワーク非依存）
+func (h *CommentHandlerV2) MarkCommentAsSpam(ctx HTTPContext, id int64) error {
+	comment, err := h.usecase.MarkCommentAsSpam(ctx.Context(), id)
+	if err != nil {
+		return writeError(ctx, err, "Failed to mark comment as spam")
+	}
+
+	return ctx.JSON(http.StatusOK, comment)
+}
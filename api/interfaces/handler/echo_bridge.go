@@ -2,14 +2,105 @@ package handler
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rssh-jp/test-api/api/domain"
 	"github.com/rssh-jp/test-api/api/gen"
+	"github.com/rssh-jp/test-api/api/usecase"
 )
 
+// ============================================================================
+// Echo Middleware
+// ============================================================================
+
+// headerAPIKey はマシンクライアントがAPIキーを送るヘッダー
+const headerAPIKey = "X-API-Key"
+
+// AuthMiddleware は Authorization: Bearer のアクセストークン、または X-API-Key のAPIキーを検証し、
+// 認証済みユーザーをリクエストコンテキストに設定します。
+// どちらもないリクエストは匿名としてそのまま通し、不正なトークン・キーは401を返します。
+// APIキーの場合は、参照系メソッドにreadスコープ、更新系メソッドにwriteスコープを要求します（不足時は403）。
+// 認証必須のルートには RequireAuth を併用します
+func AuthMiddleware(authUsecase usecase.AuthUsecase, apiKeyUsecase usecase.APIKeyUsecase) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			header := req.Header.Get(echo.HeaderAuthorization)
+			apiKey := req.Header.Get(headerAPIKey)
+
+			var user *domain.AuthUser
+			var err error
+			switch {
+			case header != "" && apiKey != "":
+				return writeError(newEchoHTTPContext(c), fmt.Errorf("%w: send either a bearer token or an api key", domain.ErrInvalidAuthInput), "")
+			case header != "":
+				token, ok := strings.CutPrefix(header, "Bearer ")
+				if !ok {
+					return writeError(newEchoHTTPContext(c), domain.ErrInvalidToken, "")
+				}
+				user, err = authUsecase.Authenticate(req.Context(), strings.TrimSpace(token))
+			case apiKey != "":
+				user, err = apiKeyUsecase.Authenticate(req.Context(), apiKey)
+				if err == nil {
+					if scope := requiredAPIKeyScope(req.Method); !user.HasScope(scope) {
+						err = fmt.Errorf("%w: api key requires the %s scope", domain.ErrForbidden, scope)
+					}
+				}
+			default:
+				return next(c)
+			}
+			if err != nil {
+				return writeError(newEchoHTTPContext(c), err, "Failed to authenticate")
+			}
+
+			c.SetRequest(req.WithContext(domain.ContextWithAuthUser(req.Context(), user)))
+			return next(c)
+		}
+	}
+}
+
+// requiredAPIKeyScope はHTTPメソッドに必要なAPIキーのスコープを返します
+func requiredAPIKeyScope(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return domain.APIKeyScopeRead
+	}
+	return domain.APIKeyScopeWrite
+}
+
+// RequireAuth は認証済みユーザーがいないリクエストを401で拒否します
+func RequireAuth() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if _, ok := domain.AuthUserFromContext(c.Request().Context()); !ok {
+				return writeError(newEchoHTTPContext(c), domain.ErrInvalidToken, "")
+			}
+			return next(c)
+		}
+	}
+}
+
+// CacheControl は成功したレスポンスにCache-Controlヘッダーを設定します（静的ファイル配信用）。
+// 404などのエラーはキャッシュさせません
+func CacheControl(value string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			res := c.Response()
+			res.Before(func() {
+				if res.Status < http.StatusBadRequest {
+					res.Header().Set(echo.HeaderCacheControl, value)
+				}
+			})
+			return next(c)
+		}
+	}
+}
+
 // ============================================================================
 // Echo HTTPContext Adapter (共通実装)
 // ============================================================================
@@ -72,6 +163,12 @@ func parseNoCache(c echo.Context) bool {
 	return noCache == "true" || noCache == "1"
 }
 
+// setVisitor は閲覧数の重複判定に使う訪問者（クライアントIP。NewIPExtractorの設定に従う）をリクエストのcontextに設定します
+func setVisitor(c echo.Context) {
+	req := c.Request()
+	c.SetRequest(req.WithContext(domain.ContextWithVisitor(req.Context(), c.RealIP())))
+}
+
 // ============================================================================
 // UserHandlerBridge (OpenAPI生成インターフェース実装)
 // ============================================================================
@@ -206,6 +303,7 @@ func (b *PostHandlerBridge) GetPosts(c echo.Context) error {
 
 // GetPostByID handles GET /posts/:id (Echo → Framework-independent)
 func (b *PostHandlerBridge) GetPostByID(c echo.Context) error {
+	setVisitor(c)
 	httpCtx := newEchoHTTPContext(c)
 
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -222,6 +320,7 @@ func (b *PostHandlerBridge) GetPostByID(c echo.Context) error {
 
 // GetPostBySlug handles GET /posts/slug/:slug (Echo → Framework-independent)
 func (b *PostHandlerBridge) GetPostBySlug(c echo.Context) error {
+	setVisitor(c)
 	httpCtx := newEchoHTTPContext(c)
 
 	slug := c.Param("slug")
@@ -285,3 +384,1026 @@ func (b *PostHandlerBridge) GetFeaturedPosts(c echo.Context) error {
 
 	return b.handler.GetFeaturedPosts(httpCtx, limit, noCache)
 }
+
+// SearchPosts handles GET /posts/search (Echo → Framework-independent)
+func (b *PostHandlerBridge) SearchPosts(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	query := c.QueryParam("q")
+	mode := c.QueryParam("mode")
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	pageSize, _ := strconv.Atoi(c.QueryParam("pageSize"))
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	noCache := parseNoCache(c)
+
+	return b.handler.SearchPosts(httpCtx, query, mode, page, pageSize, noCache)
+}
+
+// CreatePost handles POST /posts (Echo → Framework-independent)
+func (b *PostHandlerBridge) CreatePost(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+	return b.handler.CreatePost(httpCtx)
+}
+
+// UpdatePost handles PUT /posts/:id (Echo → Framework-independent)
+func (b *PostHandlerBridge) UpdatePost(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid post ID",
+		})
+	}
+
+	return b.handler.UpdatePost(httpCtx, id)
+}
+
+// PublishPost handles PATCH /posts/:id/publish (Echo → Framework-independent)
+func (b *PostHandlerBridge) PublishPost(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid post ID",
+		})
+	}
+
+	return b.handler.PublishPost(httpCtx, id)
+}
+
+// ArchivePost handles PATCH /posts/:id/archive (Echo → Framework-independent)
+func (b *PostHandlerBridge) ArchivePost(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid post ID",
+		})
+	}
+
+	return b.handler.ArchivePost(httpCtx, id)
+}
+
+// DeletePost handles DELETE /posts/:id (Echo → Framework-independent)
+func (b *PostHandlerBridge) DeletePost(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid post ID",
+		})
+	}
+
+	return b.handler.DeletePost(httpCtx, id)
+}
+
+// ============================================================================
+// CommentHandlerBridge
+// ============================================================================
+
+// CommentHandlerBridge はEchoとフレームワーク非依存CommentHandlerを繋ぐブリッジ
+type CommentHandlerBridge struct {
+	handler *CommentHandlerV2
+}
+
+// NewCommentHandlerBridge creates a new bridge for comment handler
+func NewCommentHandlerBridge(handler *CommentHandlerV2) *CommentHandlerBridge {
+	return &CommentHandlerBridge{
+		handler: handler,
+	}
+}
+
+// GetComments handles GET /posts/:id/comments (Echo → Framework-independent)
+func (b *CommentHandlerBridge) GetComments(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid post ID",
+		})
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	pageSize, _ := strconv.Atoi(c.QueryParam("pageSize"))
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	return b.handler.GetComments(httpCtx, postID, page, pageSize)
+}
+
+// PostComment handles POST /posts/:id/comments (Echo → Framework-independent)
+func (b *CommentHandlerBridge) PostComment(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid post ID",
+		})
+	}
+
+	return b.handler.PostComment(httpCtx, postID)
+}
+
+// GetModerationQueue handles GET /comments/moderation (Echo → Framework-independent)
+func (b *CommentHandlerBridge) GetModerationQueue(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	status := c.QueryParam("status")
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	pageSize, _ := strconv.Atoi(c.QueryParam("pageSize"))
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	return b.handler.GetModerationQueue(httpCtx, status, page, pageSize)
+}
+
+// ApproveComment handles PATCH /comments/:id/approve (Echo → Framework-independent)
+func (b *CommentHandlerBridge) ApproveComment(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid comment ID",
+		})
+	}
+
+	return b.handler.ApproveComment(httpCtx, id)
+}
+
+// RejectComment handles PATCH /comments/:id/reject (Echo → Framework-independent)
+func (b *CommentHandlerBridge) RejectComment(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid comment ID",
+		})
+	}
+
+	return b.handler.RejectComment(httpCtx, id)
+}
+
+// MarkCommentAsSpam handles PATCH /comments/:id/spam (Echo → Framework-independent)
+func (b *CommentHandlerBridge) MarkCommentAsSpam(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid comment ID",
+		})
+	}
+
+	return b.handler.MarkCommentAsSpam(httpCtx, id)
+}
+
+// ============================================================================
+// FollowHandlerBridge
+// ============================================================================
+
+// FollowHandlerBridge はEchoとフレームワーク非依存FollowHandlerを繋ぐブリッジ
+type FollowHandlerBridge struct {
+	handler *FollowHandlerV2
+}
+
+// NewFollowHandlerBridge creates a new bridge for follow handler
+func NewFollowHandlerBridge(handler *FollowHandlerV2) *FollowHandlerBridge {
+	return &FollowHandlerBridge{
+		handler: handler,
+	}
+}
+
+// Follow handles POST /users/:id/following (Echo → Framework-independent)
+func (b *FollowHandlerBridge) Follow(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.Follow(httpCtx, id)
+}
+
+// Unfollow handles DELETE /users/:id/following/:targetId (Echo → Framework-independent)
+func (b *FollowHandlerBridge) Unfollow(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	id, targetID, err := parseFollowPair(c)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.Unfollow(httpCtx, id, targetID)
+}
+
+// IsFollowing handles GET /users/:id/following/:targetId (Echo → Framework-independent)
+func (b *FollowHandlerBridge) IsFollowing(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	id, targetID, err := parseFollowPair(c)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.IsFollowing(httpCtx, id, targetID)
+}
+
+// GetFollowers handles GET /users/:id/followers (Echo → Framework-independent)
+func (b *FollowHandlerBridge) GetFollowers(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	return b.handler.GetFollowers(httpCtx, id, c.QueryParam("cursor"), limit)
+}
+
+// GetFollowing handles GET /users/:id/following (Echo → Framework-independent)
+func (b *FollowHandlerBridge) GetFollowing(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	return b.handler.GetFollowing(httpCtx, id, c.QueryParam("cursor"), limit)
+}
+
+// parseFollowPair は :id と :targetId をパースします
+func parseFollowPair(c echo.Context) (int64, int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	targetID, err := strconv.ParseInt(c.Param("targetId"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return id, targetID, nil
+}
+
+// ============================================================================
+// FeedHandlerBridge
+// ============================================================================
+
+// FeedHandlerBridge はEchoとフレームワーク非依存FeedHandlerを繋ぐブリッジ
+type FeedHandlerBridge struct {
+	handler *FeedHandlerV2
+}
+
+// NewFeedHandlerBridge creates a new bridge for feed handler
+func NewFeedHandlerBridge(handler *FeedHandlerV2) *FeedHandlerBridge {
+	return &FeedHandlerBridge{
+		handler: handler,
+	}
+}
+
+// GetFeed handles GET /users/:id/feed (Echo → Framework-independent)
+func (b *FeedHandlerBridge) GetFeed(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	pageSize, _ := strconv.Atoi(c.QueryParam("pageSize"))
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	return b.handler.GetFeed(httpCtx, id, page, pageSize)
+}
+
+// ============================================================================
+// LikeHandlerBridge
+// ============================================================================
+
+// LikeHandlerBridge はEchoとフレームワーク非依存LikeHandlerを繋ぐブリッジ
+type LikeHandlerBridge struct {
+	handler *LikeHandlerV2
+}
+
+// NewLikeHandlerBridge creates a new bridge for like handler
+func NewLikeHandlerBridge(handler *LikeHandlerV2) *LikeHandlerBridge {
+	return &LikeHandlerBridge{
+		handler: handler,
+	}
+}
+
+// LikePost handles PUT /posts/:id/likes/:userId (Echo → Framework-independent)
+func (b *LikeHandlerBridge) LikePost(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	postID, userID, err := parseLikeTarget(c)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid post or user ID",
+		})
+	}
+
+	return b.handler.LikePost(httpCtx, postID, userID)
+}
+
+// UnlikePost handles DELETE /posts/:id/likes/:userId (Echo → Framework-independent)
+func (b *LikeHandlerBridge) UnlikePost(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	postID, userID, err := parseLikeTarget(c)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid post or user ID",
+		})
+	}
+
+	return b.handler.UnlikePost(httpCtx, postID, userID)
+}
+
+// GetPostLikers handles GET /posts/:id/likes (Echo → Framework-independent)
+func (b *LikeHandlerBridge) GetPostLikers(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	postID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid post ID",
+		})
+	}
+
+	page, pageSize := parseLikePage(c)
+
+	return b.handler.GetPostLikers(httpCtx, postID, page, pageSize)
+}
+
+// LikeComment handles PUT /comments/:id/likes/:userId (Echo → Framework-independent)
+func (b *LikeHandlerBridge) LikeComment(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	commentID, userID, err := parseLikeTarget(c)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid comment or user ID",
+		})
+	}
+
+	return b.handler.LikeComment(httpCtx, commentID, userID)
+}
+
+// UnlikeComment handles DELETE /comments/:id/likes/:userId (Echo → Framework-independent)
+func (b *LikeHandlerBridge) UnlikeComment(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	commentID, userID, err := parseLikeTarget(c)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid comment or user ID",
+		})
+	}
+
+	return b.handler.UnlikeComment(httpCtx, commentID, userID)
+}
+
+// GetCommentLikers handles GET /comments/:id/likes (Echo → Framework-independent)
+func (b *LikeHandlerBridge) GetCommentLikers(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	commentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid comment ID",
+		})
+	}
+
+	page, pageSize := parseLikePage(c)
+
+	return b.handler.GetCommentLikers(httpCtx, commentID, page, pageSize)
+}
+
+// parseLikeTarget は :id と :userId をパースします
+func parseLikeTarget(c echo.Context) (int64, int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	userID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return id, userID, nil
+}
+
+func parseLikePage(c echo.Context) (int, int) {
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	pageSize, _ := strconv.Atoi(c.QueryParam("pageSize"))
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	return page, pageSize
+}
+
+// ============================================================================
+// NotificationHandlerBridge
+// ============================================================================
+
+// NotificationHandlerBridge はEchoとフレームワーク非依存NotificationHandlerを繋ぐブリッジ
+type NotificationHandlerBridge struct {
+	handler *NotificationHandlerV2
+}
+
+// NewNotificationHandlerBridge creates a new bridge for notification handler
+func NewNotificationHandlerBridge(handler *NotificationHandlerV2) *NotificationHandlerBridge {
+	return &NotificationHandlerBridge{
+		handler: handler,
+	}
+}
+
+// GetNotifications handles GET /users/:id/notifications (Echo → Framework-independent)
+func (b *NotificationHandlerBridge) GetNotifications(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	pageSize, _ := strconv.Atoi(c.QueryParam("pageSize"))
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	return b.handler.GetNotifications(httpCtx, userID, c.QueryParam("filter"), page, pageSize)
+}
+
+// MarkAsRead handles PATCH /users/:id/notifications/:notificationId/read (Echo → Framework-independent)
+func (b *NotificationHandlerBridge) MarkAsRead(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	id, err := strconv.ParseInt(c.Param("notificationId"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid notification ID",
+		})
+	}
+
+	return b.handler.MarkAsRead(httpCtx, userID, id)
+}
+
+// MarkAllAsRead handles PATCH /users/:id/notifications/read (Echo → Framework-independent)
+func (b *NotificationHandlerBridge) MarkAllAsRead(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.MarkAllAsRead(httpCtx, userID)
+}
+
+// StreamNotifications handles GET /users/:id/notifications/stream (Echo → Framework-independent)
+func (b *NotificationHandlerBridge) StreamNotifications(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	// EventSourceは再接続時にLast-Event-IDヘッダーを送る（ポリフィル用にクエリも受け付ける）
+	lastEventID := c.Request().Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("lastEventId")
+	}
+
+	var lastID int64
+	if lastEventID != "" {
+		lastID, err = strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil {
+			return c.JSON(400, map[string]string{
+				"error": "Invalid Last-Event-ID",
+			})
+		}
+	}
+
+	return b.handler.StreamNotifications(httpCtx, userID, lastID)
+}
+
+// ============================================================================
+// AuthHandlerBridge
+// ============================================================================
+
+// AuthHandlerBridge はEchoとフレームワーク非依存AuthHandlerを繋ぐブリッジ
+type AuthHandlerBridge struct {
+	handler *AuthHandlerV2
+}
+
+// NewAuthHandlerBridge creates a new bridge for auth handler
+func NewAuthHandlerBridge(handler *AuthHandlerV2) *AuthHandlerBridge {
+	return &AuthHandlerBridge{
+		handler: handler,
+	}
+}
+
+// Register handles POST /auth/register (Echo → Framework-independent)
+func (b *AuthHandlerBridge) Register(c echo.Context) error {
+	return b.handler.Register(newEchoHTTPContext(c))
+}
+
+// Login handles POST /auth/login (Echo → Framework-independent)
+func (b *AuthHandlerBridge) Login(c echo.Context) error {
+	return b.handler.Login(newEchoHTTPContext(c))
+}
+
+// Refresh handles POST /auth/refresh (Echo → Framework-independent)
+func (b *AuthHandlerBridge) Refresh(c echo.Context) error {
+	return b.handler.Refresh(newEchoHTTPContext(c))
+}
+
+// Logout handles POST /auth/logout (Echo → Framework-independent)
+func (b *AuthHandlerBridge) Logout(c echo.Context) error {
+	return b.handler.Logout(newEchoHTTPContext(c))
+}
+
+// LoginTwoFactor handles POST /auth/login/2fa (Echo → Framework-independent)
+func (b *AuthHandlerBridge) LoginTwoFactor(c echo.Context) error {
+	return b.handler.LoginTwoFactor(newEchoHTTPContext(c))
+}
+
+// Me handles GET /auth/me (Echo → Framework-independent)
+func (b *AuthHandlerBridge) Me(c echo.Context) error {
+	return b.handler.Me(newEchoHTTPContext(c))
+}
+
+// ============================================================================
+// RoleHandlerBridge
+// ============================================================================
+
+// RoleHandlerBridge はEchoとフレームワーク非依存RoleHandlerを繋ぐブリッジ
+type RoleHandlerBridge struct {
+	handler *RoleHandlerV2
+}
+
+// NewRoleHandlerBridge creates a new bridge for role handler
+func NewRoleHandlerBridge(handler *RoleHandlerV2) *RoleHandlerBridge {
+	return &RoleHandlerBridge{
+		handler: handler,
+	}
+}
+
+// GetUserRoles handles GET /users/:id/roles (Echo → Framework-independent)
+func (b *RoleHandlerBridge) GetUserRoles(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.GetUserRoles(httpCtx, userID)
+}
+
+// AssignRole handles PUT /users/:id/roles/:role (Echo → Framework-independent)
+func (b *RoleHandlerBridge) AssignRole(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.AssignRole(httpCtx, userID, c.Param("role"))
+}
+
+// RevokeRole handles DELETE /users/:id/roles/:role (Echo → Framework-independent)
+func (b *RoleHandlerBridge) RevokeRole(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.RevokeRole(httpCtx, userID, c.Param("role"))
+}
+
+// ============================================================================
+// EmailVerificationHandlerBridge
+// ============================================================================
+
+// EmailVerificationHandlerBridge はEchoとフレームワーク非依存EmailVerificationHandlerを繋ぐブリッジ
+type EmailVerificationHandlerBridge struct {
+	handler *EmailVerificationHandlerV2
+}
+
+// NewEmailVerificationHandlerBridge creates a new bridge for email verification handler
+func NewEmailVerificationHandlerBridge(handler *EmailVerificationHandlerV2) *EmailVerificationHandlerBridge {
+	return &EmailVerificationHandlerBridge{
+		handler: handler,
+	}
+}
+
+// SendVerification handles POST /auth/email/verification (Echo → Framework-independent)
+func (b *EmailVerificationHandlerBridge) SendVerification(c echo.Context) error {
+	return b.handler.SendVerification(newEchoHTTPContext(c))
+}
+
+// ConfirmEmail handles GET/POST /auth/email/verify (Echo → Framework-independent)
+func (b *EmailVerificationHandlerBridge) ConfirmEmail(c echo.Context) error {
+	return b.handler.ConfirmEmail(newEchoHTTPContext(c))
+}
+
+// ============================================================================
+// PasswordResetHandlerBridge
+// ============================================================================
+
+// PasswordResetHandlerBridge はEchoとフレームワーク非依存PasswordResetHandlerを繋ぐブリッジ
+type PasswordResetHandlerBridge struct {
+	handler *PasswordResetHandlerV2
+}
+
+// NewPasswordResetHandlerBridge creates a new bridge for password reset handler
+func NewPasswordResetHandlerBridge(handler *PasswordResetHandlerV2) *PasswordResetHandlerBridge {
+	return &PasswordResetHandlerBridge{
+		handler: handler,
+	}
+}
+
+// ForgotPassword handles POST /auth/password/forgot (Echo → Framework-independent)
+func (b *PasswordResetHandlerBridge) ForgotPassword(c echo.Context) error {
+	// IP単位のレート制限に使う（X-Forwarded-For は信頼するプロキシ経由の場合だけ使う。NewIPExtractorを参照）
+	return b.handler.ForgotPassword(newEchoHTTPContext(c), c.RealIP())
+}
+
+// ResetPassword handles POST /auth/password/reset (Echo → Framework-independent)
+func (b *PasswordResetHandlerBridge) ResetPassword(c echo.Context) error {
+	return b.handler.ResetPassword(newEchoHTTPContext(c))
+}
+
+// ============================================================================
+// TwoFactorHandlerBridge
+// ============================================================================
+
+// TwoFactorHandlerBridge はEchoとフレームワーク非依存TwoFactorHandlerを繋ぐブリッジ
+type TwoFactorHandlerBridge struct {
+	handler *TwoFactorHandlerV2
+}
+
+// NewTwoFactorHandlerBridge creates a new bridge for two-factor handler
+func NewTwoFactorHandlerBridge(handler *TwoFactorHandlerV2) *TwoFactorHandlerBridge {
+	return &TwoFactorHandlerBridge{
+		handler: handler,
+	}
+}
+
+// Enroll handles POST /auth/2fa/enroll (Echo → Framework-independent)
+func (b *TwoFactorHandlerBridge) Enroll(c echo.Context) error {
+	return b.handler.Enroll(newEchoHTTPContext(c))
+}
+
+// Enable handles POST /auth/2fa/enable (Echo → Framework-independent)
+func (b *TwoFactorHandlerBridge) Enable(c echo.Context) error {
+	return b.handler.Enable(newEchoHTTPContext(c))
+}
+
+// Disable handles POST /auth/2fa/disable (Echo → Framework-independent)
+func (b *TwoFactorHandlerBridge) Disable(c echo.Context) error {
+	return b.handler.Disable(newEchoHTTPContext(c))
+}
+
+// ============================================================================
+// APIKeyHandlerBridge
+// ============================================================================
+
+// APIKeyHandlerBridge はEchoとフレームワーク非依存APIKeyHandlerを繋ぐブリッジ
+type APIKeyHandlerBridge struct {
+	handler *APIKeyHandlerV2
+}
+
+// NewAPIKeyHandlerBridge creates a new bridge for API key handler
+func NewAPIKeyHandlerBridge(handler *APIKeyHandlerV2) *APIKeyHandlerBridge {
+	return &APIKeyHandlerBridge{
+		handler: handler,
+	}
+}
+
+// CreateAPIKey handles POST /users/:id/api-keys (Echo → Framework-independent)
+func (b *APIKeyHandlerBridge) CreateAPIKey(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.CreateAPIKey(httpCtx, userID)
+}
+
+// ListAPIKeys handles GET /users/:id/api-keys (Echo → Framework-independent)
+func (b *APIKeyHandlerBridge) ListAPIKeys(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.ListAPIKeys(httpCtx, userID)
+}
+
+// RevokeAPIKey handles DELETE /users/:id/api-keys/:keyId (Echo → Framework-independent)
+func (b *APIKeyHandlerBridge) RevokeAPIKey(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	keyID, err := strconv.ParseInt(c.Param("keyId"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid API key ID",
+		})
+	}
+
+	return b.handler.RevokeAPIKey(httpCtx, userID, keyID)
+}
+
+// ============================================================================
+// AccountStatusHandlerBridge
+// ============================================================================
+
+// AccountStatusHandlerBridge はEchoとフレームワーク非依存AccountStatusHandlerを繋ぐブリッジ
+type AccountStatusHandlerBridge struct {
+	handler *AccountStatusHandlerV2
+}
+
+// NewAccountStatusHandlerBridge creates a new bridge for account status handler
+func NewAccountStatusHandlerBridge(handler *AccountStatusHandlerV2) *AccountStatusHandlerBridge {
+	return &AccountStatusHandlerBridge{
+		handler: handler,
+	}
+}
+
+// DeactivateUser handles POST /users/:id/deactivate (Echo → Framework-independent)
+func (b *AccountStatusHandlerBridge) DeactivateUser(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.DeactivateUser(httpCtx, userID)
+}
+
+// SuspendUser handles POST /users/:id/suspend (Echo → Framework-independent)
+func (b *AccountStatusHandlerBridge) SuspendUser(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.SuspendUser(httpCtx, userID)
+}
+
+// ReactivateUser handles POST /users/:id/reactivate (Echo → Framework-independent)
+func (b *AccountStatusHandlerBridge) ReactivateUser(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.ReactivateUser(httpCtx, userID)
+}
+
+// RestoreUser handles POST /users/:id/restore (Echo → Framework-independent)
+func (b *AccountStatusHandlerBridge) RestoreUser(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.RestoreUser(httpCtx, userID)
+}
+
+// GetStatusHistory handles GET /users/:id/status-history (Echo → Framework-independent)
+func (b *AccountStatusHandlerBridge) GetStatusHistory(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.GetStatusHistory(httpCtx, userID)
+}
+
+// ============================================================================
+// UserProfileHandlerBridge
+// ============================================================================
+
+// UserProfileHandlerBridge はEchoとフレームワーク非依存UserProfileHandlerを繋ぐブリッジ
+type UserProfileHandlerBridge struct {
+	handler *UserProfileHandlerV2
+}
+
+// NewUserProfileHandlerBridge creates a new bridge for user profile handler
+func NewUserProfileHandlerBridge(handler *UserProfileHandlerV2) *UserProfileHandlerBridge {
+	return &UserProfileHandlerBridge{
+		handler: handler,
+	}
+}
+
+// GetProfile handles GET /users/:id/profile (Echo → Framework-independent)
+func (b *UserProfileHandlerBridge) GetProfile(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.GetProfile(httpCtx, userID)
+}
+
+// ReplaceProfile handles PUT /users/:id/profile (Echo → Framework-independent)
+func (b *UserProfileHandlerBridge) ReplaceProfile(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.ReplaceProfile(httpCtx, userID)
+}
+
+// UpdateProfile handles PATCH /users/:id/profile (Echo → Framework-independent)
+func (b *UserProfileHandlerBridge) UpdateProfile(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.UpdateProfile(httpCtx, userID)
+}
+
+// ============================================================================
+// AvatarHandlerBridge
+// ============================================================================
+
+// AvatarHandlerBridge はEchoとフレームワーク非依存AvatarHandlerを繋ぐブリッジ
+type AvatarHandlerBridge struct {
+	handler *AvatarHandlerV2
+}
+
+// NewAvatarHandlerBridge creates a new bridge for avatar handler
+func NewAvatarHandlerBridge(handler *AvatarHandlerV2) *AvatarHandlerBridge {
+	return &AvatarHandlerBridge{
+		handler: handler,
+	}
+}
+
+// UploadAvatar handles PUT /users/:id/avatar (Echo → Framework-independent)
+func (b *AvatarHandlerBridge) UploadAvatar(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.UploadAvatar(httpCtx, userID)
+}
+
+// DeleteAvatar handles DELETE /users/:id/avatar (Echo → Framework-independent)
+func (b *AvatarHandlerBridge) DeleteAvatar(c echo.Context) error {
+	httpCtx := newEchoHTTPContext(c)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(400, map[string]string{
+			"error": "Invalid user ID",
+		})
+	}
+
+	return b.handler.DeleteAvatar(httpCtx, userID)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/usecase"
+)
+
+// EmailVerificationHandlerV2 はフレームワーク非依存のメール認証ハンドラー
+type EmailVerificationHandlerV2 struct {
+	usecase usecase.EmailVerificationUsecase
+}
+
+// NewEmailVerificationHandlerV2 creates a new framework-independent email verification handler
+func NewEmailVerificationHandlerV2(usecase usecase.EmailVerificationUsecase) *EmailVerificationHandlerV2 {
+	return &EmailVerificationHandlerV2{usecase: usecase}
+}
+
+// verifyEmailRequest は POST /auth/email/verify のリクエストボディ
+type verifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// SendVerification は認証済みユーザーに確認メールを（再）送信します（フレームワーク非依存）
+func (h *EmailVerificationHandlerV2) SendVerification(ctx HTTPContext) error {
+	user, ok := domain.AuthUserFromContext(ctx.Context())
+	if !ok {
+		return writeError(ctx, domain.ErrUnauthenticated, "")
+	}
+
+	if err := h.usecase.SendVerification(ctx.Context(), user.ID); err != nil {
+		return writeError(ctx, err, "Failed to send verification email")
+	}
+
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+// ConfirmEmail はトークンを検証してメールアドレスを認証済みにします（フレームワーク非依存）。
+// メール内のリンク（GET ?token=）とAPIクライアント（POST {"token"}）の両方から呼ばれます
+func (h *EmailVerificationHandlerV2) ConfirmEmail(ctx HTTPContext) error {
+	token := ctx.QueryParam("token")
+	if token == "" && ctx.Request().Method == http.MethodPost {
+		var req verifyEmailRequest
+		if err := ctx.Bind(&req); err == nil {
+			token = req.Token
+		}
+	}
+
+	if err := h.usecase.ConfirmEmail(ctx.Context(), token); err != nil {
+		return writeError(ctx, err, "Failed to verify email")
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]bool{
+		"emailVerified": true,
+	})
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/gen"
+)
+
+// errorStatuses はドメインエラーの種別ごとのHTTPステータス
+var errorStatuses = map[domain.ErrorKind]int{
+	domain.KindValidation:      http.StatusBadRequest,
+	domain.KindUnauthenticated: http.StatusUnauthorized,
+	domain.KindForbidden:       http.StatusForbidden,
+	domain.KindNotFound:        http.StatusNotFound,
+	domain.KindConflict:        http.StatusConflict,
+	domain.KindRateLimited:     http.StatusTooManyRequests,
+}
+
+// writeError はエラーを種別に応じたHTTPステータスとエラーコードのgen.Errorに変換して返します。
+// 種別とコードはdomain.KindOf・domain.CodeOfで判定し、ドメインエラー以外は500 internal_errorとして内部の詳細の代わりにmessageを返します
+func writeError(ctx HTTPContext, err error, message string) error {
+	status, ok := errorStatuses[domain.KindOf(err)]
+	if !ok {
+		code := "internal_error"
+		return ctx.JSON(http.StatusInternalServerError, gen.Error{
+			Code:    &code,
+			Message: message,
+		})
+	}
+
+	// ドメインエラーの前にリポジトリ等の処理名（"failed to ..."）が付いている場合はドメインエラー自体のメッセージを返す
+	msg := err.Error()
+	if derrMsg := domain.MessageOf(err); !strings.HasPrefix(msg, derrMsg) {
+		msg = derrMsg
+	}
+	code := domain.CodeOf(err)
+	return ctx.JSON(status, gen.Error{
+		Code:    &code,
+		Message: msg,
+	})
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/gen"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := map[string]struct {
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		"domain error": {
+			err:     domain.ErrUserAlreadyExists,
+			status:  http.StatusConflict,
+			code:    "user_already_exists",
+			message: domain.ErrUserAlreadyExists.Error(),
+		},
+		"wrapped with details": {
+			err:     fmt.Errorf("%w: page must be 1 or greater", domain.ErrInvalidUserListQuery),
+			status:  http.StatusBadRequest,
+			code:    "invalid_query",
+			message: "invalid user list query: page must be 1 or greater",
+		},
+		// 処理名が前に付いたエラーは内部の詳細を返さずドメインエラー自体のメッセージにする
+		"wrapped by repository": {
+			err:     fmt.Errorf("failed to get user: %w", domain.NotFoundError("user")),
+			status:  http.StatusNotFound,
+			code:    "not_found",
+			message: "user not found",
+		},
+		"internal": {
+			err:     errors.New("dial tcp: connection refused"),
+			status:  http.StatusInternalServerError,
+			code:    "internal_error",
+			message: "Failed to retrieve users",
+		},
+	}
+	for name, tt := range tests {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/users", nil), rec)
+
+		if err := writeError(newEchoHTTPContext(c), tt.err, "Failed to retrieve users"); err != nil {
+			t.Fatalf("%s: expected no error, got %v", name, err)
+		}
+		var body gen.Error
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: failed to decode body: %v", name, err)
+		}
+		if rec.Code != tt.status || body.Code == nil || *body.Code != tt.code || body.Message != tt.message {
+			t.Errorf("%s: expected %d %s %q, got %d %v %q", name, tt.status, tt.code, tt.message, rec.Code, body.Code, body.Message)
+		}
+	}
+}
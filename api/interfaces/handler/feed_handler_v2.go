@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rssh-jp/test-api/api/usecase"
+)
+
+// FeedHandlerV2 はフレームワーク非依存のタイムラインハンドラー
+type FeedHandlerV2 struct {
+	usecase usecase.FeedUsecase
+}
+
+// NewFeedHandlerV2 creates a new framework-independent feed handler
+func NewFeedHandlerV2(usecase usecase.FeedUsecase) *FeedHandlerV2 {
+	return &FeedHandlerV2{usecase: usecase}
+}
+
+// GetFeed はフォロー中の著者の投稿タイムラインを取得します（フレームワーク非依存）
+func (h *FeedHandlerV2) GetFeed(ctx HTTPContext, userID int64, page, pageSize int) error {
+	posts, err := h.usecase.GetFeed(ctx.Context(), userID, page, pageSize)
+	if err != nil {
+		return writeError(ctx, err, "Failed to retrieve feed")
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"posts":    posts,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
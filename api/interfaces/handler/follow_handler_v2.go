@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/usecase"
+)
+
+// FollowHandlerV2 はフレームワーク非依存のフォローハンドラー
+type FollowHandlerV2 struct {
+	usecase usecase.FollowUsecase
+}
+
+// NewFollowHandlerV2 creates a new framework-independent follow handler
+func NewFollowHandlerV2(usecase usecase.FollowUsecase) *FollowHandlerV2 {
+	return &FollowHandlerV2{usecase: usecase}
+}
+
+// followRequest は POST /users/:id/following のリクエストボディ
+type followRequest struct {
+	UserID int64 `json:"userId"`
+}
+
+// Follow はユーザーをフォローします（フレームワーク非依存）
+func (h *FollowHandlerV2) Follow(ctx HTTPContext, followerID int64) error {
+	var req followRequest
+	if err := ctx.Bind(&req); err != nil || req.UserID <= 0 {
+		return writeError(ctx, fmt.Errorf("%w: userId is required", domain.ErrValidation), "")
+	}
+
+	if err := h.usecase.Follow(ctx.Context(), followerID, req.UserID); err != nil {
+		return writeError(ctx, err, "Failed to follow user")
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Unfollow はフォローを解除します（フレームワーク非依存）
+func (h *FollowHandlerV2) Unfollow(ctx HTTPContext, followerID, followingID int64) error {
+	if err := h.usecase.Unfollow(ctx.Context(), followerID, followingID); err != nil {
+		return writeError(ctx, err, "Failed to unfollow user")
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// IsFollowing はフォローしているかを返します（フレームワーク非依存）
+func (h *FollowHandlerV2) IsFollowing(ctx HTTPContext, followerID, followingID int64) error {
+	following, err := h.usecase.IsFollowing(ctx.Context(), followerID, followingID)
+	if err != nil {
+		return writeError(ctx, err, "Failed to check follow status")
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]bool{
+		"following": following,
+	})
+}
+
+// GetFollowers はフォロワー一覧を取得します（フレームワーク非依存）
+func (h *FollowHandlerV2) GetFollowers(ctx HTTPContext, userID int64, cursor string, limit int) error {
+	users, next, err := h.usecase.GetFollowers(ctx.Context(), userID, cursor, limit)
+	if err != nil {
+		return writeError(ctx, err, "Failed to retrieve followers")
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"users":      users,
+		"nextCursor": next,
+	})
+}
+
+// GetFollowing はフォロー中ユーザー一覧を取得します（フレームワーク非依存）
+func (h *FollowHandlerV2) GetFollowing(ctx HTTPContext, userID int64, cursor string, limit int) error {
+	users, next, err := h.usecase.GetFollowing(ctx.Context(), userID, cursor, limit)
+	if err != nil {
+		return writeError(ctx, err, "Failed to retrieve following users")
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"users":      users,
+		"nextCursor": next,
+	})
+}
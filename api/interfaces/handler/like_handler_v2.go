@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rssh-jp/test-api/api/usecase"
+)
+
+// LikeHandlerV2 はフレームワーク非依存のいいねハンドラー
+type LikeHandlerV2 struct {
+	usecase usecase.LikeUsecase
+}
+
+// NewLikeHandlerV2 creates a new framework-independent like handler
+func NewLikeHandlerV2(usecase usecase.LikeUsecase) *LikeHandlerV2 {
+	return &LikeHandlerV2{usecase: usecase}
+}
+
+// LikePost は投稿にいいねします（フレームワーク非依存）
+func (h *LikeHandlerV2) LikePost(ctx HTTPContext, postID, userID int64) error {
+	status, err := h.usecase.LikePost(ctx.Context(), postID, userID)
+	if err != nil {
+		return writeError(ctx, err, "Failed to like post")
+	}
+
+	return ctx.JSON(http.StatusOK, status)
+}
+
+// UnlikePost は投稿のいいねを取り消します（フレームワーク非依存）
+func (h *LikeHandlerV2) UnlikePost(ctx HTTPContext, postID, userID int64) error {
+	status, err := h.usecase.UnlikePost(ctx.Context(), postID, userID)
+	if err != nil {
+		return writeError(ctx, err, "Failed to unlike post")
+	}
+
+	return ctx.JSON(http.StatusOK, status)
+}
+
+// GetPostLikers は投稿にいいねしたユーザー一覧を取得します（フレームワーク非依存）
+func (h *LikeHandlerV2) GetPostLikers(ctx HTTPContext, postID int64, page, pageSize int) error {
+	likers, err := h.usecase.GetPostLikers(ctx.Context(), postID, page, pageSize)
+	if err != nil {
+		return writeError(ctx, err, "Failed to retrieve likes")
+	}
+
+	return ctx.JSON(http.StatusOK, likers)
+}
+
+// LikeComment はコメントにいいねします（フレームワーク非依存）
+func (h *LikeHandlerV2) LikeComment(ctx HTTPContext, commentID, userID int64) error {
+	status, err := h.usecase.LikeComment(ctx.Context(), commentID, userID)
+	if err != nil {
+		return writeError(ctx, err, "Failed to like comment")
+	}
+
+	return ctx.JSON(http.StatusOK, status)
+}
+
+// UnlikeComment はコメントのいいねを取り消します（フレームワーク非依存）
+func (h *LikeHandlerV2) UnlikeComment(ctx HTTPContext, commentID, userID int64) error {
+	status, err := h.usecase.UnlikeComment(ctx.Context(), commentID, userID)
+	if err != nil {
+		return writeError(ctx, err, "Failed to unlike comment")
+	}
+
+	return ctx.JSON(http.StatusOK, status)
+}
+
+// GetCommentLikers はコメントにいいねしたユーザー一覧を取得します（フレームワーク非依存）
+func (h *LikeHandlerV2) GetCommentLikers(ctx HTTPContext, commentID int64, page, pageSize int) error {
+	likers, err := h.usecase.GetCommentLikers(ctx.Context(), commentID, page, pageSize)
+	if err != nil {
+		return writeError(ctx, err, "Failed to retrieve likes")
+	}
+
+	return ctx.JSON(http.StatusOK, likers)
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/usecase"
+)
+
+// NotificationHandlerV2 はフレームワーク非依存の通知ハンドラー
+type NotificationHandlerV2 struct {
+	usecase usecase.NotificationUsecase
+}
+
+// NewNotificationHandlerV2 creates a new framework-independent notification handler
+func NewNotificationHandlerV2(usecase usecase.NotificationUsecase) *NotificationHandlerV2 {
+	return &NotificationHandlerV2{usecase: usecase}
+}
+
+// GetNotifications は通知一覧を取得します（フレームワーク非依存）
+func (h *NotificationHandlerV2) GetNotifications(ctx HTTPContext, userID int64, filter string, page, pageSize int) error {
+	notifications, total, err := h.usecase.GetNotifications(ctx.Context(), userID, filter, page, pageSize)
+	if err != nil {
+		return writeError(ctx, err, "Failed to retrieve notifications")
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"notifications": notifications,
+		"total":         total,
+		"page":          page,
+		"pageSize":      pageSize,
+	})
+}
+
+// MarkAsRead は通知を既読にします（フレームワーク非依存）
+func (h *NotificationHandlerV2) MarkAsRead(ctx HTTPContext, userID, id int64) error {
+	notification, err := h.usecase.MarkAsRead(ctx.Context(), userID, id)
+	if err != nil {
+		return writeError(ctx, err, "Failed to mark notification read")
+	}
+
+	return ctx.JSON(http.StatusOK, notification)
+}
+
+// MarkAllAsRead は全ての未読通知を既読にします（フレームワーク非依存）
+func (h *NotificationHandlerV2) MarkAllAsRead(ctx HTTPContext, userID int64) error {
+	updated, err := h.usecase.MarkAllAsRead(ctx.Context(), userID)
+	if err != nil {
+		return writeError(ctx, err, "Failed to mark notifications read")
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]int64{
+		"updated": updated,
+	})
+}
+
+// sseHeartbeatInterval はプロキシやロードバランサーに接続を切られないためのコメント送信間隔
+const sseHeartbeatInterval = 30 * time.Second
+
+// StreamNotifications は新着通知をServer-Sent Eventsで配信します（フレームワーク非依存）。
+// イベントIDは通知IDで、再接続時はLast-Event-ID以降の通知から再開します
+func (h *NotificationHandlerV2) StreamNotifications(ctx HTTPContext, userID, lastEventID int64) error {
+	reqCtx := ctx.Context()
+
+	flusher, ok := ctx.Response().(http.Flusher)
+	if !ok {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Streaming unsupported",
+		})
+	}
+
+	events, err := h.usecase.StreamNotifications(reqCtx, userID, lastEventID)
+	if err != nil {
+		return writeError(ctx, err, "Failed to subscribe notifications")
+	}
+
+	w := ctx.Response()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case notification, ok := <-events:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(notification)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "id: %d\nevent: notification\ndata: %s\n\n", notification.ID, data)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case <-reqCtx.Done():
+			return nil
+		}
+	}
+}
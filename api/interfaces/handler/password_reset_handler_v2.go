@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/usecase"
+)
+
+// PasswordResetHandlerV2 はフレームワーク非依存のパスワードリセットハンドラー
+type PasswordResetHandlerV2 struct {
+	usecase usecase.PasswordResetUsecase
+}
+
+// NewPasswordResetHandlerV2 creates a new framework-independent password reset handler
+func NewPasswordResetHandlerV2(usecase usecase.PasswordResetUsecase) *PasswordResetHandlerV2 {
+	return &PasswordResetHandlerV2{usecase: usecase}
+}
+
+// forgotPasswordRequest は POST /auth/password/forgot のリクエストボディ
+type forgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// resetPasswordRequest は POST /auth/password/reset のリクエストボディ
+type resetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// ForgotPassword はリセットリンクをメールで送信します（フレームワーク非依存）。
+// アカウントの有無を推測されないよう、登録されていないアドレスでも202を返します
+func (h *PasswordResetHandlerV2) ForgotPassword(ctx HTTPContext, clientIP string) error {
+	var req forgotPasswordRequest
+	if err := ctx.Bind(&req); err != nil {
+		return writeError(ctx, domain.ErrInvalidAuthInput, "")
+	}
+
+	if err := h.usecase.RequestReset(ctx.Context(), req.Email, clientIP); err != nil {
+		return writeError(ctx, err, "Failed to request password reset")
+	}
+
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+// ResetPassword はトークンを検証して新しいパスワードを設定します（フレームワーク非依存）
+func (h *PasswordResetHandlerV2) ResetPassword(ctx HTTPContext) error {
+	var req resetPasswordRequest
+	if err := ctx.Bind(&req); err != nil {
+		return writeError(ctx, domain.ErrInvalidAuthInput, "")
+	}
+
+	if err := h.usecase.ResetPassword(ctx.Context(), req.Token, req.Password); err != nil {
+		return writeError(ctx, err, "Failed to reset password")
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/rssh-jp/test-api/api/domain"
 	"github.com/rssh-jp/test-api/api/usecase"
 )
 
@@ -35,9 +37,7 @@ func (h *PostHandlerV2) GetPosts(ctx HTTPContext, page, pageSize int, noCache bo
 
 	posts, total, err := uc.GetPosts(reqCtx, page, pageSize)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to retrieve posts",
-		})
+		return writeError(ctx, err, "Failed to retrieve posts")
 	}
 
 	return ctx.JSON(http.StatusOK, map[string]interface{}{
@@ -55,9 +55,7 @@ func (h *PostHandlerV2) GetPostByID(ctx HTTPContext, id int64, noCache bool) err
 
 	post, err := uc.GetPostByID(reqCtx, id)
 	if err != nil {
-		return ctx.JSON(http.StatusNotFound, map[string]string{
-			"error": "Post not found",
-		})
+		return writeError(ctx, err, "Failed to retrieve post")
 	}
 
 	return ctx.JSON(http.StatusOK, post)
@@ -66,9 +64,7 @@ func (h *PostHandlerV2) GetPostByID(ctx HTTPContext, id int64, noCache bool) err
 // GetPostBySlug はスラッグで投稿を取得します（フレームワーク非依存）
 func (h *PostHandlerV2) GetPostBySlug(ctx HTTPContext, slug string, noCache bool) error {
 	if slug == "" {
-		return ctx.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Slug is required",
-		})
+		return writeError(ctx, fmt.Errorf("%w: slug is required", domain.ErrInvalidPostInput), "")
 	}
 
 	reqCtx := ctx.Context()
@@ -76,9 +72,7 @@ func (h *PostHandlerV2) GetPostBySlug(ctx HTTPContext, slug string, noCache bool
 
 	post, err := uc.GetPostBySlug(reqCtx, slug)
 	if err != nil {
-		return ctx.JSON(http.StatusNotFound, map[string]string{
-			"error": "Post not found",
-		})
+		return writeError(ctx, err, "Failed to retrieve post")
 	}
 
 	return ctx.JSON(http.StatusOK, post)
@@ -87,9 +81,7 @@ func (h *PostHandlerV2) GetPostBySlug(ctx HTTPContext, slug string, noCache bool
 // GetPostsByCategory はカテゴリー別に投稿を取得します（フレームワーク非依存）
 func (h *PostHandlerV2) GetPostsByCategory(ctx HTTPContext, slug string, page, pageSize int, noCache bool) error {
 	if slug == "" {
-		return ctx.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Category slug is required",
-		})
+		return writeError(ctx, fmt.Errorf("%w: category slug is required", domain.ErrInvalidPostInput), "")
 	}
 
 	reqCtx := ctx.Context()
@@ -97,9 +89,7 @@ func (h *PostHandlerV2) GetPostsByCategory(ctx HTTPContext, slug string, page, p
 
 	posts, err := uc.GetPostsByCategory(reqCtx, slug, page, pageSize)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to retrieve posts",
-		})
+		return writeError(ctx, err, "Failed to retrieve posts")
 	}
 
 	return ctx.JSON(http.StatusOK, posts)
@@ -108,9 +98,7 @@ func (h *PostHandlerV2) GetPostsByCategory(ctx HTTPContext, slug string, page, p
 // GetPostsByTag はタグ別に投稿を取得します（フレームワーク非依存）
 func (h *PostHandlerV2) GetPostsByTag(ctx HTTPContext, slug string, page, pageSize int, noCache bool) error {
 	if slug == "" {
-		return ctx.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Tag slug is required",
-		})
+		return writeError(ctx, fmt.Errorf("%w: tag slug is required", domain.ErrInvalidPostInput), "")
 	}
 
 	reqCtx := ctx.Context()
@@ -118,9 +106,7 @@ func (h *PostHandlerV2) GetPostsByTag(ctx HTTPContext, slug string, page, pageSi
 
 	posts, err := uc.GetPostsByTag(reqCtx, slug, page, pageSize)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to retrieve posts",
-		})
+		return writeError(ctx, err, "Failed to retrieve posts")
 	}
 
 	return ctx.JSON(http.StatusOK, posts)
@@ -133,10 +119,134 @@ func (h *PostHandlerV2) GetFeaturedPosts(ctx HTTPContext, limit int, noCache boo
 
 	posts, err := uc.GetFeaturedPosts(reqCtx, limit)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to retrieve featured posts",
-		})
+		return writeError(ctx, err, "Failed to retrieve featured posts")
 	}
 
 	return ctx.JSON(http.StatusOK, posts)
 }
+
+// SearchPosts は全文検索で投稿を取得します（フレームワーク非依存）
+func (h *PostHandlerV2) SearchPosts(ctx HTTPContext, query, mode string, page, pageSize int, noCache bool) error {
+	reqCtx := ctx.Context()
+	uc := h.selectUsecase(noCache)
+
+	results, err := uc.SearchPosts(reqCtx, query, mode, page, pageSize)
+	if err != nil {
+		return writeError(ctx, err, "Failed to search posts")
+	}
+	if results == nil {
+		results = []domain.PostSearchResult{}
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"posts":    results,
+		"query":    query,
+		"mode":     mode,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// createPostRequest は POST /posts のリクエストボディ
+type createPostRequest struct {
+	UserID     int64   `json:"userId"`
+	CategoryID *int64  `json:"categoryId,omitempty"`
+	Title      string  `json:"title"`
+	Slug       string  `json:"slug"`
+	Content    string  `json:"content"`
+	Excerpt    *string `json:"excerpt,omitempty"`
+	Status     string  `json:"status,omitempty"`
+	IsFeatured bool    `json:"isFeatured"`
+	TagIDs     []int64 `json:"tagIds,omitempty"`
+}
+
+// updatePostRequest は PUT /posts/:id のリクエストボディ（省略したフィールドは変更しない）
+type updatePostRequest struct {
+	CategoryID *int64  `json:"categoryId,omitempty"`
+	Title      *string `json:"title,omitempty"`
+	Slug       *string `json:"slug,omitempty"`
+	Content    *string `json:"content,omitempty"`
+	Excerpt    *string `json:"excerpt,omitempty"`
+	IsFeatured *bool   `json:"isFeatured,omitempty"`
+	TagIDs     []int64 `json:"tagIds,omitempty"`
+}
+
+// CreatePost は投稿を作成します（フレームワーク非依存）
+func (h *PostHandlerV2) CreatePost(ctx HTTPContext) error {
+	var req createPostRequest
+	if err := ctx.Bind(&req); err != nil {
+		return writeError(ctx, domain.ErrInvalidPostInput, "")
+	}
+
+	reqCtx := ctx.Context()
+	// 作成時は常にキャッシュ層を使用（書き込み操作）
+	post, err := h.postUsecase.CreatePost(reqCtx, usecase.CreatePostInput{
+		UserID:     req.UserID,
+		CategoryID: req.CategoryID,
+		Title:      req.Title,
+		Slug:       req.Slug,
+		Content:    req.Content,
+		Excerpt:    req.Excerpt,
+		Status:     req.Status,
+		IsFeatured: req.IsFeatured,
+		TagIDs:     req.TagIDs,
+	})
+	if err != nil {
+		return writeError(ctx, err, "Failed to create post")
+	}
+
+	return ctx.JSON(http.StatusCreated, post)
+}
+
+// UpdatePost は投稿を更新します（フレームワーク非依存）
+func (h *PostHandlerV2) UpdatePost(ctx HTTPContext, id int64) error {
+	var req updatePostRequest
+	if err := ctx.Bind(&req); err != nil {
+		return writeError(ctx, domain.ErrInvalidPostInput, "")
+	}
+
+	reqCtx := ctx.Context()
+	post, err := h.postUsecase.UpdatePost(reqCtx, id, usecase.UpdatePostInput{
+		CategoryID: req.CategoryID,
+		Title:      req.Title,
+		Slug:       req.Slug,
+		Content:    req.Content,
+		Excerpt:    req.Excerpt,
+		IsFeatured: req.IsFeatured,
+		TagIDs:     req.TagIDs,
+	})
+	if err != nil {
+		return writeError(ctx, err, "Failed to update post")
+	}
+
+	return ctx.JSON(http.StatusOK, post)
+}
+
+// PublishPost は投稿を公開します（フレームワーク非依存）
+func (h *PostHandlerV2) PublishPost(ctx HTTPContext, id int64) error {
+	post, err := h.postUsecase.PublishPost(ctx.Context(), id)
+	if err != nil {
+		return writeError(ctx, err, "Failed to publish post")
+	}
+
+	return ctx.JSON(http.StatusOK, post)
+}
+
+// ArchivePost は投稿をアーカイブします（フレームワーク非依存）
+func (h *PostHandlerV2) ArchivePost(ctx HTTPContext, id int64) error {
+	post, err := h.postUsecase.ArchivePost(ctx.Context(), id)
+	if err != nil {
+		return writeError(ctx, err, "Failed to archive post")
+	}
+
+	return ctx.JSON(http.StatusOK, post)
+}
+
+// DeletePost は投稿を削除（status = deleted）します（フレームワーク非依存）
+func (h *PostHandlerV2) DeletePost(ctx HTTPContext, id int64) error {
+	if err := h.postUsecase.DeletePost(ctx.Context(), id); err != nil {
+		return writeError(ctx, err, "Failed to delete post")
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rssh-jp/test-api/api/usecase"
+)
+
+// RoleHandlerV2 はフレームワーク非依存のロール管理ハンドラー
+type RoleHandlerV2 struct {
+	usecase usecase.RoleUsecase
+}
+
+// NewRoleHandlerV2 creates a new framework-independent role handler
+func NewRoleHandlerV2(usecase usecase.RoleUsecase) *RoleHandlerV2 {
+	return &RoleHandlerV2{usecase: usecase}
+}
+
+// GetUserRoles はユーザーのロールと権限を取得します（フレームワーク非依存）
+func (h *RoleHandlerV2) GetUserRoles(ctx HTTPContext, userID int64) error {
+	userRoles, err := h.usecase.GetUserRoles(ctx.Context(), userID)
+	if err != nil {
+		return writeError(ctx, err, "Failed to retrieve roles")
+	}
+
+	return ctx.JSON(http.StatusOK, userRoles)
+}
+
+// AssignRole はユーザーにロールを付与します（フレームワーク非依存）
+func (h *RoleHandlerV2) AssignRole(ctx HTTPContext, userID int64, role string) error {
+	userRoles, err := h.usecase.AssignRole(ctx.Context(), userID, role)
+	if err != nil {
+		return writeError(ctx, err, "Failed to assign role")
+	}
+
+	return ctx.JSON(http.StatusOK, userRoles)
+}
+
+// RevokeRole はユーザーからロールを剥奪します（フレームワーク非依存）
+func (h *RoleHandlerV2) RevokeRole(ctx HTTPContext, userID int64, role string) error {
+	userRoles, err := h.usecase.RevokeRole(ctx.Context(), userID, role)
+	if err != nil {
+		return writeError(ctx, err, "Failed to revoke role")
+	}
+
+	return ctx.JSON(http.StatusOK, userRoles)
+}
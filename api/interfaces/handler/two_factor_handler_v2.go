@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/usecase"
+)
+
+// TwoFactorHandlerV2 はフレームワーク非依存の2段階認証ハンドラー
+type TwoFactorHandlerV2 struct {
+	usecase usecase.TwoFactorUsecase
+}
+
+// NewTwoFactorHandlerV2 creates a new framework-independent two-factor handler
+func NewTwoFactorHandlerV2(usecase usecase.TwoFactorUsecase) *TwoFactorHandlerV2 {
+	return &TwoFactorHandlerV2{usecase: usecase}
+}
+
+// twoFactorCodeRequest は /auth/2fa/enable, /auth/2fa/disable のリクエストボディ
+type twoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// Enroll は認証済みユーザーに新しいTOTPシークレットとプロビジョニングURIを発行します（フレームワーク非依存）
+func (h *TwoFactorHandlerV2) Enroll(ctx HTTPContext) error {
+	user, ok := domain.AuthUserFromContext(ctx.Context())
+	if !ok {
+		return writeError(ctx, domain.ErrUnauthenticated, "")
+	}
+
+	enrollment, err := h.usecase.Enroll(ctx.Context(), user.ID)
+	if err != nil {
+		return writeError(ctx, err, "Failed to start two-factor enrollment")
+	}
+
+	return ctx.JSON(http.StatusOK, enrollment)
+}
+
+// Enable は認証アプリのコードを確認して2段階認証を有効化し、リカバリーコードを返します（フレームワーク非依存）
+func (h *TwoFactorHandlerV2) Enable(ctx HTTPContext) error {
+	user, ok := domain.AuthUserFromContext(ctx.Context())
+	if !ok {
+		return writeError(ctx, domain.ErrUnauthenticated, "")
+	}
+
+	var req twoFactorCodeRequest
+	if err := ctx.Bind(&req); err != nil {
+		return writeError(ctx, domain.ErrInvalidAuthInput, "")
+	}
+
+	codes, err := h.usecase.Enable(ctx.Context(), user.ID, req.Code)
+	if err != nil {
+		return writeError(ctx, err, "Failed to enable two-factor authentication")
+	}
+
+	return ctx.JSON(http.StatusOK, map[string][]string{
+		"recoveryCodes": codes,
+	})
+}
+
+// Disable はコードを確認して2段階認証を無効化します（フレームワーク非依存）
+func (h *TwoFactorHandlerV2) Disable(ctx HTTPContext) error {
+	user, ok := domain.AuthUserFromContext(ctx.Context())
+	if !ok {
+		return writeError(ctx, domain.ErrUnauthenticated, "")
+	}
+
+	var req twoFactorCodeRequest
+	if err := ctx.Bind(&req); err != nil {
+		return writeError(ctx, domain.ErrInvalidAuthInput, "")
+	}
+
+	if err := h.usecase.Disable(ctx.Context(), user.ID, req.Code); err != nil {
+		return writeError(ctx, err, "Failed to disable two-factor authentication")
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
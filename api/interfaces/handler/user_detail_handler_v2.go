@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/rssh-jp/test-api/api/domain"
 	"github.com/rssh-jp/test-api/api/usecase"
 )
 
@@ -23,14 +25,7 @@ func (h *UserDetailHandlerV2) GetUserDetailByID(ctx HTTPContext, id int64) error
 	// ユーザー詳細情報を取得
 	detail, err := h.usecase.GetUserDetailByID(reqCtx, id)
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			return ctx.JSON(http.StatusNotFound, map[string]string{
-				"error": "User not found",
-			})
-		}
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to fetch user details",
-		})
+		return writeError(ctx, err, "Failed to fetch user details")
 	}
 
 	return ctx.JSON(http.StatusOK, detail)
@@ -39,9 +34,7 @@ func (h *UserDetailHandlerV2) GetUserDetailByID(ctx HTTPContext, id int64) error
 // GetUserDetailByUsername は指定されたユーザー名のユーザーの全関連情報を取得します（フレームワーク非依存）
 func (h *UserDetailHandlerV2) GetUserDetailByUsername(ctx HTTPContext, username string) error {
 	if username == "" {
-		return ctx.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Username is required",
-		})
+		return writeError(ctx, fmt.Errorf("%w: username is required", domain.ErrInvalidUserInput), "")
 	}
 
 	reqCtx := ctx.Context()
@@ -49,14 +42,7 @@ func (h *UserDetailHandlerV2) GetUserDetailByUsername(ctx HTTPContext, username
 	// ユーザー詳細情報を取得
 	detail, err := h.usecase.GetUserDetailByUsername(reqCtx, username)
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			return ctx.JSON(http.StatusNotFound, map[string]string{
-				"error": "User not found",
-			})
-		}
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to fetch user details",
-		})
+		return writeError(ctx, err, "Failed to fetch user details")
 	}
 
 	return ctx.JSON(http.StatusOK, detail)
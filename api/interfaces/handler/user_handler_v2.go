@@ -1,11 +1,11 @@
 package handler
 
 import (
-	"database/sql"
-	"errors"
 	"net/http"
+	"time"
 
 	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/rssh-jp/test-api/api/domain"
 	"github.com/rssh-jp/test-api/api/gen"
 	"github.com/rssh-jp/test-api/api/usecase"
 )
@@ -26,7 +26,7 @@ func NewUserHandlerV2(userUsecase usecase.UserUsecase, directUserUsecase usecase
 	}
 }
 
-// GetUsers は全ユーザーを取得します（フレームワーク非依存）
+// GetUsers はユーザー一覧を絞り込み・並び替えてページ単位で取得します（フレームワーク非依存）
 func (h *UserHandlerV2) GetUsers(ctx HTTPContext, params gen.GetUsersParams) error {
 	reqCtx := ctx.Context()
 	
@@ -36,27 +36,82 @@ func (h *UserHandlerV2) GetUsers(ctx HTTPContext, params gen.GetUsersParams) err
 		uc = h.directUserUsecase
 	}
 
-	users, err := uc.GetAllUsers(reqCtx)
+	input := usecase.UserListInput{
+		EmailVerified: params.EmailVerified,
+		CreatedFrom:   params.CreatedFrom,
+		CreatedTo:     params.CreatedTo,
+	}
+	if params.Page != nil {
+		input.Page = *params.Page
+	}
+	if params.PageSize != nil {
+		input.PageSize = *params.PageSize
+	}
+	if params.Cursor != nil {
+		input.Cursor = *params.Cursor
+	}
+	if params.Status != nil {
+		input.Status = *params.Status
+	}
+	if params.UsernamePrefix != nil {
+		input.UsernamePrefix = *params.UsernamePrefix
+	}
+	if params.Sort != nil {
+		input.Sort = string(*params.Sort)
+	}
+	if params.Order != nil {
+		input.Order = string(*params.Order)
+	}
+
+	list, err := uc.ListUsers(reqCtx, input)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, gen.Error{
-			Message: "Failed to retrieve users",
-		})
+		return writeError(ctx, err, "Failed to retrieve users")
 	}
 
 	// Convert domain users to API users
-	apiUsers := make([]gen.User, len(users))
-	for i, user := range users {
-		apiUsers[i] = gen.User{
-			Id:        user.ID,
-			Name:      user.Name,
-			Email:     openapi_types.Email(user.Email),
-			Age:       user.Age,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-		}
+	apiUsers := make([]gen.User, len(list.Users))
+	for i := range list.Users {
+		apiUsers[i] = toAPIUser(&list.Users[i])
+	}
+
+	response := gen.UserList{
+		Users:    apiUsers,
+		PageSize: list.PageSize,
+		Total:    list.Total,
+	}
+	if list.Page > 0 {
+		response.Page = &list.Page
+	}
+	if list.NextCursor != "" {
+		response.NextCursor = &list.NextCursor
 	}
 
-	return ctx.JSON(http.StatusOK, apiUsers)
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// toAPIUser はドメインのユーザーをAPIのUserに変換します（ageは生年月日から算出）
+// 非公開項目がユースケースで消されている場合、email・lastLoginAt・birthDate・ageは返しません
+func toAPIUser(user *domain.User) gen.User {
+	apiUser := gen.User{
+		Id:            user.ID,
+		Username:      user.Username,
+		Status:        gen.UserStatus(user.Status),
+		EmailVerified: user.EmailVerified,
+		LastLoginAt:   user.LastLoginAt,
+		Age:           user.Age(time.Now()),
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+	if user.Email != "" {
+		email := openapi_types.Email(user.Email)
+		apiUser.Email = &email
+	}
+	if user.BirthDate != nil {
+		if birthDate, err := time.Parse("2006-01-02", *user.BirthDate); err == nil {
+			apiUser.BirthDate = &openapi_types.Date{Time: birthDate}
+		}
+	}
+	return apiUser
 }
 
 // GetUserById はIDでユーザーを取得します（フレームワーク非依存）
@@ -71,24 +126,10 @@ func (h *UserHandlerV2) GetUserById(ctx HTTPContext, id int64, params gen.GetUse
 
 	user, err := uc.GetUserByID(reqCtx, id)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return ctx.JSON(http.StatusNotFound, gen.Error{
-				Message: "User not found",
-			})
-		}
-		return ctx.JSON(http.StatusInternalServerError, gen.Error{
-			Message: "Failed to retrieve user",
-		})
+		return writeError(ctx, err, "Failed to retrieve user")
 	}
 
-	apiUser := gen.User{
-		Id:        user.ID,
-		Name:      user.Name,
-		Email:     openapi_types.Email(user.Email),
-		Age:       user.Age,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-	}
+	apiUser := toAPIUser(user)
 
 	return ctx.JSON(http.StatusOK, apiUser)
 }
@@ -97,31 +138,29 @@ func (h *UserHandlerV2) GetUserById(ctx HTTPContext, id int64, params gen.GetUse
 func (h *UserHandlerV2) CreateUser(ctx HTTPContext) error {
 	var req gen.CreateUserRequest
 	if err := ctx.Bind(&req); err != nil {
-		return ctx.JSON(http.StatusBadRequest, gen.Error{
-			Message: "Invalid request body",
-		})
+		return writeError(ctx, domain.ErrInvalidUserInput, "")
 	}
 
 	reqCtx := ctx.Context()
 	// 作成時は常にキャッシュ層を使用（書き込み操作）
 	uc := h.userUsecase
 
-	user, err := uc.CreateUser(reqCtx, req.Name, string(req.Email), req.Age)
-	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, gen.Error{
-			Message: "Failed to create user",
-		})
+	input := usecase.CreateUserInput{
+		Username:  req.Username,
+		Email:     string(req.Email),
+		BirthDate: req.BirthDate,
+	}
+	if req.EmailVerified != nil {
+		input.EmailVerified = *req.EmailVerified
 	}
 
-	apiUser := gen.User{
-		Id:        user.ID,
-		Name:      user.Name,
-		Email:     openapi_types.Email(user.Email),
-		Age:       user.Age,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+	user, err := uc.CreateUser(reqCtx, input)
+	if err != nil {
+		return writeError(ctx, err, "Failed to create user")
 	}
 
+	apiUser := toAPIUser(user)
+
 	return ctx.JSON(http.StatusCreated, apiUser)
 }
 
@@ -129,41 +168,29 @@ func (h *UserHandlerV2) CreateUser(ctx HTTPContext) error {
 func (h *UserHandlerV2) UpdateUser(ctx HTTPContext, id int64) error {
 	var req gen.UpdateUserRequest
 	if err := ctx.Bind(&req); err != nil {
-		return ctx.JSON(http.StatusBadRequest, gen.Error{
-			Message: "Invalid request body",
-		})
+		return writeError(ctx, domain.ErrInvalidUserInput, "")
 	}
 
-	var email *string
+	input := usecase.UpdateUserInput{
+		Username:      req.Username,
+		BirthDate:     req.BirthDate,
+		EmailVerified: req.EmailVerified,
+	}
 	if req.Email != nil {
-		emailStr := string(*req.Email)
-		email = &emailStr
+		email := string(*req.Email)
+		input.Email = &email
 	}
 
 	reqCtx := ctx.Context()
 	// 更新時は常にキャッシュ層を使用（書き込み操作）
 	uc := h.userUsecase
 
-	user, err := uc.UpdateUser(reqCtx, id, req.Name, email, req.Age)
+	user, err := uc.UpdateUser(reqCtx, id, input)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return ctx.JSON(http.StatusNotFound, gen.Error{
-				Message: "User not found",
-			})
-		}
-		return ctx.JSON(http.StatusInternalServerError, gen.Error{
-			Message: "Failed to update user",
-		})
+		return writeError(ctx, err, "Failed to update user")
 	}
 
-	apiUser := gen.User{
-		Id:        user.ID,
-		Name:      user.Name,
-		Email:     openapi_types.Email(user.Email),
-		Age:       user.Age,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-	}
+	apiUser := toAPIUser(user)
 
 	return ctx.JSON(http.StatusOK, apiUser)
 }
@@ -176,14 +203,7 @@ func (h *UserHandlerV2) DeleteUser(ctx HTTPContext, id int64) error {
 
 	err := uc.DeleteUser(reqCtx, id)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return ctx.JSON(http.StatusNotFound, gen.Error{
-				Message: "User not found",
-			})
-		}
-		return ctx.JSON(http.StatusInternalServerError, gen.Error{
-			Message: "Failed to delete user",
-		})
+		return writeError(ctx, err, "Failed to delete user")
 	}
 
 	return ctx.NoContent(http.StatusNoContent)
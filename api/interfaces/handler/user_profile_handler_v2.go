@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/usecase"
+)
+
+// UserProfileHandlerV2 はフレームワーク非依存のプロフィール編集ハンドラー
+type UserProfileHandlerV2 struct {
+	usecase usecase.UserProfileUsecase
+}
+
+// NewUserProfileHandlerV2 creates a new framework-independent user profile handler
+func NewUserProfileHandlerV2(usecase usecase.UserProfileUsecase) *UserProfileHandlerV2 {
+	return &UserProfileHandlerV2{usecase: usecase}
+}
+
+// GetProfile はプロフィールを取得します（フレームワーク非依存）
+func (h *UserProfileHandlerV2) GetProfile(ctx HTTPContext, userID int64) error {
+	profile, err := h.usecase.GetProfile(ctx.Context(), userID)
+	if err != nil {
+		return writeError(ctx, err, "Failed to retrieve profile")
+	}
+
+	return ctx.JSON(http.StatusOK, profile)
+}
+
+// ReplaceProfile はプロフィール全体を置き換えます（フレームワーク非依存）
+func (h *UserProfileHandlerV2) ReplaceProfile(ctx HTTPContext, userID int64) error {
+	var input usecase.UserProfileInput
+	if err := ctx.Bind(&input); err != nil {
+		return writeError(ctx, domain.ErrInvalidProfileInput, "")
+	}
+
+	profile, err := h.usecase.ReplaceProfile(ctx.Context(), userID, input)
+	if err != nil {
+		return writeError(ctx, err, "Failed to update profile")
+	}
+
+	return ctx.JSON(http.StatusOK, profile)
+}
+
+// UpdateProfile はリクエストに含まれる項目だけプロフィールを更新します（フレームワーク非依存）
+func (h *UserProfileHandlerV2) UpdateProfile(ctx HTTPContext, userID int64) error {
+	var input usecase.UserProfileInput
+	if err := ctx.Bind(&input); err != nil {
+		return writeError(ctx, domain.ErrInvalidProfileInput, "")
+	}
+
+	profile, err := h.usecase.UpdateProfile(ctx.Context(), userID, input)
+	if err != nil {
+		return writeError(ctx, err, "Failed to update profile")
+	}
+
+	return ctx.JSON(http.StatusOK, profile)
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+const (
+	maxStatusReasonLength = 255
+	statusHistoryLimit    = 50
+)
+
+// AccountStatusUsecase defines business logic for the account status lifecycle
+// (deactivate, suspend, reactivate, restore)
+type AccountStatusUsecase interface {
+	DeactivateUser(ctx context.Context, userID int64, reason string) (*domain.UserStatusChange, error)
+	SuspendUser(ctx context.Context, userID int64, reason string) (*domain.UserStatusChange, error)
+	ReactivateUser(ctx context.Context, userID int64, reason string) (*domain.UserStatusChange, error)
+	RestoreUser(ctx context.Context, userID int64, reason string) (*domain.UserStatusChange, error)
+	GetStatusHistory(ctx context.Context, userID int64) ([]domain.UserStatusChange, error)
+}
+
+type accountStatusUsecase struct {
+	statusRepo domain.UserStatusRepository
+	tokenStore domain.RefreshTokenStore // nilの場合はリフレッシュトークンを失効させない
+	policy     Policy
+}
+
+// NewAccountStatusUsecase creates a new account status usecase
+func NewAccountStatusUsecase(statusRepo domain.UserStatusRepository, tokenStore domain.RefreshTokenStore, policy Policy) AccountStatusUsecase {
+	return &accountStatusUsecase{
+		statusRepo: statusRepo,
+		tokenStore: tokenStore,
+		policy:     policy,
+	}
+}
+
+// DeactivateUser puts the account into inactive (the user themselves or users.manage)
+func (u *accountStatusUsecase) DeactivateUser(ctx context.Context, userID int64, reason string) (*domain.UserStatusChange, error) {
+	if err := u.policy.RequireOwnerOr(ctx, userID, domain.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+
+	return u.changeStatus(ctx, userID, domain.UserStatusInactive, reason, false)
+}
+
+// SuspendUser suspends the account (users.manage only, reason required).
+// Suspended users cannot log in and are hidden from listings together with their posts
+func (u *accountStatusUsecase) SuspendUser(ctx context.Context, userID int64, reason string) (*domain.UserStatusChange, error) {
+	if err := u.policy.RequirePermission(ctx, domain.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+	if viewerID, ok := domain.ViewerIDFromContext(ctx); ok && viewerID == userID {
+		return nil, fmt.Errorf("%w: cannot suspend your own account", domain.ErrInvalidUserStatusInput)
+	}
+
+	return u.changeStatus(ctx, userID, domain.UserStatusSuspended, reason, true)
+}
+
+// ReactivateUser returns an inactive or suspended account to active.
+// Inactive accounts may be reactivated by the user themselves; suspended accounts require users.manage and a reason
+func (u *accountStatusUsecase) ReactivateUser(ctx context.Context, userID int64, reason string) (*domain.UserStatusChange, error) {
+	if err := u.policy.RequireOwnerOr(ctx, userID, domain.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+
+	current, err := u.statusRepo.FindStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current == domain.UserStatusDeleted {
+		// 削除済みアカウントはRestoreUserで復元する
+		return nil, fmt.Errorf("%w: %s → %s", domain.ErrInvalidUserStatusTransition, current, domain.UserStatusActive)
+	}
+
+	suspended := current == domain.UserStatusSuspended
+	if suspended {
+		if err := u.policy.RequirePermission(ctx, domain.PermissionUsersManage); err != nil {
+			return nil, err
+		}
+	}
+
+	return u.changeStatus(ctx, userID, domain.UserStatusActive, reason, suspended)
+}
+
+// RestoreUser restores a soft-deleted account to active (users.manage only, reason required)
+func (u *accountStatusUsecase) RestoreUser(ctx context.Context, userID int64, reason string) (*domain.UserStatusChange, error) {
+	if err := u.policy.RequirePermission(ctx, domain.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+
+	current, err := u.statusRepo.FindStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current != domain.UserStatusDeleted {
+		return nil, fmt.Errorf("%w: only deleted accounts can be restored (current: %s)", domain.ErrInvalidUserStatusTransition, current)
+	}
+
+	return u.changeStatus(ctx, userID, domain.UserStatusActive, reason, true)
+}
+
+// GetStatusHistory retrieves the account's status changes, newest first (the user themselves or users.manage)
+func (u *accountStatusUsecase) GetStatusHistory(ctx context.Context, userID int64) ([]domain.UserStatusChange, error) {
+	if err := u.policy.RequireOwnerOr(ctx, userID, domain.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.statusRepo.FindStatus(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	changes, err := u.statusRepo.FindHistory(ctx, userID, statusHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	if changes == nil {
+		changes = []domain.UserStatusChange{}
+	}
+	return changes, nil
+}
+
+// changeStatus は遷移を検証して状態を変更し、操作者と理由を履歴に残します。
+// active以外への変更ではリフレッシュトークンを全て失効させます
+func (u *accountStatusUsecase) changeStatus(ctx context.Context, userID int64, to, reason string, reasonRequired bool) (*domain.UserStatusChange, error) {
+	reason = strings.TrimSpace(reason)
+	if reasonRequired && reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", domain.ErrInvalidUserStatusInput)
+	}
+	if utf8.RuneCountInString(reason) > maxStatusReasonLength {
+		return nil, fmt.Errorf("%w: reason must be at most %d characters", domain.ErrInvalidUserStatusInput, maxStatusReasonLength)
+	}
+
+	from, err := u.statusRepo.FindStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanTransitionUserStatus(from, to) {
+		return nil, fmt.Errorf("%w: %s → %s", domain.ErrInvalidUserStatusTransition, from, to)
+	}
+
+	change := &domain.UserStatusChange{
+		UserID:     userID,
+		FromStatus: from,
+		ToStatus:   to,
+	}
+	if reason != "" {
+		change.Reason = &reason
+	}
+	if viewerID, ok := domain.ViewerIDFromContext(ctx); ok {
+		change.ChangedBy = &viewerID
+	}
+
+	if err := u.statusRepo.ChangeStatus(ctx, change); err != nil {
+		return nil, err
+	}
+
+	if to != domain.UserStatusActive && u.tokenStore != nil {
+		if err := u.tokenStore.RevokeAll(ctx, userID); err != nil {
+			return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+	}
+
+	return change, nil
+}
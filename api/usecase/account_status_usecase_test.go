@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// Mock repository for testing
+type mockUserStatusRepository struct {
+	statuses map[int64]string
+	history  []domain.UserStatusChange
+}
+
+func newMockUserStatusRepository(statuses map[int64]string) *mockUserStatusRepository {
+	return &mockUserStatusRepository{statuses: statuses}
+}
+
+func (m *mockUserStatusRepository) FindStatus(ctx context.Context, userID int64) (string, error) {
+	status, ok := m.statuses[userID]
+	if !ok {
+		return "", domain.ErrNotFound
+	}
+	return status, nil
+}
+
+func (m *mockUserStatusRepository) ChangeStatus(ctx context.Context, change *domain.UserStatusChange) error {
+	if m.statuses[change.UserID] != change.FromStatus {
+		return domain.ErrInvalidUserStatusTransition
+	}
+	m.statuses[change.UserID] = change.ToStatus
+	change.ID = int64(len(m.history) + 1)
+	m.history = append(m.history, *change)
+	return nil
+}
+
+func (m *mockUserStatusRepository) FindHistory(ctx context.Context, userID int64, limit int) ([]domain.UserStatusChange, error) {
+	var changes []domain.UserStatusChange
+	for i := len(m.history) - 1; i >= 0 && len(changes) < limit; i-- {
+		if m.history[i].UserID == userID {
+			changes = append(changes, m.history[i])
+		}
+	}
+	return changes, nil
+}
+
+func TestSuspendReactivateRecordsReasonAndRevokesSessions(t *testing.T) {
+	repo := newMockUserStatusRepository(map[int64]string{2: domain.UserStatusActive})
+	tokenStore := newMockRefreshTokenStore()
+	tokenStore.active["2:session"] = true
+	usecase := NewAccountStatusUsecase(repo, tokenStore, allowAllPolicy{})
+	ctx := viewerContext(1)
+
+	if _, err := usecase.SuspendUser(ctx, 2, "  "); !errors.Is(err, domain.ErrInvalidUserStatusInput) {
+		t.Fatalf("Expected ErrInvalidUserStatusInput for empty reason, got %v", err)
+	}
+
+	change, err := usecase.SuspendUser(ctx, 2, "spam")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if change.ToStatus != domain.UserStatusSuspended || change.Reason == nil || *change.Reason != "spam" {
+		t.Errorf("Unexpected status change: %+v", change)
+	}
+	if change.ChangedBy == nil || *change.ChangedBy != 1 {
+		t.Errorf("Expected changedBy 1, got %v", change.ChangedBy)
+	}
+	if tokenStore.active["2:session"] {
+		t.Error("Expected refresh tokens to be revoked on suspension")
+	}
+
+	// suspended → suspended is not allowed
+	if _, err := usecase.SuspendUser(ctx, 2, "again"); !errors.Is(err, domain.ErrInvalidUserStatusTransition) {
+		t.Fatalf("Expected ErrInvalidUserStatusTransition, got %v", err)
+	}
+
+	if _, err := usecase.ReactivateUser(ctx, 2, "appeal accepted"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if repo.statuses[2] != domain.UserStatusActive {
+		t.Errorf("Expected status '%s', got '%s'", domain.UserStatusActive, repo.statuses[2])
+	}
+
+	history, err := usecase.GetStatusHistory(ctx, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(history) != 2 || history[0].ToStatus != domain.UserStatusActive {
+		t.Errorf("Expected 2 history entries newest first, got %+v", history)
+	}
+}
+
+func TestSuspendUserRequiresPermission(t *testing.T) {
+	repo := newMockUserStatusRepository(map[int64]string{1: domain.UserStatusActive, 2: domain.UserStatusActive})
+	policy := NewPolicy(&mockRoleRepository{permissions: map[int64][]string{
+		1: {domain.PermissionUsersManage},
+	}}, nil)
+	usecase := NewAccountStatusUsecase(repo, nil, policy)
+
+	if _, err := usecase.SuspendUser(viewerContext(2), 1, "spam"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+	if _, err := usecase.SuspendUser(viewerContext(1), 1, "oops"); !errors.Is(err, domain.ErrInvalidUserStatusInput) {
+		t.Errorf("Expected ErrInvalidUserStatusInput for self-suspension, got %v", err)
+	}
+
+	// 休止は本人が行え、本人が再開できる
+	if _, err := usecase.DeactivateUser(viewerContext(2), 2, ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := usecase.ReactivateUser(viewerContext(2), 2, ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 停止からの再開は管理者のみ
+	if _, err := usecase.SuspendUser(viewerContext(1), 2, "spam"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := usecase.ReactivateUser(viewerContext(2), 2, "please"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestRestoreUserOnlyFromDeleted(t *testing.T) {
+	repo := newMockUserStatusRepository(map[int64]string{2: domain.UserStatusActive, 3: domain.UserStatusDeleted})
+	usecase := NewAccountStatusUsecase(repo, nil, allowAllPolicy{})
+	ctx := viewerContext(1)
+
+	if _, err := usecase.RestoreUser(ctx, 2, "mistake"); !errors.Is(err, domain.ErrInvalidUserStatusTransition) {
+		t.Errorf("Expected ErrInvalidUserStatusTransition for active user, got %v", err)
+	}
+	if _, err := usecase.ReactivateUser(ctx, 3, "mistake"); !errors.Is(err, domain.ErrInvalidUserStatusTransition) {
+		t.Errorf("Expected ErrInvalidUserStatusTransition when reactivating a deleted user, got %v", err)
+	}
+	if _, err := usecase.RestoreUser(ctx, 4, "mistake"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected domain.ErrNotFound for unknown user, got %v", err)
+	}
+
+	change, err := usecase.RestoreUser(ctx, 3, "deleted by mistake")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if change.FromStatus != domain.UserStatusDeleted || change.ToStatus != domain.UserStatusActive {
+		t.Errorf("Unexpected status change: %+v", change)
+	}
+}
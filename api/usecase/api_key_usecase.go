@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+const (
+	maxAPIKeyNameLength = 100
+	// last_used_atの更新間隔（リクエストごとにUPDATEしないため）
+	apiKeyLastUsedInterval = time.Minute
+)
+
+// CreateAPIKeyInput is the input for issuing an API key
+type CreateAPIKeyInput struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// CreatedAPIKey is returned once when a key is issued; Key is the only copy of the secret
+type CreatedAPIKey struct {
+	domain.APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyUsecase defines business logic for API keys used by machine clients
+type APIKeyUsecase interface {
+	CreateAPIKey(ctx context.Context, userID int64, input CreateAPIKeyInput) (*CreatedAPIKey, error)
+	ListAPIKeys(ctx context.Context, userID int64) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID int64) error
+	Authenticate(ctx context.Context, rawKey string) (*domain.AuthUser, error)
+}
+
+type apiKeyUsecase struct {
+	apiKeyRepo domain.APIKeyRepository
+	userRepo   domain.UserRepository
+	policy     Policy
+}
+
+// NewAPIKeyUsecase creates a new API key usecase
+func NewAPIKeyUsecase(apiKeyRepo domain.APIKeyRepository, userRepo domain.UserRepository, policy Policy) APIKeyUsecase {
+	return &apiKeyUsecase{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		policy:     policy,
+	}
+}
+
+// CreateAPIKey issues a key for the user (the user themselves or users.manage).
+// Keys cannot be issued from a request authenticated with another API key
+func (u *apiKeyUsecase) CreateAPIKey(ctx context.Context, userID int64, input CreateAPIKeyInput) (*CreatedAPIKey, error) {
+	if err := u.policy.RequireOwnerOr(ctx, userID, domain.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+	if viewer, ok := domain.AuthUserFromContext(ctx); ok && viewer.IsAPIKey() {
+		return nil, fmt.Errorf("%w: api keys cannot issue api keys", domain.ErrForbidden)
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxAPIKeyNameLength {
+		return nil, fmt.Errorf("%w: name must be 1-%d characters", domain.ErrInvalidAPIKeyInput, maxAPIKeyNameLength)
+	}
+	scopes, err := normalizeScopes(input.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	prefix, err := newAPIKeyPrefix()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newSecureToken()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &domain.APIKey{
+		UserID:     userID,
+		Name:       name,
+		Prefix:     prefix,
+		SecretHash: hashToken(secret),
+		Scopes:     scopes,
+	}
+	if err := u.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &CreatedAPIKey{
+		APIKey: *key,
+		Key:    domain.APIKeyPrefix + prefix + "_" + secret,
+	}, nil
+}
+
+// ListAPIKeys returns the user's keys including revoked ones (the user themselves or users.manage)
+func (u *apiKeyUsecase) ListAPIKeys(ctx context.Context, userID int64) ([]domain.APIKey, error) {
+	if err := u.policy.RequireOwnerOr(ctx, userID, domain.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+
+	return u.apiKeyRepo.FindByUserID(ctx, userID)
+}
+
+// RevokeAPIKey revokes one of the user's keys (the user themselves or users.manage)
+func (u *apiKeyUsecase) RevokeAPIKey(ctx context.Context, userID, keyID int64) error {
+	if err := u.policy.RequireOwnerOr(ctx, userID, domain.PermissionUsersManage); err != nil {
+		return err
+	}
+
+	key, err := u.apiKeyRepo.FindByID(ctx, keyID)
+	if err != nil {
+		return err
+	}
+	// 他のユーザーのキーは存在しないものとして扱う
+	if key.UserID != userID {
+		return domain.NotFoundError("api key")
+	}
+
+	return u.apiKeyRepo.Revoke(ctx, key)
+}
+
+// Authenticate verifies a raw "tak_<prefix>_<secret>" key and returns its owner with the key's scopes
+func (u *apiKeyUsecase) Authenticate(ctx context.Context, rawKey string) (*domain.AuthUser, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(rawKey), domain.APIKeyPrefix)
+	if !ok {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || prefix == "" || secret == "" {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	key, err := u.apiKeyRepo.FindByPrefix(ctx, prefix)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(hashToken(secret)), []byte(key.SecretHash)) != 1 {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	user, err := u.userRepo.FindByID(ctx, key.UserID)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && user == nil) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	// 停止中・削除済みのユーザーのキーはログインと同様に拒否する
+	if domain.IsUserStatusHidden(user.Status) {
+		return nil, domain.ErrAccountDisabled
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyLastUsedInterval {
+		if err := u.apiKeyRepo.TouchLastUsed(ctx, key, now); err != nil {
+			// 認証自体は成功させる
+			log.Printf("failed to update last used for api key %d: %v", key.ID, err)
+		}
+	}
+
+	return &domain.AuthUser{
+		ID:       user.ID,
+		Username: user.Username,
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+// normalizeScopes はスコープを検証し、重複を除いて定義順に並べます
+func normalizeScopes(scopes []string) ([]string, error) {
+	requested := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope != domain.APIKeyScopeRead && scope != domain.APIKeyScopeWrite {
+			return nil, fmt.Errorf("%w: unknown scope %q", domain.ErrInvalidAPIKeyInput, scope)
+		}
+		requested[scope] = true
+	}
+	if len(requested) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", domain.ErrInvalidAPIKeyInput)
+	}
+
+	var normalized []string
+	for _, scope := range []string{domain.APIKeyScopeRead, domain.APIKeyScopeWrite} {
+		if requested[scope] {
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
+
+// newAPIKeyPrefix はキーを検索するための公開部分（48bit, hex）を生成します
+func newAPIKeyPrefix() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate api key prefix: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// Mock repository for testing
+type mockAPIKeyRepository struct {
+	keys    []*domain.APIKey
+	touches int
+}
+
+func (m *mockAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	key.ID = int64(len(m.keys) + 1)
+	key.CreatedAt = time.Now()
+	copied := *key
+	m.keys = append(m.keys, &copied)
+	return nil
+}
+
+func (m *mockAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	for _, key := range m.keys {
+		if key.Prefix == prefix {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockAPIKeyRepository) FindByID(ctx context.Context, id int64) (*domain.APIKey, error) {
+	for _, key := range m.keys {
+		if key.ID == id {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockAPIKeyRepository) FindByUserID(ctx context.Context, userID int64) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+	for _, key := range m.keys {
+		if key.UserID == userID {
+			keys = append(keys, *key)
+		}
+	}
+	return keys, nil
+}
+
+func (m *mockAPIKeyRepository) Revoke(ctx context.Context, key *domain.APIKey) error {
+	now := time.Now()
+	m.keys[key.ID-1].RevokedAt = &now
+	return nil
+}
+
+func (m *mockAPIKeyRepository) TouchLastUsed(ctx context.Context, key *domain.APIKey, at time.Time) error {
+	m.touches++
+	m.keys[key.ID-1].LastUsedAt = &at
+	return nil
+}
+
+func newAPIKeyTestUsecase() (APIKeyUsecase, *mockAPIKeyRepository) {
+	userRepo := &mockUserRepository{}
+	userRepo.Create(context.Background(), &domain.User{Username: "alice", Email: "alice@example.com"})
+	userRepo.Create(context.Background(), &domain.User{Username: "bob", Email: "bob@example.com"})
+
+	apiKeyRepo := &mockAPIKeyRepository{}
+	policy := NewPolicy(&mockRoleRepository{permissions: map[int64][]string{}}, nil)
+	return NewAPIKeyUsecase(apiKeyRepo, userRepo, policy), apiKeyRepo
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	usecase, apiKeyRepo := newAPIKeyTestUsecase()
+	ctx := viewerContext(1)
+
+	created, err := usecase.CreateAPIKey(ctx, 1, CreateAPIKeyInput{Name: "nightly batch", Scopes: []string{"write", "read", "read"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(created.Key, domain.APIKeyPrefix+created.Prefix+"_") {
+		t.Errorf("Expected key to start with its prefix, got %s", created.Key)
+	}
+	secret := strings.TrimPrefix(created.Key, domain.APIKeyPrefix+created.Prefix+"_")
+	if apiKeyRepo.keys[0].SecretHash != hashToken(secret) {
+		t.Error("Expected the secret to be stored hashed")
+	}
+	if len(created.Scopes) != 2 || created.Scopes[0] != domain.APIKeyScopeRead {
+		t.Errorf("Expected normalized scopes [read write], got %v", created.Scopes)
+	}
+
+	user, err := usecase.Authenticate(context.Background(), created.Key)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.ID != 1 || user.Username != "alice" || !user.IsAPIKey() {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+	if !user.HasScope(domain.APIKeyScopeWrite) {
+		t.Error("Expected write scope")
+	}
+
+	// last_used_at is updated at most once per interval
+	if _, err := usecase.Authenticate(context.Background(), created.Key); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if apiKeyRepo.touches != 1 {
+		t.Errorf("Expected 1 last_used_at update, got %d", apiKeyRepo.touches)
+	}
+
+	if _, err := usecase.Authenticate(context.Background(), created.Key+"x"); !errors.Is(err, domain.ErrInvalidAPIKey) {
+		t.Fatalf("Expected ErrInvalidAPIKey for wrong secret, got %v", err)
+	}
+
+	// another user's key is reported as missing
+	if err := usecase.RevokeAPIKey(viewerContext(2), 2, created.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("Expected domain.ErrNotFound, got %v", err)
+	}
+	if err := usecase.RevokeAPIKey(ctx, 1, created.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := usecase.Authenticate(context.Background(), created.Key); !errors.Is(err, domain.ErrInvalidAPIKey) {
+		t.Fatalf("Expected ErrInvalidAPIKey for revoked key, got %v", err)
+	}
+}
+
+func TestCreateAPIKeyAuthorization(t *testing.T) {
+	usecase, _ := newAPIKeyTestUsecase()
+
+	if _, err := usecase.CreateAPIKey(viewerContext(2), 1, CreateAPIKeyInput{Name: "batch", Scopes: []string{"read"}}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("Expected ErrForbidden for another user, got %v", err)
+	}
+	if _, err := usecase.CreateAPIKey(viewerContext(1), 1, CreateAPIKeyInput{Name: "batch", Scopes: []string{"admin"}}); !errors.Is(err, domain.ErrInvalidAPIKeyInput) {
+		t.Fatalf("Expected ErrInvalidAPIKeyInput for unknown scope, got %v", err)
+	}
+	if _, err := usecase.CreateAPIKey(viewerContext(1), 1, CreateAPIKeyInput{Name: "batch"}); !errors.Is(err, domain.ErrInvalidAPIKeyInput) {
+		t.Fatalf("Expected ErrInvalidAPIKeyInput without scopes, got %v", err)
+	}
+
+	apiKeyCtx := domain.ContextWithAuthUser(context.Background(), &domain.AuthUser{ID: 1, APIKeyID: 1, Scopes: []string{"write"}})
+	if _, err := usecase.CreateAPIKey(apiKeyCtx, 1, CreateAPIKeyInput{Name: "batch", Scopes: []string{"read"}}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("Expected ErrForbidden when using an api key, got %v", err)
+	}
+}
+
+func TestAPIKeyRejectsDisabledUser(t *testing.T) {
+	userRepo := &mockUserRepository{users: []domain.User{
+		{ID: 1, Username: "alice", Status: domain.UserStatusActive},
+	}}
+	policy := NewPolicy(&mockRoleRepository{permissions: map[int64][]string{}}, nil)
+	usecase := NewAPIKeyUsecase(&mockAPIKeyRepository{}, userRepo, policy)
+
+	created, err := usecase.CreateAPIKey(viewerContext(1), 1, CreateAPIKeyInput{Name: "batch", Scopes: []string{"read"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	userRepo.users[0].Status = domain.UserStatusSuspended
+	if _, err := usecase.Authenticate(context.Background(), created.Key); !errors.Is(err, domain.ErrAccountDisabled) {
+		t.Errorf("Expected ErrAccountDisabled, got %v", err)
+	}
+}
@@ -0,0 +1,320 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,50}$`)
+
+const (
+	minPasswordLength = 8
+	// bcryptは72バイトを超える入力を切り捨てるため、それ以上は受け付けない
+	maxPasswordLength = 72
+)
+
+// RegisterInput is the input for user registration
+type RegisterInput struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// LoginInput is the input for login (Login accepts a username or an email)
+type LoginInput struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+// AuthUsecase defines business logic for password authentication and JWT sessions
+type AuthUsecase interface {
+	Register(ctx context.Context, input RegisterInput) (*domain.User, *domain.TokenPair, error)
+	Login(ctx context.Context, input LoginInput) (*domain.LoginResult, error)
+	CompleteTwoFactorLogin(ctx context.Context, challengeToken, code string) (*domain.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*domain.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	Authenticate(ctx context.Context, accessToken string) (*domain.AuthUser, error)
+}
+
+type authUsecase struct {
+	authRepo   domain.AuthRepository
+	hasher     domain.PasswordHasher
+	issuer     domain.TokenIssuer
+	tokenStore domain.RefreshTokenStore
+	verifier   EmailVerificationUsecase // nilの場合は認証メールを送信しない
+	twoFactor  TwoFactorUsecase         // nilの場合は2段階認証を行わない
+	userRepo   domain.UserRepository    // nilの場合はトークン検証時にアカウント状態を確認しない
+}
+
+// NewAuthUsecase creates a new auth usecase
+func NewAuthUsecase(authRepo domain.AuthRepository, hasher domain.PasswordHasher, issuer domain.TokenIssuer, tokenStore domain.RefreshTokenStore, verifier EmailVerificationUsecase, twoFactor TwoFactorUsecase, userRepo domain.UserRepository) AuthUsecase {
+	return &authUsecase{
+		authRepo:   authRepo,
+		hasher:     hasher,
+		issuer:     issuer,
+		tokenStore: tokenStore,
+		verifier:   verifier,
+		twoFactor:  twoFactor,
+		userRepo:   userRepo,
+	}
+}
+
+// Register creates a user with a bcrypt password hash, sends the verification email and starts a session
+func (u *authUsecase) Register(ctx context.Context, input RegisterInput) (*domain.User, *domain.TokenPair, error) {
+	input.Username = strings.TrimSpace(input.Username)
+	input.Email = strings.TrimSpace(input.Email)
+	if err := validateRegisterInput(input); err != nil {
+		return nil, nil, err
+	}
+
+	hash, err := u.hasher.Hash(input.Password)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &domain.User{
+		Username: input.Username,
+		Email:    input.Email,
+	}
+	if err := u.authRepo.CreateUser(ctx, user, hash); err != nil {
+		return nil, nil, err
+	}
+
+	if u.verifier != nil {
+		// 送信に失敗しても登録は成功させる（POST /auth/email/verification で再送できる）
+		if err := u.verifier.SendVerification(ctx, user.ID); err != nil {
+			log.Printf("failed to send verification email to user %d: %v", user.ID, err)
+		}
+	}
+
+	tokens, err := u.issueTokenPair(ctx, domain.AuthUser{ID: user.ID, Username: user.Username})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// Login verifies the password and starts a session, or returns a challenge when the account has 2FA enabled
+func (u *authUsecase) Login(ctx context.Context, input LoginInput) (*domain.LoginResult, error) {
+	login := strings.TrimSpace(input.Login)
+	if login == "" || input.Password == "" {
+		return nil, fmt.Errorf("%w: login and password are required", domain.ErrInvalidAuthInput)
+	}
+
+	creds, err := u.authRepo.FindCredentials(ctx, login)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if err := u.hasher.Compare(creds.PasswordHash, input.Password); err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	// パスワード確認後に判定し、存在しないアカウントと停止中のアカウントを区別できないようにする
+	if creds.Status == domain.UserStatusSuspended || creds.Status == domain.UserStatusDeleted {
+		return nil, domain.ErrAccountDisabled
+	}
+
+	user := domain.AuthUser{ID: creds.UserID, Username: creds.Username}
+
+	if u.twoFactor != nil {
+		enabled, err := u.twoFactor.IsEnabled(ctx, creds.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if enabled {
+			if user.SessionVersion, err = u.tokenStore.SessionVersion(ctx, user.ID); err != nil {
+				return nil, err
+			}
+			// セッションはまだ発行せず、コード入力用の短命なチャレンジトークンだけを返す
+			challenge, claims, err := u.issuer.Issue(user, domain.TokenTypeTwoFactor)
+			if err != nil {
+				return nil, err
+			}
+			return &domain.LoginResult{
+				Challenge: &domain.TwoFactorChallenge{
+					TwoFactorRequired: true,
+					ChallengeToken:    challenge,
+					ExpiresAt:         claims.ExpiresAt,
+				},
+			}, nil
+		}
+	}
+
+	tokens, err := u.completeLogin(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LoginResult{Tokens: tokens}, nil
+}
+
+// CompleteTwoFactorLogin verifies the challenge from Login and a TOTP or recovery code, then starts a session
+func (u *authUsecase) CompleteTwoFactorLogin(ctx context.Context, challengeToken, code string) (*domain.TokenPair, error) {
+	if challengeToken == "" || strings.TrimSpace(code) == "" {
+		return nil, fmt.Errorf("%w: challengeToken and code are required", domain.ErrInvalidAuthInput)
+	}
+	if u.twoFactor == nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	claims, err := u.parseToken(ctx, challengeToken, domain.TokenTypeTwoFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.twoFactor.Verify(ctx, claims.User.ID, code); err != nil {
+		return nil, err
+	}
+
+	return u.completeLogin(ctx, claims.User)
+}
+
+// completeLogin はlast_login_atを記録してセッションを発行します
+func (u *authUsecase) completeLogin(ctx context.Context, user domain.AuthUser) (*domain.TokenPair, error) {
+	if err := u.authRepo.UpdateLastLogin(ctx, user.ID, time.Now()); err != nil {
+		// ログイン自体は成功させる
+		log.Printf("failed to update last login for user %d: %v", user.ID, err)
+	}
+
+	return u.issueTokenPair(ctx, user)
+}
+
+// Refresh rotates the refresh token: the presented token is revoked and a new pair is issued
+func (u *authUsecase) Refresh(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
+	claims, err := u.redeemRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.issueTokenPair(ctx, claims.User)
+}
+
+// Logout revokes the refresh token (access tokens expire on their own)
+func (u *authUsecase) Logout(ctx context.Context, refreshToken string) error {
+	_, err := u.redeemRefreshToken(ctx, refreshToken)
+	return err
+}
+
+// Authenticate verifies an access token and returns the user it was issued to.
+// Tokens issued before the user's sessions were revoked (e.g. by a password reset) are rejected,
+// and so are tokens of suspended or deleted users
+func (u *authUsecase) Authenticate(ctx context.Context, accessToken string) (*domain.AuthUser, error) {
+	claims, err := u.parseToken(ctx, accessToken, domain.TokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
+
+	user := claims.User
+	return &user, nil
+}
+
+// redeemRefreshToken はリフレッシュトークンを検証して失効させます。
+// 確認と失効を1回の操作で行うため、同じトークンでの同時リクエストは1つだけが成功します
+func (u *authUsecase) redeemRefreshToken(ctx context.Context, refreshToken string) (*domain.TokenClaims, error) {
+	if refreshToken == "" {
+		return nil, fmt.Errorf("%w: refreshToken is required", domain.ErrInvalidAuthInput)
+	}
+
+	claims, err := u.parseToken(ctx, refreshToken, domain.TokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.tokenStore.Revoke(ctx, claims.User.ID, claims.TokenID); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// parseToken はトークンを検証し、発行後にセッションが失効させられていないこと、
+// アカウントが停止・削除されていないことを確認します
+func (u *authUsecase) parseToken(ctx context.Context, token, tokenType string) (*domain.TokenClaims, error) {
+	claims, err := u.issuer.Parse(token, tokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := u.tokenStore.SessionVersion(ctx, claims.User.ID)
+	if err != nil {
+		return nil, err
+	}
+	if claims.User.SessionVersion != version {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if u.userRepo != nil {
+		// キャッシュ経由のため、状態の変更はキャッシュの無効化とともに反映される
+		user, err := u.userRepo.FindByID(ctx, claims.User.ID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrInvalidToken
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if domain.IsUserStatusHidden(user.Status) {
+			return nil, domain.ErrInvalidToken
+		}
+	}
+
+	return claims, nil
+}
+
+func (u *authUsecase) issueTokenPair(ctx context.Context, user domain.AuthUser) (*domain.TokenPair, error) {
+	version, err := u.tokenStore.SessionVersion(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	user.SessionVersion = version
+
+	accessToken, accessClaims, err := u.issuer.Issue(user, domain.TokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshClaims, err := u.issuer.Issue(user, domain.TokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.tokenStore.Save(ctx, user.ID, refreshClaims.TokenID, refreshClaims.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:           accessToken,
+		RefreshToken:          refreshToken,
+		TokenType:             "Bearer",
+		AccessTokenExpiresAt:  accessClaims.ExpiresAt,
+		RefreshTokenExpiresAt: refreshClaims.ExpiresAt,
+	}, nil
+}
+
+func validateRegisterInput(input RegisterInput) error {
+	if !usernamePattern.MatchString(input.Username) {
+		return fmt.Errorf("%w: username must be 3-50 letters, digits or underscores", domain.ErrInvalidAuthInput)
+	}
+	if addr, err := mail.ParseAddress(input.Email); err != nil || addr.Address != input.Email || len(input.Email) > 255 {
+		return fmt.Errorf("%w: email is invalid", domain.ErrInvalidAuthInput)
+	}
+	return validatePassword(input.Password)
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return fmt.Errorf("%w: password must be %d-%d bytes", domain.ErrInvalidAuthInput, minPasswordLength, maxPasswordLength)
+	}
+	return nil
+}
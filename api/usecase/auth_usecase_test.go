@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// Mock repository for testing
+type mockAuthRepository struct {
+	users     map[string]*domain.UserCredentials
+	lastLogin map[int64]time.Time
+}
+
+func newMockAuthRepository() *mockAuthRepository {
+	return &mockAuthRepository{
+		users:     make(map[string]*domain.UserCredentials),
+		lastLogin: make(map[int64]time.Time),
+	}
+}
+
+func (m *mockAuthRepository) CreateUser(ctx context.Context, user *domain.User, passwordHash string) error {
+	if _, ok := m.users[user.Username]; ok {
+		return domain.ErrUserAlreadyExists
+	}
+	user.ID = int64(len(m.users) + 1)
+	m.users[user.Username] = &domain.UserCredentials{
+		UserID:       user.ID,
+		Username:     user.Username,
+		Email:        user.Email,
+		PasswordHash: passwordHash,
+		Status:       domain.UserStatusActive,
+	}
+	return nil
+}
+
+func (m *mockAuthRepository) FindCredentials(ctx context.Context, login string) (*domain.UserCredentials, error) {
+	for _, creds := range m.users {
+		if creds.Username == login || creds.Email == login {
+			copied := *creds
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockAuthRepository) UpdateLastLogin(ctx context.Context, userID int64, at time.Time) error {
+	m.lastLogin[userID] = at
+	return nil
+}
+
+// plainHasher は平文に接頭辞を付けるだけのテスト用ハッシャー
+type plainHasher struct{}
+
+func (plainHasher) Hash(password string) (string, error) {
+	return "hashed:" + password, nil
+}
+
+func (plainHasher) Compare(hash, password string) error {
+	if hash != "hashed:"+password {
+		return domain.ErrInvalidCredentials
+	}
+	return nil
+}
+
+// mockTokenIssuer は "type:userID:username:jti:version" 形式のトークンを発行します
+type mockTokenIssuer struct {
+	seq int
+}
+
+func (m *mockTokenIssuer) Issue(user domain.AuthUser, tokenType string) (string, *domain.TokenClaims, error) {
+	m.seq++
+	claims := &domain.TokenClaims{
+		TokenID:   fmt.Sprintf("jti%d", m.seq),
+		Type:      tokenType,
+		User:      user,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	return fmt.Sprintf("%s:%d:%s:%s:%d", tokenType, user.ID, user.Username, claims.TokenID, user.SessionVersion), claims, nil
+}
+
+func (m *mockTokenIssuer) Parse(token, tokenType string) (*domain.TokenClaims, error) {
+	parts := strings.Split(token, ":")
+	if len(parts) != 5 || parts[0] != tokenType {
+		return nil, domain.ErrInvalidToken
+	}
+	var userID, version int64
+	fmt.Sscan(parts[1], &userID)
+	fmt.Sscan(parts[4], &version)
+	return &domain.TokenClaims{
+		TokenID: parts[3],
+		Type:    tokenType,
+		User:    domain.AuthUser{ID: userID, Username: parts[2], SessionVersion: version},
+	}, nil
+}
+
+// Mock store for testing
+type mockRefreshTokenStore struct {
+	mu       sync.Mutex
+	active   map[string]bool
+	versions map[int64]int64
+}
+
+func newMockRefreshTokenStore() *mockRefreshTokenStore {
+	return &mockRefreshTokenStore{active: make(map[string]bool), versions: make(map[int64]int64)}
+}
+
+func (m *mockRefreshTokenStore) Save(ctx context.Context, userID int64, tokenID string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.active[fmt.Sprintf("%d:%s", userID, tokenID)] = true
+	return nil
+}
+
+func (m *mockRefreshTokenStore) Revoke(ctx context.Context, userID int64, tokenID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := fmt.Sprintf("%d:%s", userID, tokenID)
+	if !m.active[key] {
+		return domain.ErrInvalidToken
+	}
+	delete(m.active, key)
+	return nil
+}
+
+func (m *mockRefreshTokenStore) RevokeAll(ctx context.Context, userID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	prefix := fmt.Sprintf("%d:", userID)
+	for key := range m.active {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.active, key)
+		}
+	}
+	m.versions[userID]++
+	return nil
+}
+
+func (m *mockRefreshTokenStore) SessionVersion(ctx context.Context, userID int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.versions[userID], nil
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	repo := newMockAuthRepository()
+	usecase := NewAuthUsecase(repo, plainHasher{}, &mockTokenIssuer{}, newMockRefreshTokenStore(), nil, nil, nil)
+	ctx := context.Background()
+
+	user, _, err := usecase.Register(ctx, RegisterInput{Username: "alice", Email: "alice@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if repo.users["alice"].PasswordHash != "hashed:password123" {
+		t.Errorf("Expected password to be stored hashed, got %s", repo.users["alice"].PasswordHash)
+	}
+
+	if _, _, err := usecase.Register(ctx, RegisterInput{Username: "alice", Email: "other@example.com", Password: "password123"}); !errors.Is(err, domain.ErrUserAlreadyExists) {
+		t.Errorf("Expected ErrUserAlreadyExists, got %v", err)
+	}
+
+	if _, err := usecase.Login(ctx, LoginInput{Login: "alice@example.com", Password: "wrong-password"}); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials, got %v", err)
+	}
+
+	result, err := usecase.Login(ctx, LoginInput{Login: "alice@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Challenge != nil {
+		t.Fatal("Expected no 2FA challenge without 2FA")
+	}
+	tokens := result.Tokens
+	if _, ok := repo.lastLogin[user.ID]; !ok {
+		t.Error("Expected last_login_at to be updated")
+	}
+
+	authUser, err := usecase.Authenticate(ctx, tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if authUser.ID != user.ID {
+		t.Errorf("Expected user %d, got %d", user.ID, authUser.ID)
+	}
+	if _, err := usecase.Authenticate(ctx, tokens.RefreshToken); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected refresh token to be rejected as access token, got %v", err)
+	}
+}
+
+func TestRegisterRejectsInvalidInput(t *testing.T) {
+	usecase := NewAuthUsecase(newMockAuthRepository(), plainHasher{}, &mockTokenIssuer{}, newMockRefreshTokenStore(), nil, nil, nil)
+	ctx := context.Background()
+
+	inputs := []RegisterInput{
+		{Username: "a", Email: "a@example.com", Password: "password123"},
+		{Username: "alice", Email: "not-an-email", Password: "password123"},
+		{Username: "alice", Email: "alice@example.com", Password: "short"},
+	}
+	for _, input := range inputs {
+		if _, _, err := usecase.Register(ctx, input); !errors.Is(err, domain.ErrInvalidAuthInput) {
+			t.Errorf("Expected ErrInvalidAuthInput for %+v, got %v", input, err)
+		}
+	}
+}
+
+func TestLoginRejectsSuspendedAccount(t *testing.T) {
+	repo := newMockAuthRepository()
+	usecase := NewAuthUsecase(repo, plainHasher{}, &mockTokenIssuer{}, newMockRefreshTokenStore(), nil, nil, nil)
+	ctx := context.Background()
+
+	if _, _, err := usecase.Register(ctx, RegisterInput{Username: "bob", Email: "bob@example.com", Password: "password123"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	repo.users["bob"].Status = domain.UserStatusSuspended
+
+	if _, err := usecase.Login(ctx, LoginInput{Login: "bob", Password: "password123"}); !errors.Is(err, domain.ErrAccountDisabled) {
+		t.Errorf("Expected ErrAccountDisabled, got %v", err)
+	}
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	usecase := NewAuthUsecase(newMockAuthRepository(), plainHasher{}, &mockTokenIssuer{}, newMockRefreshTokenStore(), nil, nil, nil)
+	ctx := context.Background()
+
+	_, tokens, err := usecase.Register(ctx, RegisterInput{Username: "carol", Email: "carol@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	rotated, err := usecase.Refresh(ctx, tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// the old refresh token is revoked after rotation
+	if _, err := usecase.Refresh(ctx, tokens.RefreshToken); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for reused refresh token, got %v", err)
+	}
+
+	if err := usecase.Logout(ctx, rotated.RefreshToken); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := usecase.Refresh(ctx, rotated.RefreshToken); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken after logout, got %v", err)
+	}
+}
+
+func TestRefreshRedeemsTokenOnlyOnce(t *testing.T) {
+	usecase := NewAuthUsecase(newMockAuthRepository(), plainHasher{}, &mockTokenIssuer{}, newMockRefreshTokenStore(), nil, nil, nil)
+	ctx := context.Background()
+
+	_, tokens, err := usecase.Register(ctx, RegisterInput{Username: "dave", Email: "dave@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	const attempts = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := usecase.Refresh(ctx, tokens.RefreshToken)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, domain.ErrInvalidToken):
+			t.Errorf("Expected ErrInvalidToken for a concurrent reuse, got %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("Expected exactly 1 refresh to succeed, got %d", succeeded)
+	}
+}
+
+func TestAuthenticateRejectsDisabledUsers(t *testing.T) {
+	userRepo := &mockUserRepository{}
+	usecase := NewAuthUsecase(newMockAuthRepository(), plainHasher{}, &mockTokenIssuer{}, newMockRefreshTokenStore(), nil, nil, userRepo)
+	ctx := context.Background()
+
+	user, tokens, err := usecase.Register(ctx, RegisterInput{Username: "erin", Email: "erin@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	userRepo.Create(ctx, &domain.User{Username: user.Username, Email: user.Email, Status: domain.UserStatusActive})
+
+	if _, err := usecase.Authenticate(ctx, tokens.AccessToken); err != nil {
+		t.Fatalf("Expected no error for an active user, got %v", err)
+	}
+
+	// 停止・削除されたユーザーのトークンは有効期限内でも使えない（セッションを失効させていなくても）
+	for _, status := range []string{domain.UserStatusSuspended, domain.UserStatusDeleted} {
+		userRepo.users[0].Status = status
+		if _, err := usecase.Authenticate(ctx, tokens.AccessToken); !errors.Is(err, domain.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken for a %s user, got %v", status, err)
+		}
+		if _, err := usecase.Refresh(ctx, tokens.RefreshToken); !errors.Is(err, domain.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken on refresh for a %s user, got %v", status, err)
+		}
+	}
+
+	userRepo.users[0].Status = domain.UserStatusInactive
+	if _, err := usecase.Authenticate(ctx, tokens.AccessToken); err != nil {
+		t.Errorf("Expected no error for an inactive user, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+const (
+	// MaxAvatarBytes is the maximum size of an uploaded avatar file
+	MaxAvatarBytes = 5 << 20
+	// avatarURLSize はavatar_urlに保存するサムネイルのサイズ
+	avatarURLSize = 256
+)
+
+// avatarSizes は生成するサムネイルの一辺のピクセル数
+var avatarSizes = []int{64, 128, avatarURLSize}
+
+// avatarKeyPattern はavatar_urlからこのユースケースが保存したキーを取り出す（avatars/{userID}/{version}_{size}.{ext}）
+var avatarKeyPattern = regexp.MustCompile(`avatars/(\d+)/([0-9a-f]+)_\d+\.(jpg|png)$`)
+
+// AvatarUsecase defines business logic for uploading and removing avatar images
+type AvatarUsecase interface {
+	UploadAvatar(ctx context.Context, userID int64, data []byte) (*domain.Avatar, error)
+	DeleteAvatar(ctx context.Context, userID int64) error
+}
+
+type avatarUsecase struct {
+	profileRepo domain.UserProfileRepository
+	blobStore   domain.BlobStore
+	processor   domain.ImageProcessor
+	policy      Policy
+}
+
+// NewAvatarUsecase creates a new avatar usecase
+func NewAvatarUsecase(profileRepo domain.UserProfileRepository, blobStore domain.BlobStore, processor domain.ImageProcessor, policy Policy) AvatarUsecase {
+	return &avatarUsecase{
+		profileRepo: profileRepo,
+		blobStore:   blobStore,
+		processor:   processor,
+		policy:      policy,
+	}
+}
+
+// UploadAvatar validates the image, stores thumbnails for every size and points avatar_url at the largest one
+// (the user themselves or users.manage). Previous avatar files are removed after the update
+func (u *avatarUsecase) UploadAvatar(ctx context.Context, userID int64, data []byte) (*domain.Avatar, error) {
+	if err := u.policy.RequireOwnerOr(ctx, userID, domain.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", domain.ErrInvalidAvatar)
+	}
+	if len(data) > MaxAvatarBytes {
+		return nil, fmt.Errorf("%w: file must be at most %d bytes", domain.ErrInvalidAvatar, MaxAvatarBytes)
+	}
+
+	profile, err := u.profileRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnails, err := u.processor.Thumbnails(data, avatarSizes)
+	if err != nil {
+		return nil, err
+	}
+
+	// 毎回新しいキーに保存し、URLが変わることでブラウザ・CDNのキャッシュを更新させる
+	version, err := newAvatarVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	avatar := &domain.Avatar{Thumbnails: make(map[string]string, len(thumbnails))}
+	stored := make([]string, 0, len(thumbnails))
+	for _, thumbnail := range thumbnails {
+		key := fmt.Sprintf("avatars/%d/%s_%d.%s", userID, version, thumbnail.Size, thumbnail.Extension)
+		if err := u.blobStore.Put(ctx, key, thumbnail.Data, thumbnail.ContentType); err != nil {
+			u.deleteBlobs(ctx, stored)
+			return nil, err
+		}
+		stored = append(stored, key)
+
+		url := u.blobStore.URL(key)
+		avatar.Thumbnails[strconv.Itoa(thumbnail.Size)] = url
+		if thumbnail.Size == avatarURLSize {
+			avatar.AvatarURL = url
+		}
+	}
+
+	if err := u.profileRepo.UpdateAvatarURL(ctx, userID, &avatar.AvatarURL); err != nil {
+		u.deleteBlobs(ctx, stored)
+		return nil, err
+	}
+
+	if profile.AvatarURL != nil {
+		u.deleteBlobs(ctx, avatarKeys(userID, *profile.AvatarURL))
+	}
+
+	return avatar, nil
+}
+
+// DeleteAvatar clears avatar_url and removes the stored files (the user themselves or users.manage)
+func (u *avatarUsecase) DeleteAvatar(ctx context.Context, userID int64) error {
+	if err := u.policy.RequireOwnerOr(ctx, userID, domain.PermissionUsersManage); err != nil {
+		return err
+	}
+
+	profile, err := u.profileRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if profile.AvatarURL == nil {
+		return nil
+	}
+
+	if err := u.profileRepo.UpdateAvatarURL(ctx, userID, nil); err != nil {
+		return err
+	}
+
+	u.deleteBlobs(ctx, avatarKeys(userID, *profile.AvatarURL))
+	return nil
+}
+
+// deleteBlobs は不要になったファイルを削除します。失敗しても処理は続け、ログに残します
+func (u *avatarUsecase) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := u.blobStore.Delete(ctx, key); err != nil {
+			log.Printf("failed to delete avatar blob %s: %v", key, err)
+		}
+	}
+}
+
+// avatarKeys はavatar_urlから同じバージョンの全サイズのキーを復元します。
+// 外部URLなど、このユースケースが保存したものでない場合は空を返します
+func avatarKeys(userID int64, avatarURL string) []string {
+	match := avatarKeyPattern.FindStringSubmatch(avatarURL)
+	if match == nil || match[1] != strconv.FormatInt(userID, 10) {
+		return nil
+	}
+
+	keys := make([]string, 0, len(avatarSizes))
+	for _, size := range avatarSizes {
+		keys = append(keys, fmt.Sprintf("avatars/%s/%s_%d.%s", match[1], match[2], size, match[3]))
+	}
+	return keys
+}
+
+func newAvatarVersion() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate avatar version: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
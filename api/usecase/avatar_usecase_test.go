@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// Mock blob store for testing
+type mockBlobStore struct {
+	blobs map[string][]byte
+}
+
+func (m *mockBlobStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	m.blobs[key] = data
+	return nil
+}
+
+func (m *mockBlobStore) Delete(ctx context.Context, key string) error {
+	delete(m.blobs, key)
+	return nil
+}
+
+func (m *mockBlobStore) URL(key string) string {
+	return "http://localhost:8080/static/" + key
+}
+
+// mockImageProcessor は"png"で始まるデータだけを画像として扱う
+type mockImageProcessor struct{}
+
+func (mockImageProcessor) Thumbnails(data []byte, sizes []int) ([]domain.Thumbnail, error) {
+	if len(data) < 3 || string(data[:3]) != "png" {
+		return nil, domain.ErrInvalidAvatar
+	}
+	thumbnails := make([]domain.Thumbnail, 0, len(sizes))
+	for _, size := range sizes {
+		thumbnails = append(thumbnails, domain.Thumbnail{Size: size, ContentType: "image/png", Extension: "png", Data: data})
+	}
+	return thumbnails, nil
+}
+
+func TestUploadAvatarReplacesPreviousFiles(t *testing.T) {
+	profiles := &mockUserProfileRepository{profiles: map[int64]*domain.UserProfile{1: {}}}
+	blobs := &mockBlobStore{blobs: make(map[string][]byte)}
+	usecase := NewAvatarUsecase(profiles, blobs, mockImageProcessor{}, allowAllPolicy{})
+	ctx := viewerContext(1)
+
+	first, err := usecase.UploadAvatar(ctx, 1, []byte("png-1"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(first.Thumbnails) != 3 || len(blobs.blobs) != 3 {
+		t.Fatalf("Expected 3 thumbnails, got %d (%d stored)", len(first.Thumbnails), len(blobs.blobs))
+	}
+	if first.AvatarURL != first.Thumbnails["256"] || *profiles.profiles[1].AvatarURL != first.AvatarURL {
+		t.Errorf("Expected avatar_url to point at the 256px thumbnail, got %s", first.AvatarURL)
+	}
+
+	second, err := usecase.UploadAvatar(ctx, 1, []byte("png-2"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if second.AvatarURL == first.AvatarURL {
+		t.Error("Expected a new versioned URL")
+	}
+	if len(blobs.blobs) != 3 {
+		t.Errorf("Expected previous files to be deleted, %d files stored", len(blobs.blobs))
+	}
+
+	if err := usecase.DeleteAvatar(ctx, 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if profiles.profiles[1].AvatarURL != nil || len(blobs.blobs) != 0 {
+		t.Errorf("Expected avatar to be removed, url=%v files=%d", profiles.profiles[1].AvatarURL, len(blobs.blobs))
+	}
+}
+
+func TestUploadAvatarRejectsInvalidImages(t *testing.T) {
+	profiles := &mockUserProfileRepository{profiles: map[int64]*domain.UserProfile{1: {}}}
+	blobs := &mockBlobStore{blobs: make(map[string][]byte)}
+	usecase := NewAvatarUsecase(profiles, blobs, mockImageProcessor{}, allowAllPolicy{})
+	ctx := viewerContext(1)
+
+	if _, err := usecase.UploadAvatar(ctx, 1, []byte("gif")); !errors.Is(err, domain.ErrInvalidAvatar) {
+		t.Errorf("Expected ErrInvalidAvatar for unsupported image, got %v", err)
+	}
+	if _, err := usecase.UploadAvatar(ctx, 1, make([]byte, MaxAvatarBytes+1)); !errors.Is(err, domain.ErrInvalidAvatar) {
+		t.Errorf("Expected ErrInvalidAvatar for oversized file, got %v", err)
+	}
+	if len(blobs.blobs) != 0 {
+		t.Errorf("Expected nothing to be stored, got %d files", len(blobs.blobs))
+	}
+}
@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// CommentUsecase defines business logic for comments and moderation
+type CommentUsecase interface {
+	PostComment(ctx context.Context, postID, userID int64, parentID *int64, content string) (*domain.Comment, error)
+	GetCommentTree(ctx context.Context, postID int64, page, pageSize int) ([]*domain.CommentThread, int64, error)
+	GetModerationQueue(ctx context.Context, status string, page, pageSize int) ([]domain.CommentWithAuthor, error)
+	ApproveComment(ctx context.Context, id int64) (*domain.Comment, error)
+	RejectComment(ctx context.Context, id int64) (*domain.Comment, error)
+	MarkCommentAsSpam(ctx context.Context, id int64) (*domain.Comment, error)
+}
+
+type commentUsecase struct {
+	commentRepo domain.CommentRepository
+	postRepo    domain.PostRepository
+	notifier    Notifier // nilの場合は通知しない
+	policy      Policy
+}
+
+// NewCommentUsecase creates a new comment usecase
+func NewCommentUsecase(commentRepo domain.CommentRepository, postRepo domain.PostRepository, notifier Notifier, policy Policy) CommentUsecase {
+	return &commentUsecase{
+		commentRepo: commentRepo,
+		postRepo:    postRepo,
+		notifier:    notifier,
+		policy:      policy,
+	}
+}
+
+// PostComment creates a pending comment or reply on a published post
+func (u *commentUsecase) PostComment(ctx context.Context, postID, userID int64, parentID *int64, content string) (*domain.Comment, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("%w: userId is required", domain.ErrInvalidCommentInput)
+	}
+	if err := u.policy.RequireOwnerOr(ctx, userID, domain.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+	if err := u.policy.RequireVerifiedEmail(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	content = strings.TrimSpace(content)
+	if content == "" || utf8.RuneCountInString(content) > 5000 {
+		return nil, fmt.Errorf("%w: content must be 1-5000 characters", domain.ErrInvalidCommentInput)
+	}
+
+	post, err := u.postRepo.FindByID(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+	if post.Status != domain.PostStatusPublished {
+		return nil, fmt.Errorf("%w: post %d is not published", domain.ErrInvalidCommentInput, postID)
+	}
+
+	if parentID != nil {
+		parent, err := u.commentRepo.FindByID(ctx, *parentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent comment: %w", err)
+		}
+		if parent.PostID != postID {
+			return nil, fmt.Errorf("%w: parent comment belongs to another post", domain.ErrInvalidCommentInput)
+		}
+		if parent.Status != domain.CommentStatusApproved {
+			return nil, fmt.Errorf("%w: cannot reply to an unapproved comment", domain.ErrInvalidCommentInput)
+		}
+	}
+
+	// 新規コメントはモデレーション待ち（pending）で登録
+	comment := &domain.Comment{
+		PostID:   postID,
+		UserID:   userID,
+		ParentID: parentID,
+		Content:  content,
+		Status:   domain.CommentStatusPending,
+	}
+	if err := u.commentRepo.Create(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// GetCommentTree returns a page of approved root comments with nested replies and the total root count
+func (u *commentUsecase) GetCommentTree(ctx context.Context, postID int64, page, pageSize int) ([]*domain.CommentThread, int64, error) {
+	if postID <= 0 {
+		return nil, 0, fmt.Errorf("%w: invalid post ID: %d", domain.ErrInvalidCommentInput, postID)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+
+	comments, err := u.commentRepo.FindApprovedThreadByPostID(ctx, postID, pageSize, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	total, err := u.commentRepo.CountApprovedRootsByPostID(ctx, postID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	return buildCommentTree(comments), total, nil
+}
+
+// GetModerationQueue returns comments in the given status (pending by default)
+func (u *commentUsecase) GetModerationQueue(ctx context.Context, status string, page, pageSize int) ([]domain.CommentWithAuthor, error) {
+	if err := u.policy.RequirePermission(ctx, domain.PermissionCommentsModerate); err != nil {
+		return nil, err
+	}
+
+	if status == "" {
+		status = domain.CommentStatusPending
+	}
+	if !isCommentStatus(status) {
+		return nil, fmt.Errorf("%w: unknown status %q", domain.ErrInvalidCommentInput, status)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+
+	comments, err := u.commentRepo.FindByStatus(ctx, status, pageSize, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+// ApproveComment makes a comment visible and counts it in posts.comment_count
+func (u *commentUsecase) ApproveComment(ctx context.Context, id int64) (*domain.Comment, error) {
+	return u.moderate(ctx, id, domain.CommentStatusApproved)
+}
+
+// RejectComment hides a comment
+func (u *commentUsecase) RejectComment(ctx context.Context, id int64) (*domain.Comment, error) {
+	return u.moderate(ctx, id, domain.CommentStatusRejected)
+}
+
+// MarkCommentAsSpam hides a comment as spam
+func (u *commentUsecase) MarkCommentAsSpam(ctx context.Context, id int64) (*domain.Comment, error) {
+	return u.moderate(ctx, id, domain.CommentStatusSpam)
+}
+
+func (u *commentUsecase) moderate(ctx context.Context, id int64, status string) (*domain.Comment, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid comment ID: %d", domain.ErrInvalidCommentInput, id)
+	}
+	if err := u.policy.RequirePermission(ctx, domain.PermissionCommentsModerate); err != nil {
+		return nil, err
+	}
+
+	current, err := u.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	if err := u.commentRepo.UpdateStatus(ctx, id, status); err != nil {
+		return nil, fmt.Errorf("failed to update comment status: %w", err)
+	}
+
+	comment, err := u.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 承認（公開）状態に変わった時だけ通知する
+	if status == domain.CommentStatusApproved && current.Status != domain.CommentStatusApproved && u.notifier != nil {
+		u.notifier.NotifyCommentApproved(ctx, comment)
+	}
+
+	return comment, nil
+}
+
+// buildCommentTree は created_at 順のフラットなコメント一覧を返信ツリーに組み立てます
+func buildCommentTree(comments []domain.CommentWithAuthor) []*domain.CommentThread {
+	nodes := make(map[int64]*domain.CommentThread, len(comments))
+	for _, comment := range comments {
+		nodes[comment.ID] = &domain.CommentThread{
+			CommentWithAuthor: comment,
+			Replies:           []*domain.CommentThread{},
+		}
+	}
+
+	roots := []*domain.CommentThread{}
+	for _, comment := range comments {
+		node := nodes[comment.ID]
+		if comment.ParentID != nil {
+			if parent, ok := nodes[*comment.ParentID]; ok {
+				parent.Replies = append(parent.Replies, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	return roots
+}
+
+func isCommentStatus(status string) bool {
+	switch status {
+	case domain.CommentStatusPending, domain.CommentStatusApproved, domain.CommentStatusRejected, domain.CommentStatusSpam:
+		return true
+	}
+	return false
+}
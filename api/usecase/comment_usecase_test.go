@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// Mock repository for testing
+type mockCommentRepository struct {
+	comments map[int64]*domain.Comment
+	nextID   int64
+}
+
+func newMockCommentRepository() *mockCommentRepository {
+	return &mockCommentRepository{comments: make(map[int64]*domain.Comment)}
+}
+
+func (m *mockCommentRepository) FindByID(ctx context.Context, id int64) (*domain.Comment, error) {
+	comment, ok := m.comments[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *comment
+	return &copied, nil
+}
+
+func (m *mockCommentRepository) FindApprovedThreadByPostID(ctx context.Context, postID int64, limit, offset int) ([]domain.CommentWithAuthor, error) {
+	var comments []domain.CommentWithAuthor
+	for id := int64(1); id <= m.nextID; id++ {
+		comment, ok := m.comments[id]
+		if ok && comment.PostID == postID && comment.Status == domain.CommentStatusApproved {
+			comments = append(comments, domain.CommentWithAuthor{Comment: *comment})
+		}
+	}
+	return comments, nil
+}
+
+func (m *mockCommentRepository) CountApprovedRootsByPostID(ctx context.Context, postID int64) (int64, error) {
+	var count int64
+	for _, comment := range m.comments {
+		if comment.PostID == postID && comment.ParentID == nil && comment.Status == domain.CommentStatusApproved {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockCommentRepository) FindByStatus(ctx context.Context, status string, limit, offset int) ([]domain.CommentWithAuthor, error) {
+	return nil, nil
+}
+
+func (m *mockCommentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	m.nextID++
+	comment.ID = m.nextID
+	copied := *comment
+	m.comments[comment.ID] = &copied
+	return nil
+}
+
+func (m *mockCommentRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	comment, ok := m.comments[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	comment.Status = status
+	return nil
+}
+
+// mockNotifier records approved comment notifications
+type mockNotifier struct {
+	approved []int64
+}
+
+func (m *mockNotifier) NotifyFollow(ctx context.Context, followerID, followingID int64) {}
+
+func (m *mockNotifier) NotifyPostLiked(ctx context.Context, userID, postID int64) {}
+
+func (m *mockNotifier) NotifyCommentLiked(ctx context.Context, userID, commentID int64) {}
+
+func (m *mockNotifier) NotifyCommentApproved(ctx context.Context, comment *domain.Comment) {
+	m.approved = append(m.approved, comment.ID)
+}
+
+func newCommentTestUsecase() (CommentUsecase, *mockCommentRepository, *mockNotifier) {
+	postRepo := newMockPostRepository()
+	postRepo.posts[1] = &domain.Post{ID: 1, Status: domain.PostStatusPublished}
+	postRepo.posts[2] = &domain.Post{ID: 2, Status: domain.PostStatusPublished}
+	postRepo.posts[3] = &domain.Post{ID: 3, Status: domain.PostStatusDraft}
+
+	commentRepo := newMockCommentRepository()
+	notifier := &mockNotifier{}
+	return NewCommentUsecase(commentRepo, postRepo, notifier, allowAllPolicy{}), commentRepo, notifier
+}
+
+// postApproved は承認済みのコメント（parentIDへの返信）を作ります
+func postApproved(t *testing.T, uc CommentUsecase, postID int64, parentID *int64) *domain.Comment {
+	t.Helper()
+	comment, err := uc.PostComment(context.Background(), postID, 10, parentID, "comment")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if comment.Status != domain.CommentStatusPending {
+		t.Fatalf("Expected new comment to be pending, got %s", comment.Status)
+	}
+	if _, err := uc.ApproveComment(context.Background(), comment.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return comment
+}
+
+func TestCommentTreeNestsRepliesAtAnyDepth(t *testing.T) {
+	uc, _, _ := newCommentTestUsecase()
+	ctx := context.Background()
+
+	root := postApproved(t, uc, 1, nil)
+	reply := postApproved(t, uc, 1, &root.ID)
+	nested := postApproved(t, uc, 1, &reply.ID)
+	deepest := postApproved(t, uc, 1, &nested.ID)
+	postApproved(t, uc, 1, nil)
+	// 承認待ちの返信はツリーに含まれない
+	if _, err := uc.PostComment(ctx, 1, 11, &deepest.ID, "pending"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	roots, total, err := uc.GetCommentTree(ctx, 1, 1, 20)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if total != 2 || len(roots) != 2 {
+		t.Fatalf("Expected 2 root comments, got total=%d len=%d", total, len(roots))
+	}
+
+	node := roots[0]
+	for _, want := range []int64{root.ID, reply.ID, nested.ID, deepest.ID} {
+		if node.ID != want {
+			t.Fatalf("Expected comment %d at this depth, got %d", want, node.ID)
+		}
+		if want == deepest.ID {
+			break
+		}
+		if len(node.Replies) != 1 {
+			t.Fatalf("Expected 1 reply on comment %d, got %d", node.ID, len(node.Replies))
+		}
+		node = node.Replies[0]
+	}
+	if len(node.Replies) != 0 {
+		t.Errorf("Expected the pending reply to be hidden, got %d replies", len(node.Replies))
+	}
+}
+
+func TestPostCommentRejectsInvalidParent(t *testing.T) {
+	uc, _, _ := newCommentTestUsecase()
+	ctx := context.Background()
+
+	pending, err := uc.PostComment(ctx, 1, 10, nil, "pending")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := uc.PostComment(ctx, 1, 11, &pending.ID, "reply"); !errors.Is(err, domain.ErrInvalidCommentInput) {
+		t.Errorf("Expected ErrInvalidCommentInput for a reply to a pending comment, got %v", err)
+	}
+
+	other := postApproved(t, uc, 2, nil)
+	if _, err := uc.PostComment(ctx, 1, 11, &other.ID, "reply"); !errors.Is(err, domain.ErrInvalidCommentInput) {
+		t.Errorf("Expected ErrInvalidCommentInput for a parent on another post, got %v", err)
+	}
+
+	if _, err := uc.PostComment(ctx, 3, 11, nil, "draft"); !errors.Is(err, domain.ErrInvalidCommentInput) {
+		t.Errorf("Expected ErrInvalidCommentInput for an unpublished post, got %v", err)
+	}
+}
+
+func TestModerationTransitions(t *testing.T) {
+	uc, commentRepo, notifier := newCommentTestUsecase()
+	ctx := context.Background()
+
+	comment, err := uc.PostComment(ctx, 1, 10, nil, "hello")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	steps := []struct {
+		moderate func(context.Context, int64) (*domain.Comment, error)
+		status   string
+		notified int
+	}{
+		{uc.ApproveComment, domain.CommentStatusApproved, 1},
+		// 承認済みのコメントを再度承認しても通知しない
+		{uc.ApproveComment, domain.CommentStatusApproved, 1},
+		{uc.RejectComment, domain.CommentStatusRejected, 1},
+		{uc.ApproveComment, domain.CommentStatusApproved, 2},
+		{uc.MarkCommentAsSpam, domain.CommentStatusSpam, 2},
+	}
+	for i, step := range steps {
+		moderated, err := step.moderate(ctx, comment.ID)
+		if err != nil {
+			t.Fatalf("step %d: Expected no error, got %v", i, err)
+		}
+		if moderated.Status != step.status || commentRepo.comments[comment.ID].Status != step.status {
+			t.Errorf("step %d: Expected status %s, got %s", i, step.status, moderated.Status)
+		}
+		if len(notifier.approved) != step.notified {
+			t.Errorf("step %d: Expected %d approval notifications, got %d", i, step.notified, len(notifier.approved))
+		}
+	}
+
+	if _, err := uc.ApproveComment(ctx, 999); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing comment, got %v", err)
+	}
+	if _, err := uc.GetModerationQueue(ctx, "deleted", 1, 20); !errors.Is(err, domain.ErrInvalidCommentInput) {
+		t.Errorf("Expected ErrInvalidCommentInput for an unknown status, got %v", err)
+	}
+}
+
+func TestBuildCommentTree(t *testing.T) {
+	parent := int64(1)
+	child := int64(2)
+	comments := []domain.CommentWithAuthor{
+		{Comment: domain.Comment{ID: 1}},
+		{Comment: domain.Comment{ID: 2, ParentID: &parent}},
+		{Comment: domain.Comment{ID: 3}},
+		{Comment: domain.Comment{ID: 4, ParentID: &child}},
+		{Comment: domain.Comment{ID: 5, ParentID: &parent}},
+	}
+
+	roots := buildCommentTree(comments)
+	if len(roots) != 2 {
+		t.Fatalf("Expected 2 root comments, got %d", len(roots))
+	}
+
+	if len(roots[0].Replies) != 2 {
+		t.Fatalf("Expected 2 replies on comment 1, got %d", len(roots[0].Replies))
+	}
+
+	if roots[0].Replies[0].ID != 2 || len(roots[0].Replies[0].Replies) != 1 || roots[0].Replies[0].Replies[0].ID != 4 {
+		t.Errorf("Expected comment 4 nested under comment 2")
+	}
+
+	if len(roots[1].Replies) != 0 {
+		t.Errorf("Expected no replies on comment 3, got %d", len(roots[1].Replies))
+	}
+}
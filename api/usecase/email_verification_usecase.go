@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// EmailVerificationUsecase defines business logic for verifying users' email addresses
+type EmailVerificationUsecase interface {
+	SendVerification(ctx context.Context, userID int64) error
+	ConfirmEmail(ctx context.Context, token string) error
+}
+
+type emailVerificationUsecase struct {
+	verificationRepo domain.EmailVerificationRepository
+	userRepo         domain.UserRepository
+	mailer           domain.Mailer
+	baseURL          string // 認証リンクのベースURL（例: http://localhost:8080）
+	ttl              time.Duration
+}
+
+// NewEmailVerificationUsecase creates a new email verification usecase
+func NewEmailVerificationUsecase(verificationRepo domain.EmailVerificationRepository, userRepo domain.UserRepository, mailer domain.Mailer, baseURL string, ttl time.Duration) EmailVerificationUsecase {
+	return &emailVerificationUsecase{
+		verificationRepo: verificationRepo,
+		userRepo:         userRepo,
+		mailer:           mailer,
+		baseURL:          strings.TrimRight(baseURL, "/"),
+		ttl:              ttl,
+	}
+}
+
+// SendVerification issues a new single-use token (invalidating earlier ones) and emails the confirm link
+func (u *emailVerificationUsecase) SendVerification(ctx context.Context, userID int64) error {
+	verified, err := u.verificationRepo.IsEmailVerified(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if verified {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	token, err := newSecureToken()
+	if err != nil {
+		return err
+	}
+
+	record := &domain.EmailVerificationToken{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(u.ttl),
+	}
+	if err := u.verificationRepo.Create(ctx, record); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/auth/email/verify?token=%s", u.baseURL, url.QueryEscape(token))
+	return u.mailer.Send(ctx, domain.EmailMessage{
+		To:      user.Email,
+		Subject: "メールアドレスの確認",
+		Body: fmt.Sprintf("%sさん\n\n以下のリンクからメールアドレスを確認してください（%s以内に有効）。\n\n%s\n\n心当たりがない場合はこのメールを破棄してください。\n",
+			user.Username, u.ttl, link),
+	})
+}
+
+// ConfirmEmail consumes the token and marks the email verified
+func (u *emailVerificationUsecase) ConfirmEmail(ctx context.Context, token string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return domain.ErrInvalidVerificationToken
+	}
+
+	_, err := u.verificationRepo.Consume(ctx, hashToken(token), time.Now())
+	return err
+}
+
+// newSecureToken はメールで送る推測不能なトークン（256bit, base64url）を生成します
+func newSecureToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken はDBに保存するトークンのSHA-256（hex）を返します。
+// トークン自体が十分なエントロピーを持つため、ソルトやストレッチングは不要です
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
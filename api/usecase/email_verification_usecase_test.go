@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/infrastructure/mail"
+)
+
+// Mock repository for testing
+type mockEmailVerificationRepository struct {
+	tokens   map[string]*domain.EmailVerificationToken
+	verified map[int64]bool
+}
+
+func newMockEmailVerificationRepository() *mockEmailVerificationRepository {
+	return &mockEmailVerificationRepository{
+		tokens:   make(map[string]*domain.EmailVerificationToken),
+		verified: make(map[int64]bool),
+	}
+}
+
+func (m *mockEmailVerificationRepository) Create(ctx context.Context, token *domain.EmailVerificationToken) error {
+	now := time.Now()
+	for _, t := range m.tokens {
+		if t.UserID == token.UserID && t.UsedAt == nil {
+			t.UsedAt = &now
+		}
+	}
+	copied := *token
+	m.tokens[token.TokenHash] = &copied
+	return nil
+}
+
+func (m *mockEmailVerificationRepository) Consume(ctx context.Context, tokenHash string, now time.Time) (int64, error) {
+	token, ok := m.tokens[tokenHash]
+	if !ok || token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+		return 0, domain.ErrInvalidVerificationToken
+	}
+	token.UsedAt = &now
+	m.verified[token.UserID] = true
+	return token.UserID, nil
+}
+
+func (m *mockEmailVerificationRepository) IsEmailVerified(ctx context.Context, userID int64) (bool, error) {
+	return m.verified[userID], nil
+}
+
+var verifyLinkPattern = regexp.MustCompile(`token=([A-Za-z0-9_%-]+)`)
+
+func extractToken(t *testing.T, body string) string {
+	t.Helper()
+	match := verifyLinkPattern.FindStringSubmatch(body)
+	if match == nil {
+		t.Fatalf("Expected verification link in body, got %q", body)
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return token
+}
+
+func TestEmailVerificationFlow(t *testing.T) {
+	verificationRepo := newMockEmailVerificationRepository()
+	mailer := mail.NewMemoryMailer()
+	userRepo := &mockUserRepository{users: []domain.User{{ID: 1, Username: "alice", Email: "alice@example.com"}}}
+	usecase := NewEmailVerificationUsecase(verificationRepo, userRepo, mailer, "http://localhost:8080/", time.Hour)
+	ctx := context.Background()
+
+	if err := usecase.SendVerification(ctx, 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := usecase.SendVerification(ctx, 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	messages := mailer.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(messages))
+	}
+	if messages[0].To != "alice@example.com" {
+		t.Errorf("Expected mail to alice@example.com, got %s", messages[0].To)
+	}
+
+	// resending invalidates the earlier token
+	if err := usecase.ConfirmEmail(ctx, extractToken(t, messages[0].Body)); !errors.Is(err, domain.ErrInvalidVerificationToken) {
+		t.Errorf("Expected ErrInvalidVerificationToken for superseded token, got %v", err)
+	}
+
+	token := extractToken(t, messages[1].Body)
+	if err := usecase.ConfirmEmail(ctx, token); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !verificationRepo.verified[1] {
+		t.Error("Expected email to be verified")
+	}
+
+	// tokens are single-use
+	if err := usecase.ConfirmEmail(ctx, token); !errors.Is(err, domain.ErrInvalidVerificationToken) {
+		t.Errorf("Expected ErrInvalidVerificationToken for reused token, got %v", err)
+	}
+	if err := usecase.SendVerification(ctx, 1); !errors.Is(err, domain.ErrEmailAlreadyVerified) {
+		t.Errorf("Expected ErrEmailAlreadyVerified, got %v", err)
+	}
+}
+
+func TestConfirmEmailRejectsExpiredToken(t *testing.T) {
+	verificationRepo := newMockEmailVerificationRepository()
+	mailer := mail.NewMemoryMailer()
+	userRepo := &mockUserRepository{users: []domain.User{{ID: 1, Email: "alice@example.com"}}}
+	usecase := NewEmailVerificationUsecase(verificationRepo, userRepo, mailer, "http://localhost:8080", -time.Minute)
+	ctx := context.Background()
+
+	if err := usecase.SendVerification(ctx, 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := usecase.ConfirmEmail(ctx, extractToken(t, mailer.Messages()[0].Body)); !errors.Is(err, domain.ErrInvalidVerificationToken) {
+		t.Errorf("Expected ErrInvalidVerificationToken for expired token, got %v", err)
+	}
+}
+
+func TestUnverifiedUserCannotComment(t *testing.T) {
+	verificationRepo := newMockEmailVerificationRepository()
+	postRepo := newMockPostRepository()
+	postRepo.posts[1] = &domain.Post{ID: 1, UserID: 2, Status: domain.PostStatusPublished}
+	policy := NewPolicy(&mockRoleRepository{}, verificationRepo)
+	usecase := NewCommentUsecase(nil, postRepo, nil, policy)
+
+	if _, err := usecase.PostComment(viewerContext(1), 1, 1, nil, "hello"); !errors.Is(err, domain.ErrEmailNotVerified) {
+		t.Errorf("Expected ErrEmailNotVerified, got %v", err)
+	}
+}
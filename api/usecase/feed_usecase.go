@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// FeedUsecase defines business logic for the home timeline
+type FeedUsecase interface {
+	GetFeed(ctx context.Context, userID int64, page, pageSize int) ([]domain.PostWithDetails, error)
+}
+
+type feedUsecase struct {
+	feedRepo    domain.FeedRepository
+	userRepo    domain.UserRepository
+	likeRepo    domain.LikeRepository // LikedByMe の設定用（nilの場合は設定しない）
+	viewCounter domain.ViewCounter    // 未反映の閲覧数の加算用（nilの場合は加算しない）
+}
+
+// NewFeedUsecase creates a new feed usecase
+func NewFeedUsecase(feedRepo domain.FeedRepository, userRepo domain.UserRepository, likeRepo domain.LikeRepository, viewCounter domain.ViewCounter) FeedUsecase {
+	return &feedUsecase{
+		feedRepo:    feedRepo,
+		userRepo:    userRepo,
+		likeRepo:    likeRepo,
+		viewCounter: viewCounter,
+	}
+}
+
+// GetFeed returns recent published posts from the authors userID follows
+func (u *feedUsecase) GetFeed(ctx context.Context, userID int64, page, pageSize int) ([]domain.PostWithDetails, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	if _, err := u.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	offset := (page - 1) * pageSize
+
+	posts, err := u.feedRepo.FindFeed(ctx, userID, pageSize, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed: %w", err)
+	}
+	if posts == nil {
+		posts = []domain.PostWithDetails{}
+	}
+
+	addPendingViews(ctx, u.viewCounter, postPointers(posts)...)
+	if err := markLikedByViewer(ctx, u.likeRepo, postPointers(posts)...); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// Mock repository for testing
+type mockFeedRepository struct {
+	limit, offset int
+}
+
+func (m *mockFeedRepository) FindFeed(ctx context.Context, userID int64, limit, offset int) ([]domain.PostWithDetails, error) {
+	m.limit, m.offset = limit, offset
+	return nil, nil
+}
+
+func TestGetFeedPagination(t *testing.T) {
+	feedRepo := &mockFeedRepository{}
+	uc := NewFeedUsecase(feedRepo, &mockUserRepository{users: []domain.User{{ID: 1}}}, nil, nil)
+
+	posts, err := uc.GetFeed(context.Background(), 1, 3, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if posts == nil {
+		t.Errorf("Expected empty slice, got nil")
+	}
+
+	if feedRepo.limit != 10 || feedRepo.offset != 20 {
+		t.Errorf("Expected limit=10 offset=20, got limit=%d offset=%d", feedRepo.limit, feedRepo.offset)
+	}
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// FollowUsecase defines business logic for follow relations
+type FollowUsecase interface {
+	Follow(ctx context.Context, followerID, followingID int64) error
+	Unfollow(ctx context.Context, followerID, followingID int64) error
+	IsFollowing(ctx context.Context, followerID, followingID int64) (bool, error)
+	GetFollowers(ctx context.Context, userID int64, cursor string, limit int) ([]domain.FollowUser, string, error)
+	GetFollowing(ctx context.Context, userID int64, cursor string, limit int) ([]domain.FollowUser, string, error)
+}
+
+type followUsecase struct {
+	followRepo domain.FollowRepository
+	userRepo   domain.UserRepository
+	notifier   Notifier // nilの場合は通知しない
+	policy     Policy
+}
+
+// NewFollowUsecase creates a new follow usecase
+func NewFollowUsecase(followRepo domain.FollowRepository, userRepo domain.UserRepository, notifier Notifier, policy Policy) FollowUsecase {
+	return &followUsecase{
+		followRepo: followRepo,
+		userRepo:   userRepo,
+		notifier:   notifier,
+		policy:     policy,
+	}
+}
+
+// Follow makes followerID follow followingID (self-follows and duplicates are rejected)
+func (u *followUsecase) Follow(ctx context.Context, followerID, followingID int64) error {
+	if followerID == followingID {
+		return domain.ErrSelfFollow
+	}
+	if err := u.policy.RequireOwnerOr(ctx, followerID, domain.PermissionUsersManage); err != nil {
+		return err
+	}
+
+	if err := u.ensureUsersExist(ctx, followerID, followingID); err != nil {
+		return err
+	}
+
+	if err := u.followRepo.Create(ctx, followerID, followingID); err != nil {
+		return err
+	}
+
+	if u.notifier != nil {
+		u.notifier.NotifyFollow(ctx, followerID, followingID)
+	}
+
+	return nil
+}
+
+// Unfollow removes the follow relation
+func (u *followUsecase) Unfollow(ctx context.Context, followerID, followingID int64) error {
+	if followerID == followingID {
+		return domain.ErrSelfFollow
+	}
+	if err := u.policy.RequireOwnerOr(ctx, followerID, domain.PermissionUsersManage); err != nil {
+		return err
+	}
+
+	return u.followRepo.Delete(ctx, followerID, followingID)
+}
+
+// IsFollowing reports whether followerID follows followingID
+func (u *followUsecase) IsFollowing(ctx context.Context, followerID, followingID int64) (bool, error) {
+	return u.followRepo.Exists(ctx, followerID, followingID)
+}
+
+// GetFollowers returns users following userID and the cursor for the next page ("" if none)
+func (u *followUsecase) GetFollowers(ctx context.Context, userID int64, cursor string, limit int) ([]domain.FollowUser, string, error) {
+	return u.list(ctx, userID, cursor, limit, u.followRepo.FindFollowers)
+}
+
+// GetFollowing returns users followed by userID and the cursor for the next page ("" if none)
+func (u *followUsecase) GetFollowing(ctx context.Context, userID int64, cursor string, limit int) ([]domain.FollowUser, string, error) {
+	return u.list(ctx, userID, cursor, limit, u.followRepo.FindFollowing)
+}
+
+type findFollowsFunc func(ctx context.Context, userID int64, after *domain.FollowCursor, limit int) ([]domain.FollowUser, error)
+
+func (u *followUsecase) list(ctx context.Context, userID int64, cursor string, limit int, find findFollowsFunc) ([]domain.FollowUser, string, error) {
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	after, err := decodeFollowCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if _, err := u.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 1件多く取得して次ページの有無を判定
+	users, err := find(ctx, userID, after, limit+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get follows: %w", err)
+	}
+
+	next := ""
+	if len(users) > limit {
+		users = users[:limit]
+		last := users[len(users)-1]
+		next = encodeFollowCursor(domain.FollowCursor{FollowedAt: last.FollowedAt, UserID: last.ID})
+	}
+	if users == nil {
+		users = []domain.FollowUser{}
+	}
+
+	return users, next, nil
+}
+
+func (u *followUsecase) ensureUsersExist(ctx context.Context, ids ...int64) error {
+	for _, id := range ids {
+		if _, err := u.userRepo.FindByID(ctx, id); err != nil {
+			return fmt.Errorf("failed to get user %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// encodeFollowCursor はカーソルを不透明な文字列（base64url）に変換します
+func encodeFollowCursor(cursor domain.FollowCursor) string {
+	raw := fmt.Sprintf("%d:%d", cursor.FollowedAt.UnixNano(), cursor.UserID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeFollowCursor(cursor string) (*domain.FollowCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidCursor)
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidCursor)
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidCursor)
+	}
+	userID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidCursor)
+	}
+
+	return &domain.FollowCursor{FollowedAt: time.Unix(0, nanos), UserID: userID}, nil
+}
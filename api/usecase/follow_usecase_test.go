@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// Mock repository for testing
+type mockFollowRepository struct {
+	follows []mockFollow
+	now     time.Time
+}
+
+type mockFollow struct {
+	followerID, followingID int64
+	at                      time.Time
+}
+
+func (m *mockFollowRepository) Create(ctx context.Context, followerID, followingID int64) error {
+	if ok, _ := m.Exists(ctx, followerID, followingID); ok {
+		return domain.ErrAlreadyFollowing
+	}
+	// 同じ時刻のフォローを含めるため、2件ごとに時刻を進める
+	if len(m.follows)%2 == 0 {
+		m.now = m.now.Add(time.Second)
+	}
+	m.follows = append(m.follows, mockFollow{followerID, followingID, m.now})
+	return nil
+}
+
+func (m *mockFollowRepository) Delete(ctx context.Context, followerID, followingID int64) error {
+	for i, f := range m.follows {
+		if f.followerID == followerID && f.followingID == followingID {
+			m.follows = append(m.follows[:i], m.follows[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrNotFollowing
+}
+
+func (m *mockFollowRepository) Exists(ctx context.Context, followerID, followingID int64) (bool, error) {
+	for _, f := range m.follows {
+		if f.followerID == followerID && f.followingID == followingID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockFollowRepository) FindFollowers(ctx context.Context, userID int64, after *domain.FollowCursor, limit int) ([]domain.FollowUser, error) {
+	var users []domain.FollowUser
+	for _, f := range m.follows {
+		if f.followingID == userID {
+			users = append(users, domain.FollowUser{ID: f.followerID, FollowedAt: f.at})
+		}
+	}
+	return pageFollowUsers(users, after, limit), nil
+}
+
+func (m *mockFollowRepository) FindFollowing(ctx context.Context, userID int64, after *domain.FollowCursor, limit int) ([]domain.FollowUser, error) {
+	var users []domain.FollowUser
+	for _, f := range m.follows {
+		if f.followerID == userID {
+			users = append(users, domain.FollowUser{ID: f.followingID, FollowedAt: f.at})
+		}
+	}
+	return pageFollowUsers(users, after, limit), nil
+}
+
+func (m *mockFollowRepository) FindFollowerIDs(ctx context.Context, userID int64) ([]int64, error) {
+	var ids []int64
+	for _, f := range m.follows {
+		if f.followingID == userID {
+			ids = append(ids, f.followerID)
+		}
+	}
+	return ids, nil
+}
+
+// pageFollowUsers はMySQLと同じ followedAt DESC, id DESC の順でカーソルより後ろを返します
+func pageFollowUsers(users []domain.FollowUser, after *domain.FollowCursor, limit int) []domain.FollowUser {
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].FollowedAt.Equal(users[j].FollowedAt) {
+			return users[i].FollowedAt.After(users[j].FollowedAt)
+		}
+		return users[i].ID > users[j].ID
+	})
+
+	var page []domain.FollowUser
+	for _, user := range users {
+		if after != nil && (user.FollowedAt.After(after.FollowedAt) ||
+			user.FollowedAt.Equal(after.FollowedAt) && user.ID >= after.UserID) {
+			continue
+		}
+		page = append(page, user)
+	}
+	if len(page) > limit {
+		page = page[:limit]
+	}
+	return page
+}
+
+func newFollowTestUsecase(userCount int) (FollowUsecase, *mockFollowRepository) {
+	userRepo := &mockUserRepository{}
+	for id := int64(1); id <= int64(userCount); id++ {
+		userRepo.users = append(userRepo.users, domain.User{ID: id})
+	}
+	followRepo := &mockFollowRepository{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	return NewFollowUsecase(followRepo, userRepo, nil, allowAllPolicy{}), followRepo
+}
+
+func TestFollowRejectsSelfFollowAndDuplicates(t *testing.T) {
+	uc, followRepo := newFollowTestUsecase(2)
+	ctx := context.Background()
+
+	if err := uc.Follow(ctx, 1, 1); !errors.Is(err, domain.ErrSelfFollow) {
+		t.Errorf("Expected ErrSelfFollow, got %v", err)
+	}
+	if err := uc.Unfollow(ctx, 2, 2); !errors.Is(err, domain.ErrSelfFollow) {
+		t.Errorf("Expected ErrSelfFollow on unfollow, got %v", err)
+	}
+	if len(followRepo.follows) != 0 {
+		t.Fatalf("Expected no follow relation, got %d", len(followRepo.follows))
+	}
+
+	if err := uc.Follow(ctx, 1, 2); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := uc.Follow(ctx, 1, 2); !errors.Is(err, domain.ErrAlreadyFollowing) {
+		t.Errorf("Expected ErrAlreadyFollowing, got %v", err)
+	}
+	if err := uc.Follow(ctx, 1, 3); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown user, got %v", err)
+	}
+
+	if err := uc.Unfollow(ctx, 1, 2); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := uc.Unfollow(ctx, 1, 2); !errors.Is(err, domain.ErrNotFollowing) {
+		t.Errorf("Expected ErrNotFollowing, got %v", err)
+	}
+}
+
+func TestGetFollowersPagesWithCursor(t *testing.T) {
+	uc, _ := newFollowTestUsecase(8)
+	ctx := context.Background()
+
+	// ユーザー2〜8がユーザー1をフォロー（2件ずつ同じ時刻）
+	for id := int64(2); id <= 8; id++ {
+		if err := uc.Follow(ctx, id, 1); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	var got []int64
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 4 {
+			t.Fatalf("Expected the cursor to end, got %q", cursor)
+		}
+		users, next, err := uc.GetFollowers(ctx, 1, cursor, 3)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if next != "" && len(users) != 3 {
+			t.Errorf("Expected a full page before the last one, got %d", len(users))
+		}
+		for _, user := range users {
+			got = append(got, user.ID)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	want := []int64{8, 7, 6, 5, 4, 3, 2}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected followers %v, got %v", want, got)
+	}
+
+	following, next, err := uc.GetFollowing(ctx, 2, "", 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(following) != 1 || following[0].ID != 1 || next != "" {
+		t.Errorf("Expected user 2 to follow only user 1 with no next page, got %+v next=%q", following, next)
+	}
+
+	if _, _, err := uc.GetFollowers(ctx, 1, "not-a-cursor", 3); !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestFollowRejectsSelfFollow(t *testing.T) {
+	uc := NewFollowUsecase(nil, &mockUserRepository{}, nil, allowAllPolicy{})
+
+	err := uc.Follow(context.Background(), 1, 1)
+	if !errors.Is(err, domain.ErrSelfFollow) {
+		t.Fatalf("Expected ErrSelfFollow, got %v", err)
+	}
+}
+
+func TestFollowCursorRoundTrip(t *testing.T) {
+	want := domain.FollowCursor{
+		FollowedAt: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		UserID:     42,
+	}
+
+	got, err := decodeFollowCursor(encodeFollowCursor(want))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !got.FollowedAt.Equal(want.FollowedAt) || got.UserID != want.UserID {
+		t.Errorf("Expected %+v, got %+v", want, *got)
+	}
+
+	if _, err := decodeFollowCursor("not-a-cursor"); !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// LikeUsecase defines business logic for likes on posts and comments
+type LikeUsecase interface {
+	LikePost(ctx context.Context, postID, userID int64) (*domain.LikeStatus, error)
+	UnlikePost(ctx context.Context, postID, userID int64) (*domain.LikeStatus, error)
+	LikeComment(ctx context.Context, commentID, userID int64) (*domain.LikeStatus, error)
+	UnlikeComment(ctx context.Context, commentID, userID int64) (*domain.LikeStatus, error)
+	GetPostLikers(ctx context.Context, postID int64, page, pageSize int) ([]domain.Liker, error)
+	GetCommentLikers(ctx context.Context, commentID int64, page, pageSize int) ([]domain.Liker, error)
+}
+
+type likeUsecase struct {
+	likeRepo    domain.LikeRepository
+	postRepo    domain.PostRepository
+	commentRepo domain.CommentRepository
+	userRepo    domain.UserRepository
+	notifier    Notifier // nilの場合は通知しない
+	policy      Policy
+}
+
+// NewLikeUsecase creates a new like usecase
+func NewLikeUsecase(likeRepo domain.LikeRepository, postRepo domain.PostRepository, commentRepo domain.CommentRepository, userRepo domain.UserRepository, notifier Notifier, policy Policy) LikeUsecase {
+	return &likeUsecase{
+		likeRepo:    likeRepo,
+		postRepo:    postRepo,
+		commentRepo: commentRepo,
+		userRepo:    userRepo,
+		notifier:    notifier,
+		policy:      policy,
+	}
+}
+
+// LikePost likes a published post (liking twice is a no-op)
+func (u *likeUsecase) LikePost(ctx context.Context, postID, userID int64) (*domain.LikeStatus, error) {
+	if err := u.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	post, err := u.postRepo.FindByID(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+	if post.Status != domain.PostStatusPublished {
+		return nil, fmt.Errorf("%w: post %d is not published", domain.ErrInvalidLikeInput, postID)
+	}
+
+	status, err := u.likeRepo.Like(ctx, userID, domain.LikeableTypePost, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 既にいいね済みの場合は再通知しない
+	if status.Changed && u.notifier != nil {
+		u.notifier.NotifyPostLiked(ctx, userID, postID)
+	}
+
+	return status, nil
+}
+
+// UnlikePost removes a like from a post (unliking twice is a no-op)
+func (u *likeUsecase) UnlikePost(ctx context.Context, postID, userID int64) (*domain.LikeStatus, error) {
+	if err := u.authorizeActor(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return u.likeRepo.Unlike(ctx, userID, domain.LikeableTypePost, postID)
+}
+
+// LikeComment likes an approved comment (liking twice is a no-op)
+func (u *likeUsecase) LikeComment(ctx context.Context, commentID, userID int64) (*domain.LikeStatus, error) {
+	if err := u.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	comment, err := u.commentRepo.FindByID(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	if comment.Status != domain.CommentStatusApproved {
+		return nil, fmt.Errorf("%w: comment %d is not approved", domain.ErrInvalidLikeInput, commentID)
+	}
+
+	status, err := u.likeRepo.Like(ctx, userID, domain.LikeableTypeComment, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if status.Changed && u.notifier != nil {
+		u.notifier.NotifyCommentLiked(ctx, userID, commentID)
+	}
+
+	return status, nil
+}
+
+// UnlikeComment removes a like from a comment (unliking twice is a no-op)
+func (u *likeUsecase) UnlikeComment(ctx context.Context, commentID, userID int64) (*domain.LikeStatus, error) {
+	if err := u.authorizeActor(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return u.likeRepo.Unlike(ctx, userID, domain.LikeableTypeComment, commentID)
+}
+
+// GetPostLikers returns users who liked the post, newest first
+func (u *likeUsecase) GetPostLikers(ctx context.Context, postID int64, page, pageSize int) ([]domain.Liker, error) {
+	if _, err := u.postRepo.FindByID(ctx, postID); err != nil {
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	return u.findLikers(ctx, domain.LikeableTypePost, postID, page, pageSize)
+}
+
+// GetCommentLikers returns users who liked the comment, newest first
+func (u *likeUsecase) GetCommentLikers(ctx context.Context, commentID int64, page, pageSize int) ([]domain.Liker, error) {
+	if _, err := u.commentRepo.FindByID(ctx, commentID); err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	return u.findLikers(ctx, domain.LikeableTypeComment, commentID, page, pageSize)
+}
+
+func (u *likeUsecase) findLikers(ctx context.Context, likeableType string, likeableID int64, page, pageSize int) ([]domain.Liker, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+
+	likers, err := u.likeRepo.FindLikers(ctx, likeableType, likeableID, pageSize, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get likes: %w", err)
+	}
+	if likers == nil {
+		likers = []domain.Liker{}
+	}
+
+	return likers, nil
+}
+
+// authorizeActor はuserIDが指定され、閲覧者本人（またはusers.manage権限を持つユーザー）であることを確認します
+func (u *likeUsecase) authorizeActor(ctx context.Context, userID int64) error {
+	if userID <= 0 {
+		return fmt.Errorf("%w: userId is required", domain.ErrInvalidLikeInput)
+	}
+	return u.policy.RequireOwnerOr(ctx, userID, domain.PermissionUsersManage)
+}
+
+func (u *likeUsecase) ensureUser(ctx context.Context, userID int64) error {
+	if err := u.authorizeActor(ctx, userID); err != nil {
+		return err
+	}
+	if _, err := u.userRepo.FindByID(ctx, userID); err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	return nil
+}
+
+// markLikedByViewer はリクエストしたユーザーがいいね済みの投稿にLikedByMeを設定します。
+// キャッシュされた投稿は閲覧者に依存しないため、取得後に毎回設定する
+func markLikedByViewer(ctx context.Context, likeRepo domain.LikeRepository, posts ...*domain.PostWithDetails) error {
+	viewerID, ok := domain.ViewerIDFromContext(ctx)
+	if !ok || likeRepo == nil || len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+
+	liked, err := likeRepo.FindLikedIDs(ctx, viewerID, domain.LikeableTypePost, ids)
+	if err != nil {
+		return fmt.Errorf("failed to load likes: %w", err)
+	}
+
+	for _, post := range posts {
+		post.LikedByMe = liked[post.ID]
+	}
+
+	return nil
+}
+
+func postPointers(posts []domain.PostWithDetails) []*domain.PostWithDetails {
+	ptrs := make([]*domain.PostWithDetails, len(posts))
+	for i := range posts {
+		ptrs[i] = &posts[i]
+	}
+	return ptrs
+}
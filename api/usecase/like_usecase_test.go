@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// Mock repository for testing
+type mockLikeRepository struct {
+	likes map[int64]map[int64]bool // userID -> postID
+}
+
+func (m *mockLikeRepository) Like(ctx context.Context, userID int64, likeableType string, likeableID int64) (*domain.LikeStatus, error) {
+	if m.likes[userID] == nil {
+		m.likes[userID] = make(map[int64]bool)
+	}
+	m.likes[userID][likeableID] = true
+	return &domain.LikeStatus{Liked: true, LikeCount: 1}, nil
+}
+
+func (m *mockLikeRepository) Unlike(ctx context.Context, userID int64, likeableType string, likeableID int64) (*domain.LikeStatus, error) {
+	delete(m.likes[userID], likeableID)
+	return &domain.LikeStatus{Liked: false}, nil
+}
+
+func (m *mockLikeRepository) FindLikers(ctx context.Context, likeableType string, likeableID int64, limit, offset int) ([]domain.Liker, error) {
+	return nil, nil
+}
+
+func (m *mockLikeRepository) FindLikedIDs(ctx context.Context, userID int64, likeableType string, likeableIDs []int64) (map[int64]bool, error) {
+	liked := make(map[int64]bool)
+	for _, id := range likeableIDs {
+		if m.likes[userID][id] {
+			liked[id] = true
+		}
+	}
+	return liked, nil
+}
+
+func TestLikePostRejectsUnpublishedPost(t *testing.T) {
+	postRepo := newMockPostRepository()
+	postRepo.posts[1] = &domain.Post{ID: 1, Status: domain.PostStatusDraft}
+	likeRepo := &mockLikeRepository{likes: make(map[int64]map[int64]bool)}
+	uc := NewLikeUsecase(likeRepo, postRepo, nil, &mockUserRepository{users: []domain.User{{ID: 7}}}, nil, allowAllPolicy{})
+
+	_, err := uc.LikePost(context.Background(), 1, 7)
+	if !errors.Is(err, domain.ErrInvalidLikeInput) {
+		t.Fatalf("Expected ErrInvalidLikeInput, got %v", err)
+	}
+}
+
+func TestMarkLikedByViewer(t *testing.T) {
+	likeRepo := &mockLikeRepository{likes: map[int64]map[int64]bool{7: {2: true}}}
+	posts := []domain.PostWithDetails{
+		{Post: domain.Post{ID: 1}},
+		{Post: domain.Post{ID: 2}},
+	}
+
+	// 匿名リクエストでは設定しない
+	if err := markLikedByViewer(context.Background(), likeRepo, postPointers(posts)...); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if posts[1].LikedByMe {
+		t.Errorf("Expected likedByMe=false for anonymous viewer")
+	}
+
+	ctx := domain.ContextWithAuthUser(context.Background(), &domain.AuthUser{ID: 7})
+	if err := markLikedByViewer(ctx, likeRepo, postPointers(posts)...); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if posts[0].LikedByMe || !posts[1].LikedByMe {
+		t.Errorf("Expected only post 2 to be liked, got %v, %v", posts[0].LikedByMe, posts[1].LikedByMe)
+	}
+}
@@ -0,0 +1,345 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// NotificationUsecase defines business logic for the notification inbox
+type NotificationUsecase interface {
+	GetNotifications(ctx context.Context, userID int64, filter string, page, pageSize int) ([]domain.UserNotification, int64, error)
+	MarkAsRead(ctx context.Context, userID, id int64) (*domain.UserNotification, error)
+	MarkAllAsRead(ctx context.Context, userID int64) (int64, error)
+	StreamNotifications(ctx context.Context, userID, lastEventID int64) (<-chan domain.UserNotification, error)
+}
+
+type notificationUsecase struct {
+	notificationRepo domain.NotificationRepository
+	userRepo         domain.UserRepository
+	broker           domain.NotificationBroker
+	policy           Policy
+}
+
+// NewNotificationUsecase creates a new notification usecase
+func NewNotificationUsecase(notificationRepo domain.NotificationRepository, userRepo domain.UserRepository, broker domain.NotificationBroker, policy Policy) NotificationUsecase {
+	return &notificationUsecase{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		broker:           broker,
+		policy:           policy,
+	}
+}
+
+// GetNotifications returns a page of userID's notifications (all/unread/read) and the matching total
+func (u *notificationUsecase) GetNotifications(ctx context.Context, userID int64, filter string, page, pageSize int) ([]domain.UserNotification, int64, error) {
+	if filter == "" {
+		filter = domain.NotificationFilterAll
+	}
+	if filter != domain.NotificationFilterAll && filter != domain.NotificationFilterUnread && filter != domain.NotificationFilterRead {
+		return nil, 0, fmt.Errorf("%w: filter must be all, unread or read", domain.ErrInvalidNotificationInput)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	if err := u.policy.RequireOwnerOr(ctx, userID, domain.PermissionUsersManage); err != nil {
+		return nil, 0, err
+	}
+
+	if _, err := u.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, 0, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	offset := (page - 1) * pageSize
+
+	notifications, err := u.notificationRepo.FindByUserID(ctx, userID, filter, pageSize, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	if notifications == nil {
+		notifications = []domain.UserNotification{}
+	}
+
+	total, err := u.notificationRepo.CountByUserID(ctx, userID, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	return notifications, total, nil
+}
+
+// MarkAsRead marks one of userID's notifications read
+func (u *notificationUsecase) MarkAsRead(ctx context.Context, userID, id int64) (*domain.UserNotification, error) {
+	if err := u.policy.RequireOwnerOr(ctx, userID, domain.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+
+	if err := u.notificationRepo.MarkRead(ctx, userID, id); err != nil {
+		return nil, fmt.Errorf("failed to mark notification read: %w", err)
+	}
+
+	return u.notificationRepo.FindByID(ctx, userID, id)
+}
+
+// MarkAllAsRead marks all of userID's unread notifications read and returns how many changed
+func (u *notificationUsecase) MarkAllAsRead(ctx context.Context, userID int64) (int64, error) {
+	if err := u.policy.RequireOwnerOr(ctx, userID, domain.PermissionUsersManage); err != nil {
+		return 0, err
+	}
+
+	if _, err := u.userRepo.FindByID(ctx, userID); err != nil {
+		return 0, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	updated, err := u.notificationRepo.MarkAllRead(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+
+	return updated, nil
+}
+
+// streamBackfillBatch は再接続時に1回のクエリで取得する取りこぼし通知の件数
+const streamBackfillBatch = 100
+
+// StreamNotifications returns userID's notifications created after lastEventID followed by new ones as they arrive.
+// The channel is closed when ctx is done or the live subscription ends.
+func (u *notificationUsecase) StreamNotifications(ctx context.Context, userID, lastEventID int64) (<-chan domain.UserNotification, error) {
+	if lastEventID < 0 {
+		return nil, fmt.Errorf("%w: invalid Last-Event-ID", domain.ErrInvalidNotificationInput)
+	}
+
+	if err := u.policy.RequireOwnerOr(ctx, userID, domain.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 取りこぼしを防ぐため、DBから過去分を読む前に購読を開始する
+	live, err := u.broker.Subscribe(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe notifications: %w", err)
+	}
+
+	out := make(chan domain.UserNotification)
+	go func() {
+		defer close(out)
+
+		send := func(notification domain.UserNotification) bool {
+			select {
+			case out <- notification:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		lastSent := lastEventID
+		if lastEventID > 0 {
+			for {
+				missed, err := u.notificationRepo.FindAfterID(ctx, userID, lastSent, streamBackfillBatch)
+				if err != nil {
+					return
+				}
+				for _, notification := range missed {
+					if !send(notification) {
+						return
+					}
+					lastSent = notification.ID
+				}
+				if len(missed) < streamBackfillBatch {
+					break
+				}
+			}
+		}
+
+		for notification := range live {
+			// 過去分として送信済みのものは重複させない
+			if notification.ID <= lastSent {
+				continue
+			}
+			if !send(notification) {
+				return
+			}
+			lastSent = notification.ID
+		}
+	}()
+
+	return out, nil
+}
+
+// Notifier creates notifications when follow, like, comment and mention events happen.
+// 通知の作成に失敗しても元の操作は成功させる（ログのみ）
+type Notifier interface {
+	NotifyFollow(ctx context.Context, followerID, followingID int64)
+	NotifyPostLiked(ctx context.Context, userID, postID int64)
+	NotifyCommentLiked(ctx context.Context, userID, commentID int64)
+	NotifyCommentApproved(ctx context.Context, comment *domain.Comment)
+}
+
+type notifier struct {
+	notificationRepo domain.NotificationRepository
+	userRepo         domain.UserRepository
+	postRepo         domain.PostRepository
+	commentRepo      domain.CommentRepository
+	broker           domain.NotificationBroker // nilの場合はリアルタイム配信しない
+}
+
+// NewNotifier creates a new notifier
+func NewNotifier(notificationRepo domain.NotificationRepository, userRepo domain.UserRepository, postRepo domain.PostRepository, commentRepo domain.CommentRepository, broker domain.NotificationBroker) Notifier {
+	return &notifier{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		postRepo:         postRepo,
+		commentRepo:      commentRepo,
+		broker:           broker,
+	}
+}
+
+// NotifyFollow notifies followingID of a new follower
+func (n *notifier) NotifyFollow(ctx context.Context, followerID, followingID int64) {
+	actor, ok := n.actor(ctx, followerID)
+	if !ok {
+		return
+	}
+
+	n.send(ctx, followingID, domain.NotificationTypeFollow, "新しいフォロワー",
+		fmt.Sprintf("%sさんがあなたをフォローしました", actor.Username),
+		fmt.Sprintf("/users/%d", followerID))
+}
+
+// NotifyPostLiked notifies the post author of a new like
+func (n *notifier) NotifyPostLiked(ctx context.Context, userID, postID int64) {
+	post, err := n.postRepo.FindByID(ctx, postID)
+	if err != nil || post.UserID == userID {
+		return
+	}
+
+	actor, ok := n.actor(ctx, userID)
+	if !ok {
+		return
+	}
+
+	n.send(ctx, post.UserID, domain.NotificationTypeLike, "新しいいいね",
+		fmt.Sprintf("%sさんがあなたの投稿にいいねしました", actor.Username),
+		fmt.Sprintf("/posts/%d", postID))
+}
+
+// NotifyCommentLiked notifies the comment author of a new like
+func (n *notifier) NotifyCommentLiked(ctx context.Context, userID, commentID int64) {
+	comment, err := n.commentRepo.FindByID(ctx, commentID)
+	if err != nil || comment.UserID == userID {
+		return
+	}
+
+	actor, ok := n.actor(ctx, userID)
+	if !ok {
+		return
+	}
+
+	n.send(ctx, comment.UserID, domain.NotificationTypeLike, "新しいいいね",
+		fmt.Sprintf("%sさんがあなたのコメントにいいねしました", actor.Username),
+		fmt.Sprintf("/posts/%d#comment-%d", comment.PostID, comment.ID))
+}
+
+// NotifyCommentApproved notifies the post author, the parent comment author and mentioned users.
+// コメントは承認されて公開された時点で通知する（pending/spamでは通知しない）
+func (n *notifier) NotifyCommentApproved(ctx context.Context, comment *domain.Comment) {
+	actor, ok := n.actor(ctx, comment.UserID)
+	if !ok {
+		return
+	}
+
+	link := fmt.Sprintf("/posts/%d#comment-%d", comment.PostID, comment.ID)
+	notified := map[int64]bool{comment.UserID: true}
+
+	if comment.ParentID != nil {
+		if parent, err := n.commentRepo.FindByID(ctx, *comment.ParentID); err == nil && !notified[parent.UserID] {
+			notified[parent.UserID] = true
+			n.send(ctx, parent.UserID, domain.NotificationTypeComment, "新しい返信",
+				fmt.Sprintf("%sさんがあなたのコメントに返信しました", actor.Username), link)
+		}
+	}
+
+	if post, err := n.postRepo.FindByID(ctx, comment.PostID); err == nil && !notified[post.UserID] {
+		notified[post.UserID] = true
+		n.send(ctx, post.UserID, domain.NotificationTypeComment, "新しいコメント",
+			fmt.Sprintf("%sさんがあなたの投稿にコメントしました", actor.Username), link)
+	}
+
+	usernames := extractMentions(comment.Content)
+	if len(usernames) == 0 {
+		return
+	}
+
+	mentioned, err := n.userRepo.FindByUsernames(ctx, usernames)
+	if err != nil {
+		log.Printf("Warning: failed to resolve mentions in comment %d: %v", comment.ID, err)
+		return
+	}
+	for _, user := range mentioned {
+		if notified[user.ID] {
+			continue
+		}
+		notified[user.ID] = true
+		n.send(ctx, user.ID, domain.NotificationTypeMention, "メンション",
+			fmt.Sprintf("%sさんがコメントであなたをメンションしました", actor.Username), link)
+	}
+}
+
+func (n *notifier) actor(ctx context.Context, userID int64) (*domain.User, bool) {
+	user, err := n.userRepo.FindByID(ctx, userID)
+	if err != nil || user == nil {
+		log.Printf("Warning: notification skipped, failed to get user %d: %v", userID, err)
+		return nil, false
+	}
+	return user, true
+}
+
+func (n *notifier) send(ctx context.Context, userID int64, notificationType, title, message, link string) {
+	notification := &domain.UserNotification{
+		Type:    notificationType,
+		Title:   title,
+		Message: message,
+		LinkURL: &link,
+	}
+	if err := n.notificationRepo.Create(ctx, userID, notification); err != nil {
+		log.Printf("Warning: failed to create %s notification for user %d: %v", notificationType, userID, err)
+		return
+	}
+
+	// 配信に失敗しても通知はDBに残るため、クライアントは再接続時に取得できる
+	if n.broker != nil {
+		if err := n.broker.Publish(ctx, userID, notification); err != nil {
+			log.Printf("Warning: failed to publish notification %d: %v", notification.ID, err)
+		}
+	}
+}
+
+// mentionPattern は @username 形式のメンション（users.username は英数字と_）
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_@])@([A-Za-z0-9_]{1,50})`)
+
+// extractMentions はコメント本文からメンションされたユーザー名を重複なく抽出します
+func extractMentions(content string) []string {
+	seen := make(map[string]bool)
+	var usernames []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		username := match[1]
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+func TestExtractMentions(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []string
+	}{
+		{"@sakura さん、ありがとう", []string{"sakura"}},
+		{"cc @takeshi @yuki @takeshi", []string{"takeshi", "yuki"}},
+		{"mail me at test@example.com", nil},
+		{"no mentions here", nil},
+	}
+
+	for _, tt := range tests {
+		got := extractMentions(tt.content)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("extractMentions(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+// Mock repository for testing
+type mockNotificationRepository struct {
+	notifications []domain.UserNotification
+}
+
+func (m *mockNotificationRepository) Create(ctx context.Context, userID int64, notification *domain.UserNotification) error {
+	notification.ID = int64(len(m.notifications) + 1)
+	m.notifications = append(m.notifications, *notification)
+	return nil
+}
+
+func (m *mockNotificationRepository) FindByUserID(ctx context.Context, userID int64, filter string, limit, offset int) ([]domain.UserNotification, error) {
+	return m.notifications, nil
+}
+
+func (m *mockNotificationRepository) CountByUserID(ctx context.Context, userID int64, filter string) (int64, error) {
+	return int64(len(m.notifications)), nil
+}
+
+func (m *mockNotificationRepository) FindByID(ctx context.Context, userID, id int64) (*domain.UserNotification, error) {
+	for _, notification := range m.notifications {
+		if notification.ID == id {
+			return &notification, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockNotificationRepository) MarkRead(ctx context.Context, userID, id int64) error {
+	return nil
+}
+
+func (m *mockNotificationRepository) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockNotificationRepository) FindAfterID(ctx context.Context, userID, afterID int64, limit int) ([]domain.UserNotification, error) {
+	var found []domain.UserNotification
+	for _, notification := range m.notifications {
+		if notification.ID > afterID && len(found) < limit {
+			found = append(found, notification)
+		}
+	}
+	return found, nil
+}
+
+// Mock broker for testing
+type mockNotificationBroker struct {
+	live chan domain.UserNotification
+}
+
+func (m *mockNotificationBroker) Publish(ctx context.Context, userID int64, notification *domain.UserNotification) error {
+	m.live <- *notification
+	return nil
+}
+
+func (m *mockNotificationBroker) Subscribe(ctx context.Context, userID int64) (<-chan domain.UserNotification, error) {
+	return m.live, nil
+}
+
+func TestStreamNotificationsResumesFromLastEventID(t *testing.T) {
+	repo := &mockNotificationRepository{notifications: []domain.UserNotification{{ID: 1}, {ID: 2}, {ID: 3}}}
+	broker := &mockNotificationBroker{live: make(chan domain.UserNotification, 4)}
+	uc := NewNotificationUsecase(repo, &mockUserRepository{users: []domain.User{{ID: 1}}}, broker, allowAllPolicy{})
+
+	// 購読開始後に届いたイベントのうち、過去分と重複するもの（ID 3）は送らない
+	broker.live <- domain.UserNotification{ID: 3}
+	broker.live <- domain.UserNotification{ID: 4}
+	close(broker.live)
+
+	events, err := uc.StreamNotifications(context.Background(), 1, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var ids []int64
+	for notification := range events {
+		ids = append(ids, notification.ID)
+	}
+
+	if !reflect.DeepEqual(ids, []int64{2, 3, 4}) {
+		t.Errorf("Expected notifications [2 3 4], got %v", ids)
+	}
+}
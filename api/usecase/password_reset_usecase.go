@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+const (
+	// 同一メールアドレス宛てのリセット要求は1時間に3回まで
+	passwordResetEmailLimit = 3
+	// 同一IPからのリセット要求は1時間に10回まで（メールアドレスを変えた総当たり対策）
+	passwordResetIPLimit    = 10
+	passwordResetRateWindow = time.Hour
+)
+
+// PasswordResetUsecase defines business logic for resetting forgotten passwords
+type PasswordResetUsecase interface {
+	RequestReset(ctx context.Context, email, clientIP string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+type passwordResetUsecase struct {
+	resetRepo  domain.PasswordResetRepository
+	authRepo   domain.AuthRepository
+	hasher     domain.PasswordHasher
+	tokenStore domain.RefreshTokenStore
+	mailer     domain.Mailer
+	limiter    domain.RateLimiter // nilの場合はレート制限しない
+	resetURL   string             // メールのリンク先（新しいパスワードを入力する画面。例: https://app.example.com/reset-password）
+	ttl        time.Duration
+}
+
+// NewPasswordResetUsecase creates a new password reset usecase
+func NewPasswordResetUsecase(resetRepo domain.PasswordResetRepository, authRepo domain.AuthRepository, hasher domain.PasswordHasher, tokenStore domain.RefreshTokenStore, mailer domain.Mailer, limiter domain.RateLimiter, resetURL string, ttl time.Duration) PasswordResetUsecase {
+	return &passwordResetUsecase{
+		resetRepo:  resetRepo,
+		authRepo:   authRepo,
+		hasher:     hasher,
+		tokenStore: tokenStore,
+		mailer:     mailer,
+		limiter:    limiter,
+		resetURL:   resetURL,
+		ttl:        ttl,
+	}
+}
+
+// RequestReset emails a single-use reset link if the address belongs to an active account.
+// Unknown addresses succeed silently so the endpoint cannot be used to enumerate accounts
+func (u *passwordResetUsecase) RequestReset(ctx context.Context, email, clientIP string) error {
+	email = strings.TrimSpace(email)
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return fmt.Errorf("%w: email is invalid", domain.ErrInvalidAuthInput)
+	}
+
+	if err := u.checkRateLimit(ctx, "password_reset:ip:"+clientIP, passwordResetIPLimit); err != nil {
+		return err
+	}
+	if err := u.checkRateLimit(ctx, "password_reset:email:"+strings.ToLower(email), passwordResetEmailLimit); err != nil {
+		return err
+	}
+
+	// FindCredentialsはユーザー名でも一致するため、メールアドレスとして一致した場合のみ送信する
+	creds, err := u.authRepo.FindCredentials(ctx, email)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(creds.Email, email) {
+		return nil
+	}
+	if creds.Status == domain.UserStatusSuspended || creds.Status == domain.UserStatusDeleted {
+		log.Printf("password reset requested for disabled user %d", creds.UserID)
+		return nil
+	}
+
+	token, err := newSecureToken()
+	if err != nil {
+		return err
+	}
+
+	record := &domain.PasswordResetToken{
+		UserID:    creds.UserID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(u.ttl),
+	}
+	if err := u.resetRepo.Create(ctx, record); err != nil {
+		return err
+	}
+
+	// リンク先の画面がトークンと新しいパスワードを POST /auth/password/reset に送る
+	link, err := url.Parse(u.resetURL)
+	if err != nil {
+		return fmt.Errorf("invalid password reset URL: %w", err)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return u.mailer.Send(ctx, domain.EmailMessage{
+		To:      creds.Email,
+		Subject: "パスワードの再設定",
+		Body: fmt.Sprintf("%sさん\n\n以下のリンクからパスワードを再設定してください（%s以内に有効）。\n\n%s\n\n心当たりがない場合はこのメールを破棄してください。パスワードは変更されません。\n",
+			creds.Username, u.ttl, link.String()),
+	})
+}
+
+// ResetPassword consumes the token, sets the new password and revokes every session of the account
+func (u *passwordResetUsecase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return domain.ErrInvalidResetToken
+	}
+	if err := validatePassword(newPassword); err != nil {
+		return err
+	}
+
+	hash, err := u.hasher.Hash(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	userID, err := u.resetRepo.Reset(ctx, hashToken(token), hash, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// 漏洩したパスワードで作られたセッションを残さない
+	return u.tokenStore.RevokeAll(ctx, userID)
+}
+
+func (u *passwordResetUsecase) checkRateLimit(ctx context.Context, key string, limit int) error {
+	if u.limiter == nil {
+		return nil
+	}
+
+	allowed, err := u.limiter.Allow(ctx, key, limit, passwordResetRateWindow)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return domain.ErrRateLimited
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+	"github.com/rssh-jp/test-api/api/infrastructure/mail"
+)
+
+// Mock repository for testing
+type mockPasswordResetRepository struct {
+	authRepo *mockAuthRepository
+	tokens   map[string]*domain.PasswordResetToken
+}
+
+func newMockPasswordResetRepository(authRepo *mockAuthRepository) *mockPasswordResetRepository {
+	return &mockPasswordResetRepository{
+		authRepo: authRepo,
+		tokens:   make(map[string]*domain.PasswordResetToken),
+	}
+}
+
+func (m *mockPasswordResetRepository) Create(ctx context.Context, token *domain.PasswordResetToken) error {
+	m.invalidate(token.UserID, time.Now())
+	copied := *token
+	m.tokens[token.TokenHash] = &copied
+	return nil
+}
+
+func (m *mockPasswordResetRepository) Reset(ctx context.Context, tokenHash, passwordHash string, now time.Time) (int64, error) {
+	token, ok := m.tokens[tokenHash]
+	if !ok || token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+		return 0, domain.ErrInvalidResetToken
+	}
+	m.invalidate(token.UserID, now)
+	for _, creds := range m.authRepo.users {
+		if creds.UserID == token.UserID {
+			creds.PasswordHash = passwordHash
+		}
+	}
+	return token.UserID, nil
+}
+
+func (m *mockPasswordResetRepository) invalidate(userID int64, now time.Time) {
+	for _, token := range m.tokens {
+		if token.UserID == userID && token.UsedAt == nil {
+			usedAt := now
+			token.UsedAt = &usedAt
+		}
+	}
+}
+
+// Mock limiter for testing
+type mockRateLimiter struct {
+	counts map[string]int
+}
+
+func (m *mockRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	m.counts[key]++
+	return m.counts[key] <= limit, nil
+}
+
+func TestPasswordResetFlow(t *testing.T) {
+	authRepo := newMockAuthRepository()
+	tokenStore := newMockRefreshTokenStore()
+	mailer := mail.NewMemoryMailer()
+	authUsecase := NewAuthUsecase(authRepo, plainHasher{}, &mockTokenIssuer{}, tokenStore, nil, nil, nil)
+	resetUsecase := NewPasswordResetUsecase(newMockPasswordResetRepository(authRepo), authRepo, plainHasher{}, tokenStore, mailer, nil, "http://localhost:3000/reset-password?lang=ja", time.Hour)
+	ctx := context.Background()
+
+	if _, _, err := authUsecase.Register(ctx, RegisterInput{Username: "alice", Email: "alice@example.com", Password: "password123"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	session, err := authUsecase.Login(ctx, LoginInput{Login: "alice", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// unknown addresses succeed without sending anything
+	if err := resetUsecase.RequestReset(ctx, "nobody@example.com", "127.0.0.1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(mailer.Messages()) != 0 {
+		t.Fatalf("Expected no mail for unknown address, got %d", len(mailer.Messages()))
+	}
+
+	if err := resetUsecase.RequestReset(ctx, "alice@example.com", "127.0.0.1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	messages := mailer.Messages()
+	if len(messages) != 1 || messages[0].To != "alice@example.com" {
+		t.Fatalf("Expected 1 mail to alice, got %+v", messages)
+	}
+	if !strings.Contains(messages[0].Body, "http://localhost:3000/reset-password?lang=ja&token=") {
+		t.Fatalf("Expected a link to the reset page, got %q", messages[0].Body)
+	}
+	token := extractToken(t, messages[0].Body)
+
+	if err := resetUsecase.ResetPassword(ctx, token, "short"); !errors.Is(err, domain.ErrInvalidAuthInput) {
+		t.Fatalf("Expected ErrInvalidAuthInput, got %v", err)
+	}
+	if err := resetUsecase.ResetPassword(ctx, token, "new-password"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// the token is single-use and existing sessions are revoked
+	if err := resetUsecase.ResetPassword(ctx, token, "another-password"); !errors.Is(err, domain.ErrInvalidResetToken) {
+		t.Fatalf("Expected ErrInvalidResetToken, got %v", err)
+	}
+	if _, err := authUsecase.Refresh(ctx, session.Tokens.RefreshToken); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Fatalf("Expected ErrInvalidToken for revoked session, got %v", err)
+	}
+	if _, err := authUsecase.Authenticate(ctx, session.Tokens.AccessToken); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Fatalf("Expected ErrInvalidToken for an access token issued before the reset, got %v", err)
+	}
+
+	if _, err := authUsecase.Login(ctx, LoginInput{Login: "alice", Password: "password123"}); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Fatalf("Expected ErrInvalidCredentials for old password, got %v", err)
+	}
+	relogin, err := authUsecase.Login(ctx, LoginInput{Login: "alice", Password: "new-password"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := authUsecase.Authenticate(ctx, relogin.Tokens.AccessToken); err != nil {
+		t.Fatalf("Expected the new session to be valid, got %v", err)
+	}
+}
+
+func TestPasswordResetInvalidatesEarlierTokens(t *testing.T) {
+	authRepo := newMockAuthRepository()
+	mailer := mail.NewMemoryMailer()
+	resetUsecase := NewPasswordResetUsecase(newMockPasswordResetRepository(authRepo), authRepo, plainHasher{}, newMockRefreshTokenStore(), mailer, nil, "http://localhost:3000/reset-password", time.Hour)
+	ctx := context.Background()
+
+	authRepo.CreateUser(ctx, &domain.User{Username: "alice", Email: "alice@example.com"}, "hashed:password123")
+
+	for i := 0; i < 2; i++ {
+		if err := resetUsecase.RequestReset(ctx, "alice@example.com", "127.0.0.1"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	messages := mailer.Messages()
+	first, second := extractToken(t, messages[0].Body), extractToken(t, messages[1].Body)
+
+	if err := resetUsecase.ResetPassword(ctx, first, "new-password"); !errors.Is(err, domain.ErrInvalidResetToken) {
+		t.Fatalf("Expected ErrInvalidResetToken for superseded token, got %v", err)
+	}
+	if err := resetUsecase.ResetPassword(ctx, second, "new-password"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestPasswordResetRateLimit(t *testing.T) {
+	authRepo := newMockAuthRepository()
+	limiter := &mockRateLimiter{counts: make(map[string]int)}
+	resetUsecase := NewPasswordResetUsecase(newMockPasswordResetRepository(authRepo), authRepo, plainHasher{}, newMockRefreshTokenStore(), mail.NewMemoryMailer(), limiter, "http://localhost:3000/reset-password", time.Hour)
+	ctx := context.Background()
+
+	// per email
+	for i := 0; i < passwordResetEmailLimit; i++ {
+		if err := resetUsecase.RequestReset(ctx, "alice@example.com", fmt.Sprintf("10.0.0.%d", i)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if err := resetUsecase.RequestReset(ctx, "Alice@example.com", "10.0.1.1"); !errors.Is(err, domain.ErrRateLimited) {
+		t.Fatalf("Expected ErrRateLimited for email, got %v", err)
+	}
+
+	// per IP
+	for i := 0; i < passwordResetIPLimit; i++ {
+		if err := resetUsecase.RequestReset(ctx, fmt.Sprintf("user%d@example.com", i), "192.0.2.1"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if err := resetUsecase.RequestReset(ctx, "bob@example.com", "192.0.2.1"); !errors.Is(err, domain.ErrRateLimited) {
+		t.Fatalf("Expected ErrRateLimited for IP, got %v", err)
+	}
+}
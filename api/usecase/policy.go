@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// Policy は書き込み・モデレーション操作の認可判定を行います。
+// 所有者本人か、ロールを通じて必要な権限を持つユーザーだけを許可します
+type Policy interface {
+	// RequireOwnerOr allows the owner (viewer == ownerID) or a user with the permission
+	RequireOwnerOr(ctx context.Context, ownerID int64, permission string) error
+
+	// RequirePermission allows only users with the permission
+	RequirePermission(ctx context.Context, permission string) error
+
+	// RequireVerifiedEmail allows the operation only if userID has verified their email address
+	RequireVerifiedEmail(ctx context.Context, userID int64) error
+}
+
+type rolePolicy struct {
+	roleRepo         domain.RoleRepository
+	verificationRepo domain.EmailVerificationRepository // nilの場合はメール認証を要求しない
+}
+
+// NewPolicy creates a policy backed by the roles/permissions tables
+func NewPolicy(roleRepo domain.RoleRepository, verificationRepo domain.EmailVerificationRepository) Policy {
+	return &rolePolicy{
+		roleRepo:         roleRepo,
+		verificationRepo: verificationRepo,
+	}
+}
+
+// RequireOwnerOr は閲覧者が所有者本人であれば権限を確認せずに許可します
+func (p *rolePolicy) RequireOwnerOr(ctx context.Context, ownerID int64, permission string) error {
+	viewerID, ok := domain.ViewerIDFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthenticated
+	}
+	if ownerID > 0 && viewerID == ownerID {
+		return nil
+	}
+	return p.requirePermission(ctx, viewerID, permission)
+}
+
+func (p *rolePolicy) RequirePermission(ctx context.Context, permission string) error {
+	viewerID, ok := domain.ViewerIDFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthenticated
+	}
+	return p.requirePermission(ctx, viewerID, permission)
+}
+
+// RequireVerifiedEmail は未認証アカウントの操作（コメント投稿など）を制限するためのフックです
+func (p *rolePolicy) RequireVerifiedEmail(ctx context.Context, userID int64) error {
+	if p.verificationRepo == nil {
+		return nil
+	}
+
+	verified, err := p.verificationRepo.IsEmailVerified(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if !verified {
+		return domain.ErrEmailNotVerified
+	}
+	return nil
+}
+
+func (p *rolePolicy) requirePermission(ctx context.Context, userID int64, permission string) error {
+	userRoles, err := p.roleRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user roles: %w", err)
+	}
+	if !userRoles.HasPermission(permission) {
+		return fmt.Errorf("%w: %s permission required", domain.ErrForbidden, permission)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// allowAllPolicy は認可を常に許可するテスト用ポリシー
+type allowAllPolicy struct{}
+
+func (allowAllPolicy) RequireOwnerOr(ctx context.Context, ownerID int64, permission string) error {
+	return nil
+}
+
+func (allowAllPolicy) RequirePermission(ctx context.Context, permission string) error {
+	return nil
+}
+
+func (allowAllPolicy) RequireVerifiedEmail(ctx context.Context, userID int64) error {
+	return nil
+}
+
+// Mock repository for testing
+type mockRoleRepository struct {
+	permissions map[int64][]string
+}
+
+func (m *mockRoleRepository) FindByUserID(ctx context.Context, userID int64) (*domain.UserRoles, error) {
+	return &domain.UserRoles{UserID: userID, Permissions: m.permissions[userID]}, nil
+}
+
+func (m *mockRoleRepository) Assign(ctx context.Context, userID int64, role string) error {
+	return nil
+}
+
+func (m *mockRoleRepository) Revoke(ctx context.Context, userID int64, role string) error {
+	return nil
+}
+
+func viewerContext(userID int64) context.Context {
+	return domain.ContextWithAuthUser(context.Background(), &domain.AuthUser{ID: userID})
+}
+
+func TestPolicyRequireOwnerOr(t *testing.T) {
+	policy := NewPolicy(&mockRoleRepository{permissions: map[int64][]string{
+		2: {domain.PermissionPostsModerate},
+	}}, nil)
+
+	if err := policy.RequireOwnerOr(context.Background(), 1, domain.PermissionPostsModerate); !errors.Is(err, domain.ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated for anonymous request, got %v", err)
+	}
+	if err := policy.RequireOwnerOr(viewerContext(1), 1, domain.PermissionPostsModerate); err != nil {
+		t.Errorf("Expected owner to be allowed, got %v", err)
+	}
+	if err := policy.RequireOwnerOr(viewerContext(3), 1, domain.PermissionPostsModerate); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for other user, got %v", err)
+	}
+	if err := policy.RequireOwnerOr(viewerContext(2), 1, domain.PermissionPostsModerate); err != nil {
+		t.Errorf("Expected moderator to be allowed, got %v", err)
+	}
+	if err := policy.RequireOwnerOr(viewerContext(2), 1, domain.PermissionPostsManage); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden without posts.manage, got %v", err)
+	}
+}
+
+func TestPostWritesRequireOwnership(t *testing.T) {
+	mockRepo := newMockPostRepository()
+	mockRepo.posts[1] = &domain.Post{ID: 1, UserID: 1, Title: "Hello", Slug: "hello", Content: "body", Status: domain.PostStatusPublished}
+	usecase := NewPostUsecase(mockRepo, nil, nil, nil, NewPolicy(&mockRoleRepository{permissions: map[int64][]string{
+		2: {domain.PermissionPostsModerate},
+	}}, nil))
+
+	title := "Updated"
+	if _, err := usecase.UpdatePost(viewerContext(3), 1, UpdatePostInput{Title: &title}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for non-owner update, got %v", err)
+	}
+	if _, err := usecase.UpdatePost(viewerContext(2), 1, UpdatePostInput{Title: &title}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for moderator update, got %v", err)
+	}
+	if _, err := usecase.UpdatePost(viewerContext(1), 1, UpdatePostInput{Title: &title}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// moderators can take down other users' posts
+	if _, err := usecase.ArchivePost(viewerContext(2), 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := usecase.CreatePost(viewerContext(3), CreatePostInput{UserID: 1, Title: "Hi", Slug: "hi", Content: "body"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for posting as another user, got %v", err)
+	}
+}
@@ -3,10 +3,39 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/rssh-jp/test-api/api/domain"
 )
 
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// CreatePostInput は投稿作成の入力値
+type CreatePostInput struct {
+	UserID     int64
+	CategoryID *int64
+	Title      string
+	Slug       string
+	Content    string
+	Excerpt    *string
+	Status     string // 空の場合は draft
+	IsFeatured bool
+	TagIDs     []int64
+}
+
+// UpdatePostInput は投稿更新の入力値（nilのフィールドは変更しない）
+type UpdatePostInput struct {
+	CategoryID *int64
+	Title      *string
+	Slug       *string
+	Content    *string
+	Excerpt    *string
+	IsFeatured *bool
+	TagIDs     []int64 // nilの場合はタグを変更しない
+}
+
 // PostUsecase defines business logic for posts
 type PostUsecase interface {
 	GetPosts(ctx context.Context, page, pageSize int) ([]domain.PostWithDetails, int64, error)
@@ -15,16 +44,30 @@ type PostUsecase interface {
 	GetPostsByCategory(ctx context.Context, categorySlug string, page, pageSize int) ([]domain.PostWithDetails, error)
 	GetPostsByTag(ctx context.Context, tagSlug string, page, pageSize int) ([]domain.PostWithDetails, error)
 	GetFeaturedPosts(ctx context.Context, limit int) ([]domain.PostWithDetails, error)
+	SearchPosts(ctx context.Context, query, mode string, page, pageSize int) ([]domain.PostSearchResult, error)
+	CreatePost(ctx context.Context, input CreatePostInput) (*domain.Post, error)
+	UpdatePost(ctx context.Context, id int64, input UpdatePostInput) (*domain.Post, error)
+	PublishPost(ctx context.Context, id int64) (*domain.Post, error)
+	ArchivePost(ctx context.Context, id int64) (*domain.Post, error)
+	DeletePost(ctx context.Context, id int64) error
 }
 
 type postUsecase struct {
-	postRepo domain.PostRepository
+	postRepo     domain.PostRepository
+	taxonomyRepo domain.TaxonomyRepository // カテゴリー・タグの存在確認用（nilの場合は確認しない）
+	likeRepo     domain.LikeRepository     // LikedByMe の設定用（nilの場合は設定しない）
+	viewCounter  domain.ViewCounter        // 閲覧数の記録用（nilの場合は記録しない）
+	policy       Policy
 }
 
 // NewPostUsecase creates a new post usecase
-func NewPostUsecase(postRepo domain.PostRepository) PostUsecase {
+func NewPostUsecase(postRepo domain.PostRepository, taxonomyRepo domain.TaxonomyRepository, likeRepo domain.LikeRepository, viewCounter domain.ViewCounter, policy Policy) PostUsecase {
 	return &postUsecase{
-		postRepo: postRepo,
+		postRepo:     postRepo,
+		taxonomyRepo: taxonomyRepo,
+		likeRepo:     likeRepo,
+		viewCounter:  viewCounter,
+		policy:       policy,
 	}
 }
 
@@ -49,10 +92,14 @@ func (u *postUsecase) GetPosts(ctx context.Context, page, pageSize int) ([]domai
 		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
 	}
 
+	if err := u.decorate(ctx, postPointers(posts)...); err != nil {
+		return nil, 0, err
+	}
+
 	return posts, total, nil
 }
 
-// GetPostByID retrieves a post by ID and increments view count
+// GetPostByID retrieves a post by ID and records a view
 func (u *postUsecase) GetPostByID(ctx context.Context, id int64) (*domain.PostWithDetails, error) {
 	if id <= 0 {
 		return nil, fmt.Errorf("invalid post ID: %d", id)
@@ -63,15 +110,16 @@ func (u *postUsecase) GetPostByID(ctx context.Context, id int64) (*domain.PostWi
 		return nil, fmt.Errorf("failed to get post: %w", err)
 	}
 
-	// Increment view count asynchronously (fire and forget)
-	go func() {
-		_ = u.postRepo.IncrementViewCount(context.Background(), id)
-	}()
+	recordView(ctx, u.viewCounter, post)
+
+	if err := u.decorate(ctx, post); err != nil {
+		return nil, err
+	}
 
 	return post, nil
 }
 
-// GetPostBySlug retrieves a post by slug and increments view count
+// GetPostBySlug retrieves a post by slug and records a view
 func (u *postUsecase) GetPostBySlug(ctx context.Context, slug string) (*domain.PostWithDetails, error) {
 	if slug == "" {
 		return nil, fmt.Errorf("slug cannot be empty")
@@ -82,10 +130,11 @@ func (u *postUsecase) GetPostBySlug(ctx context.Context, slug string) (*domain.P
 		return nil, fmt.Errorf("failed to get post: %w", err)
 	}
 
-	// Increment view count asynchronously (fire and forget)
-	go func() {
-		_ = u.postRepo.IncrementViewCount(context.Background(), post.ID)
-	}()
+	recordView(ctx, u.viewCounter, post)
+
+	if err := u.decorate(ctx, post); err != nil {
+		return nil, err
+	}
 
 	return post, nil
 }
@@ -110,6 +159,10 @@ func (u *postUsecase) GetPostsByCategory(ctx context.Context, categorySlug strin
 		return nil, fmt.Errorf("failed to get posts by category: %w", err)
 	}
 
+	if err := u.decorate(ctx, postPointers(posts)...); err != nil {
+		return nil, err
+	}
+
 	return posts, nil
 }
 
@@ -133,6 +186,10 @@ func (u *postUsecase) GetPostsByTag(ctx context.Context, tagSlug string, page, p
 		return nil, fmt.Errorf("failed to get posts by tag: %w", err)
 	}
 
+	if err := u.decorate(ctx, postPointers(posts)...); err != nil {
+		return nil, err
+	}
+
 	return posts, nil
 }
 
@@ -147,5 +204,263 @@ func (u *postUsecase) GetFeaturedPosts(ctx context.Context, limit int) ([]domain
 		return nil, fmt.Errorf("failed to get featured posts: %w", err)
 	}
 
+	if err := u.decorate(ctx, postPointers(posts)...); err != nil {
+		return nil, err
+	}
+
 	return posts, nil
 }
+
+// SearchPosts runs a full-text search and adds highlighted title and snippet
+func (u *postUsecase) SearchPosts(ctx context.Context, query, mode string, page, pageSize int) ([]domain.PostSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: q is required", domain.ErrInvalidSearchQuery)
+	}
+	if utf8.RuneCountInString(query) > 200 {
+		return nil, fmt.Errorf("%w: q must be at most 200 characters", domain.ErrInvalidSearchQuery)
+	}
+
+	if mode == "" {
+		mode = domain.SearchModeNatural
+	}
+	if mode != domain.SearchModeNatural && mode != domain.SearchModeBoolean {
+		return nil, fmt.Errorf("%w: mode must be natural or boolean", domain.ErrInvalidSearchQuery)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+
+	results, err := u.postRepo.SearchWithDetails(ctx, query, mode, pageSize, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search posts: %w", err)
+	}
+
+	terms := searchTerms(query)
+	for i := range results {
+		results[i].TitleHighlight = highlightTerms(results[i].Title, terms)
+		results[i].Snippet = buildSnippet(results[i].Content, terms, snippetRadius)
+	}
+
+	hits := make([]*domain.PostWithDetails, len(results))
+	for i := range results {
+		hits[i] = &results[i].PostWithDetails
+	}
+	if err := u.decorate(ctx, hits...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// CreatePost validates the input and creates a new post (draft by default)
+func (u *postUsecase) CreatePost(ctx context.Context, input CreatePostInput) (*domain.Post, error) {
+	if input.UserID <= 0 {
+		return nil, fmt.Errorf("%w: userId is required", domain.ErrInvalidPostInput)
+	}
+	if err := u.policy.RequireOwnerOr(ctx, input.UserID, domain.PermissionPostsManage); err != nil {
+		return nil, err
+	}
+	if err := u.policy.RequireVerifiedEmail(ctx, input.UserID); err != nil {
+		return nil, err
+	}
+
+	status := input.Status
+	if status == "" {
+		status = domain.PostStatusDraft
+	}
+	if status != domain.PostStatusDraft && status != domain.PostStatusPublished {
+		return nil, fmt.Errorf("%w: status must be draft or published", domain.ErrInvalidPostInput)
+	}
+
+	post := &domain.Post{
+		UserID:     input.UserID,
+		CategoryID: input.CategoryID,
+		Title:      strings.TrimSpace(input.Title),
+		Slug:       input.Slug,
+		Content:    input.Content,
+		Excerpt:    input.Excerpt,
+		Status:     status,
+		IsFeatured: input.IsFeatured,
+	}
+	if err := validatePost(post); err != nil {
+		return nil, err
+	}
+	if err := u.validateReferences(ctx, input.CategoryID, input.TagIDs); err != nil {
+		return nil, err
+	}
+
+	if err := u.postRepo.Create(ctx, post, input.TagIDs); err != nil {
+		return nil, fmt.Errorf("failed to create post: %w", err)
+	}
+
+	return post, nil
+}
+
+// UpdatePost updates editable fields of a post that is not deleted
+func (u *postUsecase) UpdatePost(ctx context.Context, id int64, input UpdatePostInput) (*domain.Post, error) {
+	post, err := u.findWritablePost(ctx, id, domain.PermissionPostsManage)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.CategoryID != nil {
+		post.CategoryID = input.CategoryID
+	}
+	if input.Title != nil {
+		post.Title = strings.TrimSpace(*input.Title)
+	}
+	if input.Slug != nil {
+		post.Slug = *input.Slug
+	}
+	if input.Content != nil {
+		post.Content = *input.Content
+	}
+	if input.Excerpt != nil {
+		post.Excerpt = input.Excerpt
+	}
+	if input.IsFeatured != nil {
+		post.IsFeatured = *input.IsFeatured
+	}
+	if err := validatePost(post); err != nil {
+		return nil, err
+	}
+	if err := u.validateReferences(ctx, input.CategoryID, input.TagIDs); err != nil {
+		return nil, err
+	}
+
+	if err := u.postRepo.Update(ctx, post, input.TagIDs); err != nil {
+		return nil, fmt.Errorf("failed to update post: %w", err)
+	}
+
+	return post, nil
+}
+
+// PublishPost moves a draft or archived post to published
+func (u *postUsecase) PublishPost(ctx context.Context, id int64) (*domain.Post, error) {
+	return u.changeStatus(ctx, id, domain.PostStatusPublished, domain.PermissionPostsManage)
+}
+
+// ArchivePost moves a published post to archived
+func (u *postUsecase) ArchivePost(ctx context.Context, id int64) (*domain.Post, error) {
+	return u.changeStatus(ctx, id, domain.PostStatusArchived, domain.PermissionPostsModerate)
+}
+
+// DeletePost soft-deletes a post (status = deleted)
+func (u *postUsecase) DeletePost(ctx context.Context, id int64) error {
+	post, err := u.findWritablePost(ctx, id, domain.PermissionPostsModerate)
+	if err != nil {
+		return err
+	}
+
+	if !domain.CanTransitionPostStatus(post.Status, domain.PostStatusDeleted) {
+		return fmt.Errorf("%w: %s → %s", domain.ErrInvalidPostStatusTransition, post.Status, domain.PostStatusDeleted)
+	}
+
+	if err := u.postRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete post: %w", err)
+	}
+
+	return nil
+}
+
+func (u *postUsecase) changeStatus(ctx context.Context, id int64, status, permission string) (*domain.Post, error) {
+	post, err := u.findWritablePost(ctx, id, permission)
+	if err != nil {
+		return nil, err
+	}
+
+	if !domain.CanTransitionPostStatus(post.Status, status) {
+		return nil, fmt.Errorf("%w: %s → %s", domain.ErrInvalidPostStatusTransition, post.Status, status)
+	}
+
+	if err := u.postRepo.UpdateStatus(ctx, id, status); err != nil {
+		return nil, fmt.Errorf("failed to update post status: %w", err)
+	}
+
+	return u.postRepo.FindByID(ctx, id)
+}
+
+// decorate は閲覧者のいいね状態と未反映の閲覧数を投稿へ設定します
+func (u *postUsecase) decorate(ctx context.Context, posts ...*domain.PostWithDetails) error {
+	addPendingViews(ctx, u.viewCounter, posts...)
+	return markLikedByViewer(ctx, u.likeRepo, posts...)
+}
+
+// findWritablePost は更新対象の投稿を取得します（削除済みは対象外）。
+// 投稿者本人か、permissionを持つユーザーのみ更新できます
+func (u *postUsecase) findWritablePost(ctx context.Context, id int64, permission string) (*domain.Post, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid post ID: %d", domain.ErrInvalidPostInput, id)
+	}
+
+	post, err := u.postRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+	if post.Status == domain.PostStatusDeleted {
+		return nil, fmt.Errorf("%w: post %d is deleted", domain.ErrInvalidPostStatusTransition, id)
+	}
+	if err := u.policy.RequireOwnerOr(ctx, post.UserID, permission); err != nil {
+		return nil, err
+	}
+
+	return post, nil
+}
+
+// validateReferences は指定されたカテゴリー・タグが存在することを確認します（外部キー違反を入力エラーとして返すため）
+func (u *postUsecase) validateReferences(ctx context.Context, categoryID *int64, tagIDs []int64) error {
+	if u.taxonomyRepo == nil {
+		return nil
+	}
+
+	if categoryID != nil {
+		exists, err := u.taxonomyRepo.CategoryExists(ctx, *categoryID)
+		if err != nil {
+			return fmt.Errorf("failed to check category: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("%w: category %d does not exist", domain.ErrInvalidPostInput, *categoryID)
+		}
+	}
+
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	existing, err := u.taxonomyRepo.FindExistingTagIDs(ctx, tagIDs)
+	if err != nil {
+		return fmt.Errorf("failed to check tags: %w", err)
+	}
+	found := make(map[int64]bool, len(existing))
+	for _, id := range existing {
+		found[id] = true
+	}
+	for _, id := range tagIDs {
+		if !found[id] {
+			return fmt.Errorf("%w: tag %d does not exist", domain.ErrInvalidPostInput, id)
+		}
+	}
+	return nil
+}
+
+func validatePost(post *domain.Post) error {
+	if post.Title == "" || utf8.RuneCountInString(post.Title) > 255 {
+		return fmt.Errorf("%w: title must be 1-255 characters", domain.ErrInvalidPostInput)
+	}
+	if !slugPattern.MatchString(post.Slug) || len(post.Slug) > 255 {
+		return fmt.Errorf("%w: slug must contain only lowercase letters, digits and hyphens", domain.ErrInvalidPostInput)
+	}
+	if strings.TrimSpace(post.Content) == "" {
+		return fmt.Errorf("%w: content is required", domain.ErrInvalidPostInput)
+	}
+	if post.Excerpt != nil && utf8.RuneCountInString(*post.Excerpt) > 500 {
+		return fmt.Errorf("%w: excerpt must be at most 500 characters", domain.ErrInvalidPostInput)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// Mock repository for testing
+type mockPostRepository struct {
+	posts map[int64]*domain.Post
+	tags  map[int64][]int64
+}
+
+func newMockPostRepository() *mockPostRepository {
+	return &mockPostRepository{
+		posts: make(map[int64]*domain.Post),
+		tags:  make(map[int64][]int64),
+	}
+}
+
+func (m *mockPostRepository) FindAllWithDetails(ctx context.Context, limit, offset int) ([]domain.PostWithDetails, error) {
+	return nil, nil
+}
+
+func (m *mockPostRepository) FindByIDWithDetails(ctx context.Context, id int64) (*domain.PostWithDetails, error) {
+	post, ok := m.posts[id]
+	if !ok || post.Status != domain.PostStatusPublished {
+		return nil, domain.ErrNotFound
+	}
+	return &domain.PostWithDetails{Post: *post}, nil
+}
+
+func (m *mockPostRepository) FindBySlugWithDetails(ctx context.Context, slug string) (*domain.PostWithDetails, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockPostRepository) FindByCategoryWithDetails(ctx context.Context, categorySlug string, limit, offset int) ([]domain.PostWithDetails, error) {
+	return nil, nil
+}
+
+func (m *mockPostRepository) FindByTagWithDetails(ctx context.Context, tagSlug string, limit, offset int) ([]domain.PostWithDetails, error) {
+	return nil, nil
+}
+
+func (m *mockPostRepository) FindFeaturedWithDetails(ctx context.Context, limit int) ([]domain.PostWithDetails, error) {
+	return nil, nil
+}
+
+func (m *mockPostRepository) SearchWithDetails(ctx context.Context, query, mode string, limit, offset int) ([]domain.PostSearchResult, error) {
+	var results []domain.PostSearchResult
+	for _, post := range m.posts {
+		results = append(results, domain.PostSearchResult{PostWithDetails: domain.PostWithDetails{Post: *post}})
+	}
+	return results, nil
+}
+
+func (m *mockPostRepository) GetTotalCount(ctx context.Context) (int64, error) {
+	return int64(len(m.posts)), nil
+}
+
+func (m *mockPostRepository) AddViewCounts(ctx context.Context, deltas map[int64]int64) error {
+	for id, delta := range deltas {
+		if post, ok := m.posts[id]; ok {
+			post.ViewCount += int32(delta)
+		}
+	}
+	return nil
+}
+
+func (m *mockPostRepository) FindByID(ctx context.Context, id int64) (*domain.Post, error) {
+	post, ok := m.posts[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *post
+	return &copied, nil
+}
+
+func (m *mockPostRepository) Create(ctx context.Context, post *domain.Post, tagIDs []int64) error {
+	post.ID = int64(len(m.posts) + 1)
+	copied := *post
+	m.posts[post.ID] = &copied
+	m.tags[post.ID] = tagIDs
+	return nil
+}
+
+func (m *mockPostRepository) Update(ctx context.Context, post *domain.Post, tagIDs []int64) error {
+	copied := *post
+	m.posts[post.ID] = &copied
+	if tagIDs != nil {
+		m.tags[post.ID] = tagIDs
+	}
+	return nil
+}
+
+func (m *mockPostRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	m.posts[id].Status = status
+	return nil
+}
+
+func (m *mockPostRepository) Delete(ctx context.Context, id int64) error {
+	m.posts[id].Status = domain.PostStatusDeleted
+	delete(m.tags, id)
+	return nil
+}
+
+func TestCreatePostDefaultsToDraft(t *testing.T) {
+	mockRepo := newMockPostRepository()
+	usecase := NewPostUsecase(mockRepo, nil, nil, nil, allowAllPolicy{})
+
+	ctx := context.Background()
+	post, err := usecase.CreatePost(ctx, CreatePostInput{
+		UserID:  1,
+		Title:   "Hello",
+		Slug:    "hello-world",
+		Content: "body",
+		TagIDs:  []int64{1, 2},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if post.Status != domain.PostStatusDraft {
+		t.Errorf("Expected status '%s', got '%s'", domain.PostStatusDraft, post.Status)
+	}
+
+	if len(mockRepo.tags[post.ID]) != 2 {
+		t.Errorf("Expected 2 tag links, got %d", len(mockRepo.tags[post.ID]))
+	}
+}
+
+func TestCreatePostRejectsInvalidSlug(t *testing.T) {
+	usecase := NewPostUsecase(newMockPostRepository(), nil, nil, nil, allowAllPolicy{})
+
+	_, err := usecase.CreatePost(context.Background(), CreatePostInput{
+		UserID:  1,
+		Title:   "Hello",
+		Slug:    "Hello World",
+		Content: "body",
+	})
+	if !errors.Is(err, domain.ErrInvalidPostInput) {
+		t.Fatalf("Expected ErrInvalidPostInput, got %v", err)
+	}
+}
+
+// mockTaxonomyRepository は存在するカテゴリー・タグのIDを保持します
+type mockTaxonomyRepository struct {
+	categories map[int64]bool
+	tags       map[int64]bool
+}
+
+func (m *mockTaxonomyRepository) CategoryExists(ctx context.Context, id int64) (bool, error) {
+	return m.categories[id], nil
+}
+
+func (m *mockTaxonomyRepository) FindExistingTagIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	var existing []int64
+	for _, id := range ids {
+		if m.tags[id] {
+			existing = append(existing, id)
+		}
+	}
+	return existing, nil
+}
+
+func TestCreateAndUpdatePostRejectUnknownReferences(t *testing.T) {
+	mockRepo := newMockPostRepository()
+	taxonomy := &mockTaxonomyRepository{
+		categories: map[int64]bool{1: true},
+		tags:       map[int64]bool{1: true, 2: true},
+	}
+	usecase := NewPostUsecase(mockRepo, taxonomy, nil, nil, allowAllPolicy{})
+	ctx := context.Background()
+
+	categoryID := int64(1)
+	post, err := usecase.CreatePost(ctx, CreatePostInput{
+		UserID:     1,
+		CategoryID: &categoryID,
+		Title:      "Hello",
+		Slug:       "hello",
+		Content:    "body",
+		TagIDs:     []int64{1, 2},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error for existing references, got %v", err)
+	}
+
+	unknownCategory := int64(99)
+	for name, input := range map[string]CreatePostInput{
+		"category": {UserID: 1, CategoryID: &unknownCategory, Title: "Hello", Slug: "hello-2", Content: "body"},
+		"tag":      {UserID: 1, Title: "Hello", Slug: "hello-3", Content: "body", TagIDs: []int64{1, 99}},
+	} {
+		if _, err := usecase.CreatePost(ctx, input); !errors.Is(err, domain.ErrInvalidPostInput) {
+			t.Errorf("create with unknown %s: Expected ErrInvalidPostInput, got %v", name, err)
+		}
+	}
+
+	if _, err := usecase.UpdatePost(ctx, post.ID, UpdatePostInput{CategoryID: &unknownCategory}); !errors.Is(err, domain.ErrInvalidPostInput) {
+		t.Errorf("update with unknown category: Expected ErrInvalidPostInput, got %v", err)
+	}
+	if _, err := usecase.UpdatePost(ctx, post.ID, UpdatePostInput{TagIDs: []int64{99}}); !errors.Is(err, domain.ErrInvalidPostInput) {
+		t.Errorf("update with unknown tag: Expected ErrInvalidPostInput, got %v", err)
+	}
+	if len(mockRepo.posts) != 1 || len(mockRepo.tags[post.ID]) != 2 {
+		t.Errorf("Expected rejected inputs not to be written, got %d posts and tags %v", len(mockRepo.posts), mockRepo.tags[post.ID])
+	}
+}
+
+func TestPostStatusLifecycle(t *testing.T) {
+	mockRepo := newMockPostRepository()
+	usecase := NewPostUsecase(mockRepo, nil, nil, nil, allowAllPolicy{})
+
+	ctx := context.Background()
+	post, err := usecase.CreatePost(ctx, CreatePostInput{
+		UserID:  1,
+		Title:   "Hello",
+		Slug:    "hello",
+		Content: "body",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// draft → archived is not allowed
+	if _, err := usecase.ArchivePost(ctx, post.ID); !errors.Is(err, domain.ErrInvalidPostStatusTransition) {
+		t.Fatalf("Expected ErrInvalidPostStatusTransition, got %v", err)
+	}
+
+	published, err := usecase.PublishPost(ctx, post.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if published.Status != domain.PostStatusPublished {
+		t.Errorf("Expected status '%s', got '%s'", domain.PostStatusPublished, published.Status)
+	}
+
+	archived, err := usecase.ArchivePost(ctx, post.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if archived.Status != domain.PostStatusArchived {
+		t.Errorf("Expected status '%s', got '%s'", domain.PostStatusArchived, archived.Status)
+	}
+
+	if err := usecase.DeletePost(ctx, post.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// deleted posts cannot be edited or deleted again
+	title := "Updated"
+	if _, err := usecase.UpdatePost(ctx, post.ID, UpdatePostInput{Title: &title}); !errors.Is(err, domain.ErrInvalidPostStatusTransition) {
+		t.Fatalf("Expected ErrInvalidPostStatusTransition, got %v", err)
+	}
+	if err := usecase.DeletePost(ctx, post.ID); !errors.Is(err, domain.ErrInvalidPostStatusTransition) {
+		t.Fatalf("Expected ErrInvalidPostStatusTransition, got %v", err)
+	}
+}
+
+func TestSearchPostsHighlightsTerms(t *testing.T) {
+	mockRepo := newMockPostRepository()
+	mockRepo.posts[1] = &domain.Post{
+		ID:      1,
+		Title:   "Redis Caching",
+		Content: "Caching with <b>Redis</b> makes reads fast.",
+		Status:  domain.PostStatusPublished,
+	}
+	usecase := NewPostUsecase(mockRepo, nil, nil, nil, allowAllPolicy{})
+
+	results, err := usecase.SearchPosts(context.Background(), "+redis", domain.SearchModeBoolean, 1, 20)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+
+	if results[0].TitleHighlight != "<mark>Redis</mark> Caching" {
+		t.Errorf("Unexpected title highlight: %s", results[0].TitleHighlight)
+	}
+	if results[0].Snippet != "Caching with &lt;b&gt;<mark>Redis</mark>&lt;/b&gt; makes reads fast." {
+		t.Errorf("Unexpected snippet: %s", results[0].Snippet)
+	}
+}
+
+func TestSearchPostsRejectsInvalidInput(t *testing.T) {
+	usecase := NewPostUsecase(newMockPostRepository(), nil, nil, nil, allowAllPolicy{})
+	ctx := context.Background()
+
+	if _, err := usecase.SearchPosts(ctx, "  ", "", 1, 20); !errors.Is(err, domain.ErrInvalidSearchQuery) {
+		t.Errorf("Expected ErrInvalidSearchQuery for empty query, got %v", err)
+	}
+	if _, err := usecase.SearchPosts(ctx, "go", "regex", 1, 20); !errors.Is(err, domain.ErrInvalidSearchQuery) {
+		t.Errorf("Expected ErrInvalidSearchQuery for unknown mode, got %v", err)
+	}
+}
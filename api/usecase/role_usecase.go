@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// RoleUsecase defines business logic for granting and revoking roles
+type RoleUsecase interface {
+	GetUserRoles(ctx context.Context, userID int64) (*domain.UserRoles, error)
+	AssignRole(ctx context.Context, userID int64, role string) (*domain.UserRoles, error)
+	RevokeRole(ctx context.Context, userID int64, role string) (*domain.UserRoles, error)
+}
+
+type roleUsecase struct {
+	roleRepo domain.RoleRepository
+	userRepo domain.UserRepository
+	policy   Policy
+}
+
+// NewRoleUsecase creates a new role usecase
+func NewRoleUsecase(roleRepo domain.RoleRepository, userRepo domain.UserRepository, policy Policy) RoleUsecase {
+	return &roleUsecase{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+		policy:   policy,
+	}
+}
+
+// GetUserRoles returns the user's roles (the user themselves or roles.manage)
+func (u *roleUsecase) GetUserRoles(ctx context.Context, userID int64) (*domain.UserRoles, error) {
+	if err := u.policy.RequireOwnerOr(ctx, userID, domain.PermissionRolesManage); err != nil {
+		return nil, err
+	}
+
+	return u.roleRepo.FindByUserID(ctx, userID)
+}
+
+// AssignRole grants a role to the user (roles.manage only)
+func (u *roleUsecase) AssignRole(ctx context.Context, userID int64, role string) (*domain.UserRoles, error) {
+	if err := u.prepare(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := u.roleRepo.Assign(ctx, userID, strings.TrimSpace(role)); err != nil {
+		return nil, err
+	}
+
+	return u.roleRepo.FindByUserID(ctx, userID)
+}
+
+// RevokeRole removes a role from the user (roles.manage only)
+func (u *roleUsecase) RevokeRole(ctx context.Context, userID int64, role string) (*domain.UserRoles, error) {
+	if err := u.prepare(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := u.roleRepo.Revoke(ctx, userID, strings.TrimSpace(role)); err != nil {
+		return nil, err
+	}
+
+	return u.roleRepo.FindByUserID(ctx, userID)
+}
+
+// prepare は権限と対象ユーザーの存在を確認します
+func (u *roleUsecase) prepare(ctx context.Context, userID int64) error {
+	if err := u.policy.RequirePermission(ctx, domain.PermissionRolesManage); err != nil {
+		return err
+	}
+
+	if _, err := u.userRepo.FindByID(ctx, userID); err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	// snippetRadius は検索スニペットでマッチ位置の前後に含める文字数
+	snippetRadius = 60
+
+	highlightOpen  = "<mark>"
+	highlightClose = "</mark>"
+)
+
+// searchTerms は検索クエリからハイライト対象の語を取り出します。
+// BOOLEAN MODEの演算子（+ - ~ < > ( ) * "）は取り除きます。
+func searchTerms(query string) []string {
+	cleaned := strings.Map(func(r rune) rune {
+		switch r {
+		case '+', '-', '~', '<', '>', '(', ')', '*', '"', '@':
+			return ' '
+		}
+		return r
+	}, query)
+
+	seen := make(map[string]bool)
+	var terms []string
+	for _, field := range strings.Fields(cleaned) {
+		term := strings.ToLower(field)
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// highlightTerms はテキスト中の検索語を<mark>で囲みます（HTMLエスケープ済み）
+func highlightTerms(text string, terms []string) string {
+	runes := []rune(text)
+	marks := matchMask(runes, terms)
+
+	var b strings.Builder
+	inMark := false
+	for i, r := range runes {
+		if marks[i] && !inMark {
+			b.WriteString(highlightOpen)
+			inMark = true
+		} else if !marks[i] && inMark {
+			b.WriteString(highlightClose)
+			inMark = false
+		}
+		b.WriteString(html.EscapeString(string(r)))
+	}
+	if inMark {
+		b.WriteString(highlightClose)
+	}
+	return b.String()
+}
+
+// buildSnippet は最初にマッチした位置の前後radius文字を切り出してハイライトします
+func buildSnippet(content string, terms []string, radius int) string {
+	runes := []rune(content)
+	marks := matchMask(runes, terms)
+
+	first := -1
+	for i, marked := range marks {
+		if marked {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		first = 0
+	}
+
+	start := first - radius
+	if start < 0 {
+		start = 0
+	}
+	end := first + radius
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	snippet := highlightTerms(string(runes[start:end]), terms)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// matchMask は各文字が検索語のいずれかに含まれるかを大文字小文字を区別せずに判定します
+func matchMask(runes []rune, terms []string) []bool {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	marks := make([]bool, len(runes))
+	for _, term := range terms {
+		t := []rune(term)
+		if len(t) == 0 {
+			continue
+		}
+		for i, r := range t {
+			t[i] = unicode.ToLower(r)
+		}
+		for i := 0; i+len(t) <= len(lower); i++ {
+			if runesEqual(lower[i:i+len(t)], t) {
+				for j := i; j < i+len(t); j++ {
+					marks[j] = true
+				}
+			}
+		}
+	}
+	return marks
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
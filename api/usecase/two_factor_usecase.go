@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+const (
+	recoveryCodeCount = 10
+	// 1ユーザーあたりのコード検証は5分間に5回まで（6桁コードの総当たり対策）
+	twoFactorAttemptLimit  = 5
+	twoFactorAttemptWindow = 5 * time.Minute
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TwoFactorUsecase defines business logic for TOTP two-factor authentication
+type TwoFactorUsecase interface {
+	Enroll(ctx context.Context, userID int64) (*domain.TwoFactorEnrollment, error)
+	Enable(ctx context.Context, userID int64, code string) ([]string, error)
+	Disable(ctx context.Context, userID int64, code string) error
+	IsEnabled(ctx context.Context, userID int64) (bool, error)
+	Verify(ctx context.Context, userID int64, code string) error
+}
+
+type twoFactorUsecase struct {
+	twoFactorRepo domain.TwoFactorRepository
+	userRepo      domain.UserRepository
+	totp          domain.TOTP
+	cipher        domain.SecretCipher // TOTPシークレットを暗号化して保存する
+	limiter       domain.RateLimiter  // nilの場合は試行回数を制限しない
+}
+
+// NewTwoFactorUsecase creates a new two-factor usecase
+func NewTwoFactorUsecase(twoFactorRepo domain.TwoFactorRepository, userRepo domain.UserRepository, totp domain.TOTP, cipher domain.SecretCipher, limiter domain.RateLimiter) TwoFactorUsecase {
+	return &twoFactorUsecase{
+		twoFactorRepo: twoFactorRepo,
+		userRepo:      userRepo,
+		totp:          totp,
+		cipher:        cipher,
+		limiter:       limiter,
+	}
+}
+
+// Enroll issues a new pending secret; 2FA stays disabled until Enable confirms a code
+func (u *twoFactorUsecase) Enroll(ctx context.Context, userID int64) (*domain.TwoFactorEnrollment, error) {
+	settings, err := u.twoFactorRepo.FindByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	if settings != nil && settings.EnabledAt != nil {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	secret, err := u.totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := u.cipher.Encrypt(secret, secretCipherContext(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt two-factor secret: %w", err)
+	}
+	if err := u.twoFactorRepo.SaveSecret(ctx, userID, encrypted); err != nil {
+		return nil, err
+	}
+
+	return &domain.TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: u.totp.ProvisioningURI(secret, user.Email),
+	}, nil
+}
+
+// Enable confirms the pending secret with a code and returns the plaintext recovery codes (shown only once)
+func (u *twoFactorUsecase) Enable(ctx context.Context, userID int64, code string) ([]string, error) {
+	if err := u.checkAttempts(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	settings, err := u.twoFactorRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrTwoFactorNotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if settings.EnabledAt != nil {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := u.decryptSecret(settings)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := u.totp.Validate(secret, code, time.Now())
+	if !ok {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		recoveryCode, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = recoveryCode
+		hashes[i] = hashToken(normalizeRecoveryCode(recoveryCode))
+	}
+
+	if err := u.twoFactorRepo.Enable(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Disable turns 2FA off after verifying a current TOTP or recovery code
+func (u *twoFactorUsecase) Disable(ctx context.Context, userID int64, code string) error {
+	if err := u.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+
+	return u.twoFactorRepo.Disable(ctx, userID)
+}
+
+// IsEnabled reports whether the user has confirmed 2FA enrollment
+func (u *twoFactorUsecase) IsEnabled(ctx context.Context, userID int64) (bool, error) {
+	settings, err := u.twoFactorRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return settings.EnabledAt != nil, nil
+}
+
+// Verify accepts a TOTP code (each time step only once) or an unused recovery code
+func (u *twoFactorUsecase) Verify(ctx context.Context, userID int64, code string) error {
+	if err := u.checkAttempts(ctx, userID); err != nil {
+		return err
+	}
+
+	settings, err := u.twoFactorRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return err
+	}
+	if settings.EnabledAt == nil {
+		return domain.ErrTwoFactorNotEnabled
+	}
+
+	secret, err := u.decryptSecret(settings)
+	if err != nil {
+		return err
+	}
+	if step, ok := u.totp.Validate(secret, code, time.Now()); ok {
+		used, err := u.twoFactorRepo.UseStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return domain.ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	consumed, err := u.twoFactorRepo.ConsumeRecoveryCode(ctx, userID, hashToken(normalized))
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func (u *twoFactorUsecase) decryptSecret(settings *domain.TwoFactorSettings) (string, error) {
+	secret, err := u.cipher.Decrypt(settings.Secret, secretCipherContext(settings.UserID))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt two-factor secret of user %d: %w", settings.UserID, err)
+	}
+	return secret, nil
+}
+
+// secretCipherContext は暗号文をユーザーに紐付けます（他のユーザーの行にコピーされた値は復号できない）
+func secretCipherContext(userID int64) string {
+	return fmt.Sprintf("user_two_factor:%d", userID)
+}
+
+func (u *twoFactorUsecase) checkAttempts(ctx context.Context, userID int64) error {
+	if u.limiter == nil {
+		return nil
+	}
+
+	allowed, err := u.limiter.Allow(ctx, fmt.Sprintf("two_factor:user:%d", userID), twoFactorAttemptLimit, twoFactorAttemptWindow)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return domain.ErrRateLimited
+	}
+	return nil
+}
+
+// newRecoveryCode は "xxxxx-xxxxx" 形式のリカバリーコード（50bit）を生成します
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate recovery code: %w", err)
+	}
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeRecoveryCode は入力揺れ（大文字小文字・ハイフン・空白）を吸収します
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// Mock repository for testing
+type mockTwoFactorRepository struct {
+	settings      map[int64]*domain.TwoFactorSettings
+	recoveryCodes map[int64]map[string]bool
+}
+
+func newMockTwoFactorRepository() *mockTwoFactorRepository {
+	return &mockTwoFactorRepository{
+		settings:      make(map[int64]*domain.TwoFactorSettings),
+		recoveryCodes: make(map[int64]map[string]bool),
+	}
+}
+
+func (m *mockTwoFactorRepository) FindByUserID(ctx context.Context, userID int64) (*domain.TwoFactorSettings, error) {
+	settings, ok := m.settings[userID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *settings
+	return &copied, nil
+}
+
+func (m *mockTwoFactorRepository) SaveSecret(ctx context.Context, userID int64, secret string) error {
+	m.settings[userID] = &domain.TwoFactorSettings{UserID: userID, Secret: secret}
+	return nil
+}
+
+func (m *mockTwoFactorRepository) Enable(ctx context.Context, userID int64, step int64, recoveryCodeHashes []string) error {
+	now := time.Now()
+	m.settings[userID].EnabledAt = &now
+	m.settings[userID].LastUsedStep = step
+	m.recoveryCodes[userID] = make(map[string]bool)
+	for _, hash := range recoveryCodeHashes {
+		m.recoveryCodes[userID][hash] = true
+	}
+	return nil
+}
+
+func (m *mockTwoFactorRepository) Disable(ctx context.Context, userID int64) error {
+	delete(m.settings, userID)
+	delete(m.recoveryCodes, userID)
+	return nil
+}
+
+func (m *mockTwoFactorRepository) UseStep(ctx context.Context, userID int64, step int64) (bool, error) {
+	if step <= m.settings[userID].LastUsedStep {
+		return false, nil
+	}
+	m.settings[userID].LastUsedStep = step
+	return true, nil
+}
+
+func (m *mockTwoFactorRepository) ConsumeRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	if !m.recoveryCodes[userID][codeHash] {
+		return false, nil
+	}
+	delete(m.recoveryCodes[userID], codeHash)
+	return true, nil
+}
+
+// mockTOTP はcodesに登録したコードを対応する時間ステップとして受け付けます
+type mockTOTP struct {
+	codes map[string]int64
+}
+
+func (m *mockTOTP) GenerateSecret() (string, error) {
+	return "SECRET", nil
+}
+
+func (m *mockTOTP) ProvisioningURI(secret, accountName string) string {
+	return "otpauth://totp/test-api:" + accountName + "?secret=" + secret
+}
+
+func (m *mockTOTP) Validate(secret, code string, at time.Time) (int64, bool) {
+	if secret != "SECRET" {
+		return 0, false
+	}
+	step, ok := m.codes[code]
+	return step, ok
+}
+
+// mockSecretCipher は "enc(context):plaintext" 形式に変換します
+type mockSecretCipher struct{}
+
+func (mockSecretCipher) Encrypt(plaintext, context string) (string, error) {
+	return "enc(" + context + "):" + plaintext, nil
+}
+
+func (mockSecretCipher) Decrypt(ciphertext, context string) (string, error) {
+	plaintext, ok := strings.CutPrefix(ciphertext, "enc("+context+"):")
+	if !ok {
+		return "", errors.New("invalid ciphertext")
+	}
+	return plaintext, nil
+}
+
+func TestTwoFactorEnrollmentAndLogin(t *testing.T) {
+	authRepo := newMockAuthRepository()
+	userRepo := &mockUserRepository{}
+	totp := &mockTOTP{codes: map[string]int64{"111111": 100, "222222": 101}}
+	twoFactorRepo := newMockTwoFactorRepository()
+	twoFactor := NewTwoFactorUsecase(twoFactorRepo, userRepo, totp, mockSecretCipher{}, nil)
+	authUsecase := NewAuthUsecase(authRepo, plainHasher{}, &mockTokenIssuer{}, newMockRefreshTokenStore(), nil, twoFactor, nil)
+	ctx := context.Background()
+
+	user, _, err := authUsecase.Register(ctx, RegisterInput{Username: "alice", Email: "alice@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	userRepo.Create(ctx, &domain.User{Username: user.Username, Email: user.Email})
+
+	if _, err := twoFactor.Enable(ctx, user.ID, "111111"); !errors.Is(err, domain.ErrTwoFactorNotEnrolled) {
+		t.Fatalf("Expected ErrTwoFactorNotEnrolled, got %v", err)
+	}
+
+	enrollment, err := twoFactor.Enroll(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if enrollment.Secret != "SECRET" {
+		t.Errorf("Expected the plaintext secret in the enrollment, got %s", enrollment.Secret)
+	}
+	if stored := twoFactorRepo.settings[user.ID].Secret; stored == "SECRET" {
+		t.Errorf("Expected the secret to be stored encrypted, got %s", stored)
+	}
+	if !strings.Contains(enrollment.ProvisioningURI, "alice@example.com") {
+		t.Errorf("Expected provisioning URI for alice, got %s", enrollment.ProvisioningURI)
+	}
+
+	// enrollment alone does not require 2FA at login
+	result, err := authUsecase.Login(ctx, LoginInput{Login: "alice", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Challenge != nil {
+		t.Fatal("Expected no 2FA challenge before enabling")
+	}
+
+	if _, err := twoFactor.Enable(ctx, user.ID, "000000"); !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		t.Fatalf("Expected ErrInvalidTwoFactorCode, got %v", err)
+	}
+	recoveryCodes, err := twoFactor.Enable(ctx, user.ID, "111111")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(recoveryCodes) != recoveryCodeCount {
+		t.Fatalf("Expected %d recovery codes, got %d", recoveryCodeCount, len(recoveryCodes))
+	}
+
+	result, err = authUsecase.Login(ctx, LoginInput{Login: "alice", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Tokens != nil || result.Challenge == nil {
+		t.Fatal("Expected a 2FA challenge instead of tokens")
+	}
+	challenge := result.Challenge.ChallengeToken
+
+	// the challenge is not an access token
+	if _, err := authUsecase.Authenticate(ctx, challenge); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Fatalf("Expected ErrInvalidToken for challenge token, got %v", err)
+	}
+
+	// the code used to enable 2FA cannot be replayed
+	if _, err := authUsecase.CompleteTwoFactorLogin(ctx, challenge, "111111"); !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		t.Fatalf("Expected ErrInvalidTwoFactorCode for reused code, got %v", err)
+	}
+	if _, err := authUsecase.CompleteTwoFactorLogin(ctx, challenge, "222222"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// recovery codes are accepted once, ignoring case and separators
+	recovery := strings.ToUpper(strings.ReplaceAll(recoveryCodes[0], "-", ""))
+	if _, err := authUsecase.CompleteTwoFactorLogin(ctx, challenge, recovery); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := authUsecase.CompleteTwoFactorLogin(ctx, challenge, recoveryCodes[0]); !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		t.Fatalf("Expected ErrInvalidTwoFactorCode for used recovery code, got %v", err)
+	}
+}
+
+func TestTwoFactorDisableRequiresCode(t *testing.T) {
+	userRepo := &mockUserRepository{}
+	totp := &mockTOTP{codes: map[string]int64{"111111": 100, "222222": 101}}
+	twoFactorRepo := newMockTwoFactorRepository()
+	twoFactor := NewTwoFactorUsecase(twoFactorRepo, userRepo, totp, mockSecretCipher{}, nil)
+	ctx := context.Background()
+
+	user := &domain.User{Username: "alice", Email: "alice@example.com"}
+	userRepo.Create(ctx, user)
+
+	if _, err := twoFactor.Enroll(ctx, user.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := twoFactor.Enable(ctx, user.ID, "111111"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := twoFactor.Enroll(ctx, user.ID); !errors.Is(err, domain.ErrTwoFactorAlreadyEnabled) {
+		t.Fatalf("Expected ErrTwoFactorAlreadyEnabled, got %v", err)
+	}
+
+	if err := twoFactor.Disable(ctx, user.ID, "000000"); !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		t.Fatalf("Expected ErrInvalidTwoFactorCode, got %v", err)
+	}
+	if err := twoFactor.Disable(ctx, user.ID, "222222"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	enabled, err := twoFactor.IsEnabled(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if enabled {
+		t.Error("Expected 2FA to be disabled")
+	}
+}
@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	// タイムゾーンの検証をOSのzoneinfoに依存させない
+	_ "time/tzdata"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+const (
+	maxProfileNameLength        = 50
+	maxProfileDisplayNameLength = 100
+	maxProfileBioLength         = 2000
+	maxProfileURLLength         = 500
+	maxProfileTimezoneLength    = 50
+	maxProfileLanguageLength    = 35
+)
+
+var (
+	// E.164: 先頭の+と、0以外で始まる最大15桁
+	e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+	// BCP 47 language tag (language[-script][-region]*(-variant)*(-extension)*[-x-private])
+	bcp47Pattern = regexp.MustCompile(`^(?i)[a-z]{2,3}(-[a-z]{3}){0,3}(-[a-z]{4})?(-([a-z]{2}|[0-9]{3}))?(-([a-z0-9]{5,8}|[0-9][a-z0-9]{3}))*(-[0-9a-wyz](-[a-z0-9]{2,8})+)*(-x(-[a-z0-9]{1,8})+)?$`)
+
+	// isoCountryCodes はISO 3166-1 alpha-2で割り当て済みの国コード
+	isoCountryCodes = makeCountryCodeSet(`
+		AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS
+		BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE
+		EG EH ER ES ET FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM
+		HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC
+		LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA
+		NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW
+		SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO
+		TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW
+	`)
+)
+
+// UserProfileInput is the input for editing a profile.
+// PUT replaces all fields (omitted fields are cleared); PATCH only changes the fields present.
+// An empty string clears the field in both cases
+type UserProfileInput struct {
+	FirstName   *string `json:"firstName"`
+	LastName    *string `json:"lastName"`
+	DisplayName *string `json:"displayName"`
+	Bio         *string `json:"bio"`
+	BirthDate   *string `json:"birthDate"`
+	Gender      *string `json:"gender"`
+	CountryCode *string `json:"countryCode"`
+	Timezone    *string `json:"timezone"`
+	Language    *string `json:"language"`
+	PhoneNumber *string `json:"phoneNumber"`
+	WebsiteURL  *string `json:"websiteUrl"`
+}
+
+// UserProfileUsecase defines business logic for reading and editing user_profiles
+type UserProfileUsecase interface {
+	GetProfile(ctx context.Context, userID int64) (*domain.UserProfile, error)
+	ReplaceProfile(ctx context.Context, userID int64, input UserProfileInput) (*domain.UserProfile, error)
+	UpdateProfile(ctx context.Context, userID int64, input UserProfileInput) (*domain.UserProfile, error)
+}
+
+type userProfileUsecase struct {
+	profileRepo domain.UserProfileRepository
+	policy      Policy
+	now         func() time.Time
+}
+
+// NewUserProfileUsecase creates a new user profile usecase
+func NewUserProfileUsecase(profileRepo domain.UserProfileRepository, policy Policy) UserProfileUsecase {
+	return &userProfileUsecase{
+		profileRepo: profileRepo,
+		policy:      policy,
+		now:         time.Now,
+	}
+}
+
+// GetProfile retrieves the user's profile (domain.ErrNotFound if the user does not exist)
+func (u *userProfileUsecase) GetProfile(ctx context.Context, userID int64) (*domain.UserProfile, error) {
+	return u.profileRepo.FindByUserID(ctx, userID)
+}
+
+// ReplaceProfile replaces all editable fields (the user themselves or users.manage)
+func (u *userProfileUsecase) ReplaceProfile(ctx context.Context, userID int64, input UserProfileInput) (*domain.UserProfile, error) {
+	return u.saveProfile(ctx, userID, input, true)
+}
+
+// UpdateProfile changes only the fields present in the input (the user themselves or users.manage)
+func (u *userProfileUsecase) UpdateProfile(ctx context.Context, userID int64, input UserProfileInput) (*domain.UserProfile, error) {
+	return u.saveProfile(ctx, userID, input, false)
+}
+
+// saveProfile は入力を検証して既存のプロフィールに反映し、保存します。
+// 既存データは検証せず、入力に含まれる項目だけを検証します
+func (u *userProfileUsecase) saveProfile(ctx context.Context, userID int64, input UserProfileInput, replace bool) (*domain.UserProfile, error) {
+	if err := u.policy.RequireOwnerOr(ctx, userID, domain.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+
+	input, err := u.normalizeProfileInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := u.profileRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []struct {
+		dst **string
+		src *string
+	}{
+		{&profile.FirstName, input.FirstName},
+		{&profile.LastName, input.LastName},
+		{&profile.DisplayName, input.DisplayName},
+		{&profile.Bio, input.Bio},
+		{&profile.BirthDate, input.BirthDate},
+		{&profile.Gender, input.Gender},
+		{&profile.CountryCode, input.CountryCode},
+		{&profile.Timezone, input.Timezone},
+		{&profile.Language, input.Language},
+		{&profile.PhoneNumber, input.PhoneNumber},
+		{&profile.WebsiteURL, input.WebsiteURL},
+	}
+	for _, field := range fields {
+		switch {
+		case field.src == nil:
+			if replace {
+				*field.dst = nil
+			}
+		case *field.src == "":
+			*field.dst = nil
+		default:
+			value := *field.src
+			*field.dst = &value
+		}
+	}
+
+	if err := u.profileRepo.Upsert(ctx, userID, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// normalizeProfileInput は入力の前後の空白を除き、形式を検証して正規化した入力を返します
+func (u *userProfileUsecase) normalizeProfileInput(input UserProfileInput) (UserProfileInput, error) {
+	trim := func(value *string) *string {
+		if value == nil {
+			return nil
+		}
+		trimmed := strings.TrimSpace(*value)
+		return &trimmed
+	}
+	input.FirstName = trim(input.FirstName)
+	input.LastName = trim(input.LastName)
+	input.DisplayName = trim(input.DisplayName)
+	input.Bio = trim(input.Bio)
+	input.BirthDate = trim(input.BirthDate)
+	input.Gender = trim(input.Gender)
+	input.CountryCode = trim(input.CountryCode)
+	input.Timezone = trim(input.Timezone)
+	input.Language = trim(input.Language)
+	input.PhoneNumber = trim(input.PhoneNumber)
+	input.WebsiteURL = trim(input.WebsiteURL)
+
+	present := func(value *string) bool {
+		return value != nil && *value != ""
+	}
+
+	for _, field := range []struct {
+		name  string
+		value *string
+		max   int
+	}{
+		{"firstName", input.FirstName, maxProfileNameLength},
+		{"lastName", input.LastName, maxProfileNameLength},
+		{"displayName", input.DisplayName, maxProfileDisplayNameLength},
+		{"bio", input.Bio, maxProfileBioLength},
+	} {
+		if present(field.value) && utf8.RuneCountInString(*field.value) > field.max {
+			return input, fmt.Errorf("%w: %s must be at most %d characters", domain.ErrInvalidProfileInput, field.name, field.max)
+		}
+	}
+
+	if present(input.BirthDate) {
+		if err := checkBirthDate(*input.BirthDate, u.now()); err != nil {
+			return input, fmt.Errorf("%w: %v", domain.ErrInvalidProfileInput, err)
+		}
+	}
+
+	if present(input.Gender) {
+		switch *input.Gender {
+		case domain.GenderMale, domain.GenderFemale, domain.GenderOther, domain.GenderPreferNotToSay:
+		default:
+			return input, fmt.Errorf("%w: gender must be one of male, female, other, prefer_not_to_say", domain.ErrInvalidProfileInput)
+		}
+	}
+
+	if present(input.CountryCode) {
+		code := strings.ToUpper(*input.CountryCode)
+		if _, ok := isoCountryCodes[code]; !ok {
+			return input, fmt.Errorf("%w: countryCode must be an ISO 3166-1 alpha-2 code", domain.ErrInvalidProfileInput)
+		}
+		input.CountryCode = &code
+	}
+
+	if present(input.Timezone) {
+		// "Local"はサーバーのタイムゾーンを指すため受け付けない
+		tz := *input.Timezone
+		if len(tz) > maxProfileTimezoneLength || tz == "Local" {
+			return input, fmt.Errorf("%w: timezone must be an IANA time zone name", domain.ErrInvalidProfileInput)
+		}
+		if _, err := time.LoadLocation(tz); err != nil {
+			return input, fmt.Errorf("%w: timezone must be an IANA time zone name", domain.ErrInvalidProfileInput)
+		}
+	}
+
+	if present(input.Language) {
+		if len(*input.Language) > maxProfileLanguageLength || !bcp47Pattern.MatchString(*input.Language) {
+			return input, fmt.Errorf("%w: language must be a BCP 47 language tag", domain.ErrInvalidProfileInput)
+		}
+		language := canonicalLanguageTag(*input.Language)
+		input.Language = &language
+	}
+
+	if present(input.PhoneNumber) && !e164Pattern.MatchString(*input.PhoneNumber) {
+		return input, fmt.Errorf("%w: phoneNumber must be in E.164 format (e.g. +819012345678)", domain.ErrInvalidProfileInput)
+	}
+
+	if present(input.WebsiteURL) {
+		parsed, err := url.Parse(*input.WebsiteURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || len(*input.WebsiteURL) > maxProfileURLLength {
+			return input, fmt.Errorf("%w: websiteUrl must be an http(s) URL of at most %d characters", domain.ErrInvalidProfileInput, maxProfileURLLength)
+		}
+	}
+
+	return input, nil
+}
+
+// checkBirthDate は生年月日がYYYY-MM-DD形式で未来日でないことを確認します（ユーザーとプロフィールで共通）
+func checkBirthDate(value string, now time.Time) error {
+	birthDate, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return errors.New("birthDate must be YYYY-MM-DD")
+	}
+	// 最も進んだタイムゾーン（UTC+14）での今日までを許可する
+	today := now.UTC().Add(14 * time.Hour).Format("2006-01-02")
+	if birthDate.Format("2006-01-02") > today {
+		return errors.New("birthDate must not be in the future")
+	}
+	return nil
+}
+
+// canonicalLanguageTag はBCP 47の慣例に合わせて大文字・小文字を整えます（例: zh-hant-tw → zh-Hant-TW）
+func canonicalLanguageTag(tag string) string {
+	subtags := strings.Split(strings.ToLower(tag), "-")
+	for i := 1; i < len(subtags); i++ {
+		// 拡張・私用サブタグ以降はそのまま小文字
+		if len(subtags[i]) == 1 {
+			break
+		}
+		switch len(subtags[i]) {
+		case 2:
+			subtags[i] = strings.ToUpper(subtags[i])
+		case 4:
+			if subtags[i][0] < '0' || subtags[i][0] > '9' {
+				subtags[i] = strings.ToUpper(subtags[i][:1]) + subtags[i][1:]
+			}
+		}
+	}
+	return strings.Join(subtags, "-")
+}
+
+func makeCountryCodeSet(codes string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, code := range strings.Fields(codes) {
+		set[code] = struct{}{}
+	}
+	return set
+}
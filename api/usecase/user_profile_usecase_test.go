@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// Mock repository for testing
+type mockUserProfileRepository struct {
+	profiles map[int64]*domain.UserProfile
+}
+
+func (m *mockUserProfileRepository) FindByUserID(ctx context.Context, userID int64) (*domain.UserProfile, error) {
+	profile, ok := m.profiles[userID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *profile
+	return &copied, nil
+}
+
+func (m *mockUserProfileRepository) Upsert(ctx context.Context, userID int64, profile *domain.UserProfile) error {
+	copied := *profile
+	m.profiles[userID] = &copied
+	return nil
+}
+
+func (m *mockUserProfileRepository) UpdateAvatarURL(ctx context.Context, userID int64, avatarURL *string) error {
+	m.profiles[userID].AvatarURL = avatarURL
+	return nil
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestUpdateProfileOnlyChangesGivenFields(t *testing.T) {
+	repo := &mockUserProfileRepository{profiles: map[int64]*domain.UserProfile{
+		// 既存データは検証しない
+		1: {DisplayName: stringPtr("sakura"), PhoneNumber: stringPtr("090-1234-5678")},
+	}}
+	usecase := NewUserProfileUsecase(repo, allowAllPolicy{})
+	ctx := viewerContext(1)
+
+	profile, err := usecase.UpdateProfile(ctx, 1, UserProfileInput{
+		Bio:         stringPtr("  hello  "),
+		CountryCode: stringPtr("jp"),
+		Language:    stringPtr("zh-hant-tw"),
+		Timezone:    stringPtr("Asia/Tokyo"),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if profile.DisplayName == nil || *profile.DisplayName != "sakura" {
+		t.Errorf("Expected displayName to be kept, got %v", profile.DisplayName)
+	}
+	if *profile.Bio != "hello" || *profile.CountryCode != "JP" || *profile.Language != "zh-Hant-TW" {
+		t.Errorf("Unexpected normalized profile: bio=%q country=%q language=%q", *profile.Bio, *profile.CountryCode, *profile.Language)
+	}
+
+	// 空文字はクリア
+	profile, err = usecase.UpdateProfile(ctx, 1, UserProfileInput{PhoneNumber: stringPtr("")})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if profile.PhoneNumber != nil {
+		t.Errorf("Expected phoneNumber to be cleared, got %q", *profile.PhoneNumber)
+	}
+}
+
+func TestReplaceProfileClearsOmittedFields(t *testing.T) {
+	repo := &mockUserProfileRepository{profiles: map[int64]*domain.UserProfile{
+		1: {DisplayName: stringPtr("sakura"), Bio: stringPtr("old"), AvatarURL: stringPtr("/avatars/1.png")},
+	}}
+	usecase := NewUserProfileUsecase(repo, allowAllPolicy{})
+
+	profile, err := usecase.ReplaceProfile(viewerContext(1), 1, UserProfileInput{DisplayName: stringPtr("Sakura")})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if profile.Bio != nil {
+		t.Errorf("Expected bio to be cleared, got %q", *profile.Bio)
+	}
+	if profile.AvatarURL == nil {
+		t.Error("Expected avatarUrl to be kept")
+	}
+}
+
+func TestUpdateProfileValidation(t *testing.T) {
+	repo := &mockUserProfileRepository{profiles: map[int64]*domain.UserProfile{1: {}}}
+	usecase := NewUserProfileUsecase(repo, allowAllPolicy{})
+	ctx := viewerContext(1)
+	tomorrow := time.Now().AddDate(0, 0, 2).Format("2006-01-02")
+
+	invalid := map[string]UserProfileInput{
+		"timezone":    {Timezone: stringPtr("Mars/Olympus")},
+		"local tz":    {Timezone: stringPtr("Local")},
+		"country":     {CountryCode: stringPtr("XX")},
+		"language":    {Language: stringPtr("english")},
+		"phone":       {PhoneNumber: stringPtr("09012345678")},
+		"website":     {WebsiteURL: stringPtr("javascript:alert(1)")},
+		"birth date":  {BirthDate: stringPtr(tomorrow)},
+		"date format": {BirthDate: stringPtr("1990/01/01")},
+		"gender":      {Gender: stringPtr("unknown")},
+	}
+	for name, input := range invalid {
+		if _, err := usecase.UpdateProfile(ctx, 1, input); !errors.Is(err, domain.ErrInvalidProfileInput) {
+			t.Errorf("%s: expected ErrInvalidProfileInput, got %v", name, err)
+		}
+	}
+
+	_, err := usecase.UpdateProfile(ctx, 1, UserProfileInput{
+		PhoneNumber: stringPtr("+819012345678"),
+		WebsiteURL:  stringPtr("https://example.com/me"),
+		BirthDate:   stringPtr("1995-05-15"),
+		Language:    stringPtr("en-US"),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := usecase.UpdateProfile(ctx, 2, UserProfileInput{}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected domain.ErrNotFound for unknown user, got %v", err)
+	}
+}
@@ -2,44 +2,267 @@ package usecase
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/rssh-jp/test-api/api/domain"
 )
 
+const (
+	defaultUserListPageSize = 20
+	maxUserListPageSize     = 100
+	maxUsernamePrefixLength = 50
+)
+
+// UserListInput is the input for GET /users.
+// Page > 0 selects page-number paging (with a total count); otherwise Cursor is used
+type UserListInput struct {
+	Page           int
+	PageSize       int
+	Cursor         string
+	Status         string // カンマ区切り（例: "active,inactive"）
+	EmailVerified  *bool
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	UsernamePrefix string
+	Sort           string
+	Order          string
+}
+
+// CreateUserInput is the input for creating a user (users.manage only)
+type CreateUserInput struct {
+	Username      string
+	Email         string
+	BirthDate     *string // YYYY-MM-DD
+	EmailVerified bool
+}
+
+// UpdateUserInput changes only the fields present. An empty birthDate clears it
+type UpdateUserInput struct {
+	Username      *string
+	Email         *string
+	BirthDate     *string
+	EmailVerified *bool
+}
+
+// UserList is a page of users. Total is set only for page-number paging,
+// NextCursor only for cursor paging ("" if there is no next page)
+type UserList struct {
+	Users      []domain.User
+	Total      *int64
+	Page       int
+	PageSize   int
+	NextCursor string
+}
+
 // UserUsecase handles business logic for user operations
 type UserUsecase interface {
-	GetAllUsers(ctx context.Context) ([]domain.User, error)
+	ListUsers(ctx context.Context, input UserListInput) (*UserList, error)
 	GetUserByID(ctx context.Context, id int64) (*domain.User, error)
-	CreateUser(ctx context.Context, name, email string, age *int32) (*domain.User, error)
-	UpdateUser(ctx context.Context, id int64, name, email *string, age *int32) (*domain.User, error)
+	CreateUser(ctx context.Context, input CreateUserInput) (*domain.User, error)
+	UpdateUser(ctx context.Context, id int64, input UpdateUserInput) (*domain.User, error)
 	DeleteUser(ctx context.Context, id int64) error
 }
 
 type userUsecase struct {
 	userRepo domain.UserRepository
+	policy   Policy
+	now      func() time.Time
 }
 
 // NewUserUsecase creates a new user usecase
-func NewUserUsecase(userRepo domain.UserRepository) UserUsecase {
+func NewUserUsecase(userRepo domain.UserRepository, policy Policy) UserUsecase {
 	return &userUsecase{
 		userRepo: userRepo,
+		policy:   policy,
+		now:      time.Now,
+	}
+}
+
+// ListUsers returns a filtered, sorted page of users.
+// Suspended and deleted users are listed only when requested explicitly by users.manage.
+// Email, last login and birth date are returned only for the viewer themselves or to users.manage
+func (u *userUsecase) ListUsers(ctx context.Context, input UserListInput) (*UserList, error) {
+	query, err := u.buildUserListQuery(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UserList{PageSize: query.Limit}
+	if input.Page > 0 {
+		result.Page = input.Page
+		query.Offset = (input.Page - 1) * query.Limit
+	}
+
+	// カーソル方式は1件多く取得して次ページの有無を判定
+	limit := query.Limit
+	if input.Page == 0 {
+		query.Limit++
+	}
+	users, err := u.userRepo.FindAll(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	if input.Page > 0 {
+		total, err := u.userRepo.Count(ctx, query.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count users: %w", err)
+		}
+		result.Total = &total
+	} else if len(users) > limit {
+		users = users[:limit]
+		result.NextCursor = encodeUserCursor(query.Sort, query.Desc, users[len(users)-1])
+	}
+
+	if users == nil {
+		users = []domain.User{}
+	}
+	result.Users = u.hidePrivateFields(ctx, users)
+	return result, nil
+}
+
+// hidePrivateFields は閲覧者本人とusers.manage保持者以外にはメールアドレス等を返さないようにします
+// （リポジトリやキャッシュの値を書き換えないようコピーしてから消す）
+func (u *userUsecase) hidePrivateFields(ctx context.Context, users []domain.User) []domain.User {
+	if len(users) == 0 || u.policy.RequirePermission(ctx, domain.PermissionUsersManage) == nil {
+		return users
+	}
+	viewerID, _ := domain.ViewerIDFromContext(ctx)
+
+	users = slices.Clone(users)
+	for i := range users {
+		if users[i].ID != viewerID {
+			users[i].HidePrivateFields()
+		}
 	}
+	return users
 }
 
-func (u *userUsecase) GetAllUsers(ctx context.Context) ([]domain.User, error) {
-	return u.userRepo.FindAll(ctx)
+// buildUserListQuery は入力を検証してリポジトリの検索条件に変換します
+func (u *userUsecase) buildUserListQuery(ctx context.Context, input UserListInput) (domain.UserListQuery, error) {
+	query := domain.UserListQuery{Limit: input.PageSize}
+	if query.Limit < 1 || query.Limit > maxUserListPageSize {
+		query.Limit = defaultUserListPageSize
+	}
+	if input.Page < 0 {
+		return query, fmt.Errorf("%w: page must be 1 or greater", domain.ErrInvalidUserListQuery)
+	}
+	if input.Page > 0 && input.Cursor != "" {
+		return query, fmt.Errorf("%w: page and cursor cannot be used together", domain.ErrInvalidUserListQuery)
+	}
+
+	query.Sort = input.Sort
+	if query.Sort == "" {
+		query.Sort = domain.UserSortCreatedAt
+	}
+	switch query.Sort {
+	case domain.UserSortCreatedAt, domain.UserSortID:
+		query.Desc = true
+	case domain.UserSortUsername:
+	default:
+		return query, fmt.Errorf("%w: sort must be one of created_at, username, id", domain.ErrInvalidUserListQuery)
+	}
+	switch input.Order {
+	case "":
+	case "asc":
+		query.Desc = false
+	case "desc":
+		query.Desc = true
+	default:
+		return query, fmt.Errorf("%w: order must be asc or desc", domain.ErrInvalidUserListQuery)
+	}
+
+	filter, err := u.buildUserListFilter(ctx, input)
+	if err != nil {
+		return query, err
+	}
+	query.Filter = filter
+
+	after, err := decodeUserCursor(input.Cursor, query.Sort, query.Desc)
+	if err != nil {
+		return query, err
+	}
+	query.After = after
+
+	return query, nil
 }
 
+func (u *userUsecase) buildUserListFilter(ctx context.Context, input UserListInput) (domain.UserListFilter, error) {
+	filter := domain.UserListFilter{
+		EmailVerified:  input.EmailVerified,
+		CreatedFrom:    input.CreatedFrom,
+		CreatedTo:      input.CreatedTo,
+		UsernamePrefix: input.UsernamePrefix,
+	}
+
+	if input.Status == "" {
+		// 指定がない場合は停止中・削除済みのユーザーを出さない
+		filter.Statuses = []string{domain.UserStatusActive, domain.UserStatusInactive}
+	} else {
+		hidden := false
+		for _, status := range strings.Split(input.Status, ",") {
+			status = strings.TrimSpace(status)
+			switch status {
+			case domain.UserStatusActive, domain.UserStatusInactive:
+			case domain.UserStatusSuspended, domain.UserStatusDeleted:
+				hidden = true
+			default:
+				return filter, fmt.Errorf("%w: status must be a comma-separated list of active, inactive, suspended, deleted", domain.ErrInvalidUserListQuery)
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+		if hidden {
+			if err := u.policy.RequirePermission(ctx, domain.PermissionUsersManage); err != nil {
+				return filter, err
+			}
+		}
+	}
+
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return filter, fmt.Errorf("%w: createdFrom must be before createdTo", domain.ErrInvalidUserListQuery)
+	}
+	if utf8.RuneCountInString(filter.UsernamePrefix) > maxUsernamePrefixLength {
+		return filter, fmt.Errorf("%w: usernamePrefix must be at most %d characters", domain.ErrInvalidUserListQuery, maxUsernamePrefixLength)
+	}
+
+	return filter, nil
+}
+
+// GetUserByID returns a user, hiding the private fields as ListUsers does
 func (u *userUsecase) GetUserByID(ctx context.Context, id int64) (*domain.User, error) {
-	return u.userRepo.FindByID(ctx, id)
+	user, err := u.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &u.hidePrivateFields(ctx, []domain.User{*user})[0], nil
 }
 
-func (u *userUsecase) CreateUser(ctx context.Context, name, email string, age *int32) (*domain.User, error) {
+// CreateUser creates a user without a password (users.manage only; self-registration uses AuthUsecase.Register)
+func (u *userUsecase) CreateUser(ctx context.Context, input CreateUserInput) (*domain.User, error) {
+	if err := u.policy.RequirePermission(ctx, domain.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+
 	user := &domain.User{
-		Name:  name,
-		Email: email,
-		Age:   age,
+		Username:      strings.TrimSpace(input.Username),
+		Email:         strings.TrimSpace(input.Email),
+		Status:        domain.UserStatusActive,
+		EmailVerified: input.EmailVerified,
 	}
+	if input.BirthDate != nil && *input.BirthDate != "" {
+		user.BirthDate = input.BirthDate
+	}
+	if err := u.validateUser(user); err != nil {
+		return nil, err
+	}
+
 	err := u.userRepo.Create(ctx, user)
 	if err != nil {
 		return nil, err
@@ -47,20 +270,44 @@ func (u *userUsecase) CreateUser(ctx context.Context, name, email string, age *i
 	return user, nil
 }
 
-func (u *userUsecase) UpdateUser(ctx context.Context, id int64, name, email *string, age *int32) (*domain.User, error) {
+// UpdateUser updates the fields present in the input (the user themselves or users.manage).
+// Changing the email resets emailVerified; only users.manage can set emailVerified
+func (u *userUsecase) UpdateUser(ctx context.Context, id int64, input UpdateUserInput) (*domain.User, error) {
+	if err := u.policy.RequireOwnerOr(ctx, id, domain.PermissionUsersManage); err != nil {
+		return nil, err
+	}
+	if input.EmailVerified != nil {
+		if err := u.policy.RequirePermission(ctx, domain.PermissionUsersManage); err != nil {
+			return nil, err
+		}
+	}
+
 	user, err := u.userRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if name != nil {
-		user.Name = *name
+	if input.Username != nil {
+		user.Username = strings.TrimSpace(*input.Username)
+	}
+	if input.Email != nil {
+		email := strings.TrimSpace(*input.Email)
+		if email != user.Email {
+			user.EmailVerified = false
+		}
+		user.Email = email
 	}
-	if email != nil {
-		user.Email = *email
+	if input.BirthDate != nil {
+		user.BirthDate = input.BirthDate
+		if *input.BirthDate == "" {
+			user.BirthDate = nil
+		}
 	}
-	if age != nil {
-		user.Age = age
+	if input.EmailVerified != nil {
+		user.EmailVerified = *input.EmailVerified
+	}
+	if err := u.validateUser(user); err != nil {
+		return nil, err
 	}
 
 	err = u.userRepo.Update(ctx, user)
@@ -70,6 +317,79 @@ func (u *userUsecase) UpdateUser(ctx context.Context, id int64, name, email *str
 	return user, nil
 }
 
+// validateUser は登録と同じ規則でユーザー名とメールアドレスを、プロフィールと同じ規則で生年月日を検証します
+func (u *userUsecase) validateUser(user *domain.User) error {
+	if !usernamePattern.MatchString(user.Username) {
+		return fmt.Errorf("%w: username must be 3-50 letters, digits or underscores", domain.ErrInvalidUserInput)
+	}
+	if addr, err := mail.ParseAddress(user.Email); err != nil || addr.Address != user.Email || len(user.Email) > 255 {
+		return fmt.Errorf("%w: email is invalid", domain.ErrInvalidUserInput)
+	}
+	if user.BirthDate != nil {
+		if err := checkBirthDate(*user.BirthDate, u.now()); err != nil {
+			return fmt.Errorf("%w: %v", domain.ErrInvalidUserInput, err)
+		}
+	}
+	return nil
+}
+
+// DeleteUser deletes the user (the user themselves or users.manage)
 func (u *userUsecase) DeleteUser(ctx context.Context, id int64) error {
+	if err := u.policy.RequireOwnerOr(ctx, id, domain.PermissionUsersManage); err != nil {
+		return err
+	}
+
 	return u.userRepo.Delete(ctx, id)
 }
+
+// encodeUserCursor はカーソルを不透明な文字列（base64url）に変換します。
+// 並び順が変わった場合に検出できるよう、並び替えの列と向きを含めます
+func encodeUserCursor(sort string, desc bool, last domain.User) string {
+	value := ""
+	switch sort {
+	case domain.UserSortCreatedAt:
+		value = strconv.FormatInt(last.CreatedAt.UnixNano(), 10)
+	case domain.UserSortUsername:
+		value = last.Username
+	}
+	raw := fmt.Sprintf("%s:%t:%d:%s", sort, desc, last.ID, value)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeUserCursor(cursor, sort string, desc bool) (*domain.UserCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidCursor)
+	}
+
+	// ユーザー名に":"が含まれても良いよう、値は最後に置く
+	parts := strings.SplitN(string(raw), ":", 4)
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidCursor)
+	}
+	if parts[0] != sort || parts[1] != strconv.FormatBool(desc) {
+		return nil, fmt.Errorf("%w: cursor does not match the sort order", domain.ErrInvalidCursor)
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidCursor)
+	}
+
+	after := &domain.UserCursor{ID: id}
+	switch sort {
+	case domain.UserSortCreatedAt:
+		nanos, err := strconv.ParseInt(parts[3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidCursor)
+		}
+		after.CreatedAt = time.Unix(0, nanos)
+	case domain.UserSortUsername:
+		after.Username = parts[3]
+	}
+
+	return after, nil
+}
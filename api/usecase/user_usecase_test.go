@@ -2,7 +2,12 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/rssh-jp/test-api/api/domain"
 )
@@ -12,8 +17,45 @@ type mockUserRepository struct {
 	users []domain.User
 }
 
-func (m *mockUserRepository) FindAll(ctx context.Context) ([]domain.User, error) {
-	return m.users, nil
+// FindAll は並び替えをIDのみサポートします
+func (m *mockUserRepository) FindAll(ctx context.Context, query domain.UserListQuery) ([]domain.User, error) {
+	users := m.filter(query.Filter)
+	sort.Slice(users, func(i, j int) bool {
+		if query.Desc {
+			return users[i].ID > users[j].ID
+		}
+		return users[i].ID < users[j].ID
+	})
+
+	var page []domain.User
+	for _, user := range users {
+		if query.After != nil && (query.Desc && user.ID >= query.After.ID || !query.Desc && user.ID <= query.After.ID) {
+			continue
+		}
+		page = append(page, user)
+	}
+	if query.Offset >= len(page) {
+		return nil, nil
+	}
+	page = page[query.Offset:]
+	if len(page) > query.Limit {
+		page = page[:query.Limit]
+	}
+	return page, nil
+}
+
+func (m *mockUserRepository) Count(ctx context.Context, filter domain.UserListFilter) (int64, error) {
+	return int64(len(m.filter(filter))), nil
+}
+
+func (m *mockUserRepository) filter(filter domain.UserListFilter) []domain.User {
+	var users []domain.User
+	for _, user := range m.users {
+		if strings.HasPrefix(user.Username, filter.UsernamePrefix) {
+			users = append(users, user)
+		}
+	}
+	return users
 }
 
 func (m *mockUserRepository) FindByID(ctx context.Context, id int64) (*domain.User, error) {
@@ -22,7 +64,19 @@ func (m *mockUserRepository) FindByID(ctx context.Context, id int64) (*domain.Us
 			return &user, nil
 		}
 	}
-	return nil, nil
+	return nil, domain.NotFoundError("user")
+}
+
+func (m *mockUserRepository) FindByUsernames(ctx context.Context, usernames []string) ([]domain.User, error) {
+	var users []domain.User
+	for _, user := range m.users {
+		for _, username := range usernames {
+			if user.Username == username {
+				users = append(users, user)
+			}
+		}
+	}
+	return users, nil
 }
 
 func (m *mockUserRepository) Create(ctx context.Context, user *domain.User) error {
@@ -48,29 +102,159 @@ func (m *mockUserRepository) Delete(ctx context.Context, id int64) error {
 			return nil
 		}
 	}
-	return nil
+	return domain.NotFoundError("user")
 }
 
-func TestGetAllUsers(t *testing.T) {
+func TestListUsers(t *testing.T) {
 	mockRepo := &mockUserRepository{
 		users: []domain.User{
-			{ID: 1, Name: "Test User", Email: "test@example.com"},
+			{ID: 1, Username: "Test User", Email: "test@example.com"},
 		},
 	}
-	usecase := NewUserUsecase(mockRepo)
+	usecase := NewUserUsecase(mockRepo, allowAllPolicy{})
+
+	ctx := context.Background()
+	list, err := usecase.ListUsers(ctx, UserListInput{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(list.Users) != 1 {
+		t.Fatalf("Expected 1 user, got %d", len(list.Users))
+	}
+
+	if list.Users[0].Username != "Test User" {
+		t.Errorf("Expected user name 'Test User', got '%s'", list.Users[0].Username)
+	}
+}
 
+func TestListUsersPaging(t *testing.T) {
+	mockRepo := &mockUserRepository{}
+	for i := 1; i <= 5; i++ {
+		mockRepo.users = append(mockRepo.users, domain.User{ID: int64(i), Username: fmt.Sprintf("user%d", i)})
+	}
+	usecase := NewUserUsecase(mockRepo, allowAllPolicy{})
 	ctx := context.Background()
-	users, err := usecase.GetAllUsers(ctx)
+
+	// カーソル方式: 5 → 4 → 3 → 2 → 1 の順に2件ずつ
+	var ids []int64
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("Expected paging to finish in 3 pages")
+		}
+		list, err := usecase.ListUsers(ctx, UserListInput{Sort: domain.UserSortID, PageSize: 2, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if list.Total != nil {
+			t.Error("Expected no total for cursor paging")
+		}
+		for _, user := range list.Users {
+			ids = append(ids, user.ID)
+		}
+		if list.NextCursor == "" {
+			break
+		}
+		cursor = list.NextCursor
+	}
+	if fmt.Sprint(ids) != "[5 4 3 2 1]" {
+		t.Errorf("Expected [5 4 3 2 1], got %v", ids)
+	}
+
+	// 並び順が違うカーソルは使えない
+	first, _ := usecase.ListUsers(ctx, UserListInput{Sort: domain.UserSortID, PageSize: 2})
+	if _, err := usecase.ListUsers(ctx, UserListInput{Sort: domain.UserSortID, Order: "asc", Cursor: first.NextCursor}); !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+
+	// ページ番号方式
+	list, err := usecase.ListUsers(ctx, UserListInput{Sort: domain.UserSortID, Order: "asc", Page: 2, PageSize: 2, UsernamePrefix: "user"})
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
+	if list.Total == nil || *list.Total != 5 || list.NextCursor != "" {
+		t.Errorf("Expected total 5 and no cursor, got total=%v cursor=%q", list.Total, list.NextCursor)
+	}
+	if len(list.Users) != 2 || list.Users[0].ID != 3 {
+		t.Errorf("Expected users 3 and 4, got %v", list.Users)
+	}
+}
+
+func TestListUsersValidation(t *testing.T) {
+	policy := NewPolicy(&mockRoleRepository{permissions: map[int64][]string{
+		1: {domain.PermissionUsersManage},
+	}}, nil)
+	usecase := NewUserUsecase(&mockUserRepository{}, policy)
+
+	invalid := map[string]UserListInput{
+		"sort":   {Sort: "email"},
+		"order":  {Order: "up"},
+		"status": {Status: "active,banned"},
+		"paging": {Page: 1, Cursor: "abc"},
+	}
+	for name, input := range invalid {
+		if _, err := usecase.ListUsers(viewerContext(1), input); !errors.Is(err, domain.ErrInvalidUserListQuery) {
+			t.Errorf("%s: expected ErrInvalidUserListQuery, got %v", name, err)
+		}
+	}
+
+	// 停止中・削除済みのユーザーはusers.manageのみ一覧できる
+	if _, err := usecase.ListUsers(viewerContext(2), UserListInput{Status: "suspended"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+	if _, err := usecase.ListUsers(viewerContext(1), UserListInput{Status: "active,deleted"}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestUserPrivateFieldsAreHiddenFromOtherUsers(t *testing.T) {
+	lastLogin := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockRepo := &mockUserRepository{users: []domain.User{
+		{ID: 1, Username: "alice", Email: "alice@example.com", LastLoginAt: &lastLogin, BirthDate: stringPtr("1995-05-15")},
+		{ID: 2, Username: "bob", Email: "bob@example.com", LastLoginAt: &lastLogin, BirthDate: stringPtr("1990-01-01")},
+	}}
+	policy := NewPolicy(&mockRoleRepository{permissions: map[int64][]string{
+		3: {domain.PermissionUsersManage},
+	}}, nil)
+	usecase := NewUserUsecase(mockRepo, policy)
+
+	hidden := func(user domain.User) bool {
+		return user.Email == "" && user.LastLoginAt == nil && user.BirthDate == nil
+	}
 
-	if len(users) != 1 {
-		t.Fatalf("Expected 1 user, got %d", len(users))
+	// 匿名・他人には非公開項目を返さない
+	for name, ctx := range map[string]context.Context{"anonymous": context.Background(), "other user": viewerContext(2)} {
+		user, err := usecase.GetUserByID(ctx, 1)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", name, err)
+		}
+		if user.Username != "alice" || !hidden(*user) {
+			t.Errorf("%s: expected private fields to be hidden, got %+v", name, user)
+		}
+	}
+
+	// 本人とusers.manageには返す
+	for name, ctx := range map[string]context.Context{"self": viewerContext(1), "users.manage": viewerContext(3)} {
+		user, err := usecase.GetUserByID(ctx, 1)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", name, err)
+		}
+		if user.Email != "alice@example.com" || user.LastLoginAt == nil || user.BirthDate == nil {
+			t.Errorf("%s: expected private fields, got %+v", name, user)
+		}
 	}
 
-	if users[0].Name != "Test User" {
-		t.Errorf("Expected user name 'Test User', got '%s'", users[0].Name)
+	// 一覧では本人の行だけ非公開項目を返し、リポジトリの値は書き換えない
+	list, err := usecase.ListUsers(viewerContext(2), UserListInput{Sort: domain.UserSortID, Order: "asc"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(list.Users) != 2 || !hidden(list.Users[0]) || list.Users[1].Email != "bob@example.com" {
+		t.Errorf("Expected only the viewer's own private fields, got %+v", list.Users)
+	}
+	if mockRepo.users[0].Email != "alice@example.com" {
+		t.Errorf("Expected the repository's users to be left untouched, got %+v", mockRepo.users[0])
 	}
 }
 
@@ -78,20 +262,127 @@ func TestCreateUser(t *testing.T) {
 	mockRepo := &mockUserRepository{
 		users: []domain.User{},
 	}
-	usecase := NewUserUsecase(mockRepo)
+	usecase := NewUserUsecase(mockRepo, allowAllPolicy{})
 
 	ctx := context.Background()
-	age := int32(25)
-	user, err := usecase.CreateUser(ctx, "New User", "new@example.com", &age)
+	user, err := usecase.CreateUser(ctx, CreateUserInput{Username: "new_user", Email: "new@example.com", BirthDate: stringPtr("2000-04-01")})
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
-	if user.Name != "New User" {
-		t.Errorf("Expected user name 'New User', got '%s'", user.Name)
+	if user.Username != "new_user" {
+		t.Errorf("Expected username 'new_user', got '%s'", user.Username)
 	}
 
 	if user.ID == 0 {
 		t.Error("Expected user ID to be set")
 	}
+
+	if age := user.Age(time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)); age == nil || *age != 24 {
+		t.Errorf("Expected age 24 the day before the birthday, got %v", age)
+	}
+
+	invalid := map[string]CreateUserInput{
+		"username":   {Username: "New User", Email: "new@example.com"},
+		"email":      {Username: "new_user2", Email: "not-an-email"},
+		"birth date": {Username: "new_user2", Email: "new2@example.com", BirthDate: stringPtr("2000/04/01")},
+	}
+	for name, input := range invalid {
+		if _, err := usecase.CreateUser(ctx, input); !errors.Is(err, domain.ErrInvalidUserInput) {
+			t.Errorf("%s: expected ErrInvalidUserInput, got %v", name, err)
+		}
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	mockRepo := &mockUserRepository{
+		users: []domain.User{
+			{ID: 1, Username: "alice", Email: "alice@example.com", EmailVerified: true, BirthDate: stringPtr("1990-01-01")},
+		},
+	}
+	policy := NewPolicy(&mockRoleRepository{permissions: map[int64][]string{
+		2: {domain.PermissionUsersManage},
+	}}, nil)
+	usecase := NewUserUsecase(mockRepo, policy)
+
+	// メールアドレスを変えると未認証に戻る
+	user, err := usecase.UpdateUser(viewerContext(1), 1, UpdateUserInput{Email: stringPtr("alice@example.org"), BirthDate: stringPtr("")})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.EmailVerified || user.BirthDate != nil || user.Username != "alice" {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+
+	// 認証状態はusers.manageのみ変更できる
+	verified := true
+	if _, err := usecase.UpdateUser(viewerContext(1), 1, UpdateUserInput{EmailVerified: &verified}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+	user, err = usecase.UpdateUser(viewerContext(2), 1, UpdateUserInput{EmailVerified: &verified})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !user.EmailVerified {
+		t.Error("Expected emailVerified to be set")
+	}
+}
+
+func TestUserErrorKinds(t *testing.T) {
+	mockRepo := &mockUserRepository{
+		users: []domain.User{
+			{ID: 1, Username: "alice", Email: "alice@example.com"},
+		},
+	}
+	policy := NewPolicy(&mockRoleRepository{permissions: map[int64][]string{
+		2: {domain.PermissionUsersManage},
+	}}, nil)
+	usecase := NewUserUsecase(mockRepo, policy)
+	_, validationErr := usecase.UpdateUser(viewerContext(1), 1, UpdateUserInput{Username: stringPtr("a b")})
+
+	tests := map[string]struct {
+		err  error
+		kind domain.ErrorKind
+		code string
+	}{
+		"not found": {
+			err:  usecase.DeleteUser(viewerContext(2), 99),
+			kind: domain.KindNotFound,
+			code: "not_found",
+		},
+		"validation": {
+			err:  validationErr,
+			kind: domain.KindValidation,
+			code: "invalid_input",
+		},
+		"forbidden": {
+			err:  usecase.DeleteUser(viewerContext(3), 1),
+			kind: domain.KindForbidden,
+			code: "forbidden",
+		},
+		"unauthenticated": {
+			err:  usecase.DeleteUser(context.Background(), 1),
+			kind: domain.KindUnauthenticated,
+			code: "unauthenticated",
+		},
+	}
+	for name, tt := range tests {
+		if got := domain.KindOf(tt.err); got != tt.kind {
+			t.Errorf("%s: expected kind %v, got %v (%v)", name, tt.kind, got, tt.err)
+		}
+		if got := domain.CodeOf(tt.err); got != tt.code {
+			t.Errorf("%s: expected code %q, got %q", name, tt.code, got)
+		}
+	}
+
+	// 種別の汎用エラーは同じ種別の個別エラーに一致し、個別エラー同士は区別される
+	if !errors.Is(domain.ErrUserAlreadyExists, domain.ErrConflict) || errors.Is(domain.ErrUserAlreadyExists, domain.ErrNotFound) {
+		t.Error("Expected ErrUserAlreadyExists to match only ErrConflict")
+	}
+	if errors.Is(domain.ErrInvalidPostStatusTransition, domain.ErrInvalidUserStatusTransition) {
+		t.Error("Expected distinct conflict errors not to match each other")
+	}
+	if domain.CodeOf(errors.New("boom")) != "internal_error" {
+		t.Error("Expected non-domain errors to be internal_error")
+	}
 }
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rssh-jp/test-api/api/domain"
+)
+
+// Mock view counter for testing
+type mockViewCounter struct {
+	postRepo domain.PostRepository
+	pending  map[int64]int64
+	seen     map[string]bool
+}
+
+func newMockViewCounter(postRepo domain.PostRepository) *mockViewCounter {
+	return &mockViewCounter{
+		postRepo: postRepo,
+		pending:  make(map[int64]int64),
+		seen:     make(map[string]bool),
+	}
+}
+
+func (m *mockViewCounter) Record(ctx context.Context, postID int64, viewer string) error {
+	if viewer != "" {
+		key := fmt.Sprintf("%s/%d", viewer, postID)
+		if m.seen[key] {
+			return nil
+		}
+		m.seen[key] = true
+	}
+	m.pending[postID]++
+	return nil
+}
+
+func (m *mockViewCounter) Pending(ctx context.Context, postIDs []int64) (map[int64]int64, error) {
+	pending := make(map[int64]int64)
+	for _, id := range postIDs {
+		pending[id] = m.pending[id]
+	}
+	return pending, nil
+}
+
+func (m *mockViewCounter) Flush(ctx context.Context) (int, error) {
+	if err := m.postRepo.AddViewCounts(ctx, m.pending); err != nil {
+		return 0, err
+	}
+	n := len(m.pending)
+	m.pending = make(map[int64]int64)
+	return n, nil
+}
+
+func TestGetPostByIDAddsPendingViews(t *testing.T) {
+	mockRepo := newMockPostRepository()
+	mockRepo.posts[1] = &domain.Post{ID: 1, Status: domain.PostStatusPublished, ViewCount: 10}
+	counter := newMockViewCounter(mockRepo)
+	usecase := NewPostUsecase(mockRepo, nil, nil, counter, allowAllPolicy{})
+
+	// 同じ訪問者の再閲覧は数えない
+	visitor := domain.ContextWithVisitor(context.Background(), "192.0.2.1")
+	for i := 0; i < 2; i++ {
+		if _, err := usecase.GetPostByID(visitor, 1); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	post, err := usecase.GetPostByID(viewerContext(7), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if post.ViewCount != 12 {
+		t.Errorf("Expected view count 12 (10 in DB + 2 pending), got %d", post.ViewCount)
+	}
+	if mockRepo.posts[1].ViewCount != 10 {
+		t.Errorf("Expected DB view count to stay 10 before flush, got %d", mockRepo.posts[1].ViewCount)
+	}
+}
+
+func TestViewCountFlusherDrainsOnShutdown(t *testing.T) {
+	mockRepo := newMockPostRepository()
+	mockRepo.posts[1] = &domain.Post{ID: 1, Status: domain.PostStatusPublished}
+	counter := newMockViewCounter(mockRepo)
+	counter.pending[1] = 3
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewViewCountFlusher(counter, time.Hour).Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	<-done
+
+	if mockRepo.posts[1].ViewCount != 3 {
+		t.Errorf("Expected view count 3 after drain, got %d", mockRepo.posts[1].ViewCount)
+	}
+	if len(counter.pending) != 0 {
+		t.Errorf("Expected no pending views after drain, got %v", counter.pending)
+	}
+}
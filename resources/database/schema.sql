@@ -38,8 +38,8 @@ CREATE TABLE IF NOT EXISTS user_profiles (
     gender ENUM('male', 'female', 'other', 'prefer_not_to_say') COMMENT '性別',
     country_code CHAR(2) COMMENT '国コード（ISO 3166-1 alpha-2）',
     timezone VARCHAR(50) DEFAULT 'UTC' COMMENT 'タイムゾーン',
-    language_code CHAR(2) DEFAULT 'en' COMMENT '言語コード',
-    phone_number VARCHAR(20) COMMENT '電話番号',
+    language_code VARCHAR(35) DEFAULT 'en' COMMENT '言語コード（BCP 47）',
+    phone_number VARCHAR(20) COMMENT '電話番号（E.164）',
     website_url VARCHAR(500) COMMENT 'ウェブサイトURL',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
@@ -104,6 +104,7 @@ CREATE TABLE IF NOT EXISTS posts (
     INDEX idx_like_count (like_count),
     INDEX idx_is_featured (is_featured),
     INDEX idx_created_at (created_at),
+    INDEX idx_user_status_published (user_id, status, published_at),
     FULLTEXT INDEX idx_fulltext_search (title, content)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='投稿';
 
@@ -212,16 +213,148 @@ CREATE TABLE IF NOT EXISTS notifications (
     INDEX idx_created_at (created_at)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='通知';
 
+-- =====================================================
+-- メール認証トークンテーブル
+-- =====================================================
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL COMMENT 'ユーザーID',
+    token_hash CHAR(64) NOT NULL UNIQUE COMMENT 'トークンのSHA-256（平文は保存しない）',
+    expires_at TIMESTAMP NOT NULL COMMENT '有効期限',
+    used_at TIMESTAMP NULL COMMENT '使用日時（使用済み・無効化済みはNULL以外）',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_id (user_id),
+    INDEX idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='メール認証トークン';
+
+-- =====================================================
+-- パスワードリセットトークンテーブル
+-- =====================================================
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL COMMENT 'ユーザーID',
+    token_hash CHAR(64) NOT NULL UNIQUE COMMENT 'トークンのSHA-256（平文は保存しない）',
+    expires_at TIMESTAMP NOT NULL COMMENT '有効期限',
+    used_at TIMESTAMP NULL COMMENT '使用日時（使用済み・無効化済みはNULL以外）',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_id (user_id),
+    INDEX idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='パスワードリセットトークン';
+
+-- =====================================================
+-- 2段階認証（TOTP）テーブル
+-- =====================================================
+CREATE TABLE IF NOT EXISTS user_two_factor (
+    user_id BIGINT PRIMARY KEY COMMENT 'ユーザーID',
+    secret VARCHAR(255) NOT NULL COMMENT 'TOTPシークレット（AES-256-GCMで暗号化）',
+    last_used_step BIGINT NOT NULL DEFAULT 0 COMMENT '最後に使用したTOTPの時間ステップ（再利用防止）',
+    enabled_at TIMESTAMP NULL COMMENT '有効化日時（NULLは登録途中）',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='2段階認証設定';
+
+-- =====================================================
+-- 2段階認証リカバリーコードテーブル
+-- =====================================================
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL COMMENT 'ユーザーID',
+    code_hash CHAR(64) NOT NULL COMMENT 'リカバリーコードのSHA-256（平文は保存しない）',
+    used_at TIMESTAMP NULL COMMENT '使用日時（未使用はNULL）',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_user_code (user_id, code_hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='2段階認証リカバリーコード';
+
+-- =====================================================
+-- APIキーテーブル（バッチなどのマシンクライアント用）
+-- =====================================================
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL COMMENT 'ユーザーID',
+    name VARCHAR(100) NOT NULL COMMENT 'キー名（用途）',
+    prefix VARCHAR(16) NOT NULL UNIQUE COMMENT 'キーの公開部分（検索用）',
+    secret_hash CHAR(64) NOT NULL COMMENT 'シークレットのSHA-256（平文は保存しない）',
+    scopes VARCHAR(255) NOT NULL COMMENT 'スコープ（カンマ区切り: read, write）',
+    last_used_at TIMESTAMP NULL COMMENT '最終使用日時',
+    revoked_at TIMESTAMP NULL COMMENT '失効日時（有効なキーはNULL）',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='APIキー';
+
+-- =====================================================
+-- アカウント状態変更履歴テーブル（停止・削除・復元の理由を記録）
+-- =====================================================
+CREATE TABLE IF NOT EXISTS user_status_history (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL COMMENT 'ユーザーID',
+    from_status ENUM('active', 'inactive', 'suspended', 'deleted') NOT NULL COMMENT '変更前の状態',
+    to_status ENUM('active', 'inactive', 'suspended', 'deleted') NOT NULL COMMENT '変更後の状態',
+    reason VARCHAR(255) NULL COMMENT '理由',
+    changed_by BIGINT NULL COMMENT '操作したユーザーID',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (changed_by) REFERENCES users(id) ON DELETE SET NULL,
+    INDEX idx_user_created (user_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='アカウント状態変更履歴';
+
+-- =====================================================
+-- ロール・権限テーブル（RBAC）
+-- =====================================================
+CREATE TABLE IF NOT EXISTS roles (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE COMMENT 'ロール名',
+    description VARCHAR(255) COMMENT '説明',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='ロール';
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE COMMENT '権限名（resource.action）',
+    description VARCHAR(255) COMMENT '説明',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='権限';
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id BIGINT NOT NULL,
+    permission_id BIGINT NOT NULL,
+    
+    PRIMARY KEY (role_id, permission_id),
+    FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='ロールと権限の対応';
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id BIGINT NOT NULL,
+    role_id BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '付与日時',
+    
+    PRIMARY KEY (user_id, role_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    INDEX idx_role_id (role_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='ユーザーに付与されたロール';
+
 -- =====================================================
 -- サンプルデータ投入
 -- =====================================================
 
--- ユーザーデータ
+-- ユーザーデータ（パスワードは全員 "password"）
 INSERT INTO users (username, email, password_hash, status, email_verified, last_login_at) VALUES
-    ('sakura', 'sakura@example.com', '$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy', 'active', TRUE, NOW() - INTERVAL 1 HOUR),
-    ('takeshi', 'takeshi@example.com', '$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy', 'active', TRUE, NOW() - INTERVAL 2 DAY),
-    ('yuki', 'yuki@example.com', '$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy', 'active', TRUE, NOW() - INTERVAL 5 DAY),
-    ('haruto', 'haruto@example.com', '$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy', 'inactive', FALSE, NULL)
+    ('sakura', 'sakura@example.com', '$2a$10$sK0/6jVsC/XF5Ndj7k8OZuSaOT1IR8HSS7lKz68y8htrwKuUt2PXO', 'active', TRUE, NOW() - INTERVAL 1 HOUR),
+    ('takeshi', 'takeshi@example.com', '$2a$10$sK0/6jVsC/XF5Ndj7k8OZuSaOT1IR8HSS7lKz68y8htrwKuUt2PXO', 'active', TRUE, NOW() - INTERVAL 2 DAY),
+    ('yuki', 'yuki@example.com', '$2a$10$sK0/6jVsC/XF5Ndj7k8OZuSaOT1IR8HSS7lKz68y8htrwKuUt2PXO', 'active', TRUE, NOW() - INTERVAL 5 DAY),
+    ('haruto', 'haruto@example.com', '$2a$10$sK0/6jVsC/XF5Ndj7k8OZuSaOT1IR8HSS7lKz68y8htrwKuUt2PXO', 'inactive', FALSE, NULL)
 ON DUPLICATE KEY UPDATE username=VALUES(username);
 
 -- プロフィールデータ
@@ -232,6 +365,36 @@ INSERT INTO user_profiles (user_id, first_name, last_name, display_name, bio, bi
     (4, '陽翔', '高橋', 'はると', 'マイクロサービスを専門とするバックエンドエンジニアです。', '1992-11-30', 'male', 'JP', 'Asia/Tokyo')
 ON DUPLICATE KEY UPDATE user_id=VALUES(user_id);
 
+-- ロール・権限データ
+INSERT INTO roles (name, description) VALUES
+    ('moderator', 'コメントのモデレーションと投稿の非公開化'),
+    ('admin', '全ての操作')
+ON DUPLICATE KEY UPDATE name=VALUES(name);
+
+INSERT INTO permissions (name, description) VALUES
+    ('posts.manage', '他人の投稿の編集・公開・削除'),
+    ('posts.moderate', '他人の投稿のアーカイブ・削除'),
+    ('comments.moderate', 'コメントのモデレーション'),
+    ('users.manage', 'ユーザーの作成・他人のアカウント操作'),
+    ('roles.manage', 'ロールの付与・剥奪')
+ON DUPLICATE KEY UPDATE name=VALUES(name);
+
+INSERT IGNORE INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r
+INNER JOIN permissions p ON p.name IN ('posts.moderate', 'comments.moderate')
+WHERE r.name = 'moderator';
+
+INSERT IGNORE INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin';
+
+INSERT IGNORE INTO user_roles (user_id, role_id)
+SELECT 1, id FROM roles WHERE name = 'admin';  -- さくらは管理者
+
+INSERT IGNORE INTO user_roles (user_id, role_id)
+SELECT 2, id FROM roles WHERE name = 'moderator';  -- たけしはモデレーター
+
 -- カテゴリーデータ
 INSERT INTO categories (name, slug, description, parent_id, display_order) VALUES
     ('テクノロジー', 'technology', 'テクノロジーとイノベーションに関するすべて', NULL, 1),
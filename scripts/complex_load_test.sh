@@ -4,6 +4,8 @@
 # Tests both simple user API and complex JOIN queries for posts
 
 API_URL="http://localhost:8080"
+# 書き込み操作（POST/PUT /users）は管理者のアクセストークンが必要（POST /auth/login で取得）
+API_TOKEN="${API_TOKEN:-}"
 COUNTER=1
 
 echo "Starting comprehensive load test against $API_URL"
@@ -47,8 +49,14 @@ while true; do
         echo "[$COUNTER] GET /posts/featured (JOIN: posts+users+profiles+categories)"
         curl -s 'http://localhost:8080/posts/featured?limit=3' > /dev/null && echo "✓ Success" || echo "✗ Failed"
         
+    elif [ $RAND -lt 65 ]; then
+        # 10% GET /users/:id/feed (home timeline: FEED_MODE=read/write で比較)
+        USER_ID=$((RANDOM % 4 + 1))
+        echo "[$COUNTER] GET /users/$USER_ID/feed (JOIN: user_follows+posts+users+categories / Redis timeline)"
+        curl -s "$API_URL/users/$USER_ID/feed?pageSize=10" > /dev/null && echo "✓ Success" || echo "✗ Failed"
+        
     elif [ $RAND -lt 75 ]; then
-        # 20% GET /users (simple query)
+        # 10% GET /users (simple query)
         echo "[$COUNTER] GET /users (Simple query)"
         curl -s "$API_URL/users" > /dev/null && echo "✓ Success" || echo "✗ Failed"
         
@@ -62,11 +70,12 @@ while true; do
         # 10% POST /users (write operation)
         NAME="LoadTest_$COUNTER"
         EMAIL="test_${COUNTER}_$(date +%s)@example.com"
-        AGE=$((RANDOM % 40 + 20))
+        BIRTH_YEAR=$((RANDOM % 40 + 1965))
         echo "[$COUNTER] POST /users (Write operation)"
         curl -s -X POST "$API_URL/users" \
+            -H "Authorization: Bearer $API_TOKEN" \
             -H "Content-Type: application/json" \
-            -d "{\"name\":\"$NAME\",\"email\":\"$EMAIL\",\"age\":$AGE}" > /dev/null && echo "✓ Success" || echo "✗ Failed"
+            -d "{\"username\":\"$NAME\",\"email\":\"$EMAIL\",\"birthDate\":\"$BIRTH_YEAR-01-01\"}" > /dev/null && echo "✓ Success" || echo "✗ Failed"
     fi
     
     echo "--------"
@@ -4,6 +4,8 @@
 # Sends requests to the API at ~1 request per second
 
 API_URL="http://localhost:8080"
+# 書き込み操作（POST/PUT /users）は管理者のアクセストークンが必要（POST /auth/login で取得）
+API_TOKEN="${API_TOKEN:-}"
 COUNTER=1
 
 echo "Starting load test against $API_URL"
@@ -32,22 +34,24 @@ while true; do
         # 15% POST new user
         RANDOM_NAME="User_$(date +%s)"
         RANDOM_EMAIL="user_$(date +%s)@example.com"
-        RANDOM_AGE=$((RANDOM % 50 + 20))
+        RANDOM_BIRTH_YEAR=$((RANDOM % 50 + 1955))
         echo "[Request #$COUNTER] POST /users"
         curl -s -X POST "$API_URL/users" \
+            -H "Authorization: Bearer $API_TOKEN" \
             -H "Content-Type: application/json" \
-            -d "{\"name\":\"$RANDOM_NAME\",\"email\":\"$RANDOM_EMAIL\",\"age\":$RANDOM_AGE}" | jq -c '.' || echo "Failed"
+            -d "{\"username\":\"$RANDOM_NAME\",\"email\":\"$RANDOM_EMAIL\",\"birthDate\":\"$RANDOM_BIRTH_YEAR-01-01\"}" | jq -c '.' || echo "Failed"
     
     else
         # 5% PUT update user
         USER_ID=$((RANDOM % 3 + 1))
         RANDOM_NAME="Updated_User_$(date +%s)"
         RANDOM_EMAIL="updated_$(date +%s)@example.com"
-        RANDOM_AGE=$((RANDOM % 50 + 20))
+        RANDOM_BIRTH_YEAR=$((RANDOM % 50 + 1955))
         echo "[Request #$COUNTER] PUT /users/$USER_ID"
         curl -s -X PUT "$API_URL/users/$USER_ID" \
+            -H "Authorization: Bearer $API_TOKEN" \
             -H "Content-Type: application/json" \
-            -d "{\"name\":\"$RANDOM_NAME\",\"email\":\"$RANDOM_EMAIL\",\"age\":$RANDOM_AGE}" | jq -c '.' || echo "Failed"
+            -d "{\"username\":\"$RANDOM_NAME\",\"email\":\"$RANDOM_EMAIL\",\"birthDate\":\"$RANDOM_BIRTH_YEAR-01-01\"}" | jq -c '.' || echo "Failed"
     fi
     
     echo "----------------------------------------"